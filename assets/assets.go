@@ -0,0 +1,10 @@
+// Package assets embeds the files shared by the front ends.
+package assets
+
+import _ "embed"
+
+// NoImage is the PNG the TUI and the web front end show in place of
+// missing artwork.
+//
+//go:embed no-image.png
+var NoImage []byte
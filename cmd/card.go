@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/card"
+	"github.com/sangnt1552314/digimontex/internal/export"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+// runCard renders printable cards for a collection or a search result.
+func runCard(args []string) error {
+	flags := flag.NewFlagSet("card", flag.ExitOnError)
+	ids := flags.String("ids", "", "comma separated IDs or exact names of the Digimon to print")
+	search := flags.String("search", "", "print the Digimon whose name contains this")
+	limit := flags.Int("limit", 18, "most Digimon printed from --search")
+	out := flags.String("out", "cards.pdf", "output file: .pdf for a printable sheet, .png for one image per card")
+	perPage := flags.Int("per-page", card.DefaultPerPage, "cards per PDF page")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API")
+	logOpts := addLogFlags(flags)
+	flags.Parse(args)
+	if *limit < 1 {
+		return fmt.Errorf("invalid --limit %d, want at least 1", *limit)
+	}
+
+	logCloser, err := logOpts.setup()
+	if err != nil {
+		return err
+	}
+	defer logCloser.Close()
+
+	client := services.NewClient(*apiURL, nil)
+	var selected []*models.DigimonDetail
+	switch {
+	case *ids != "":
+		selected, err = cardsByKey(client, *ids)
+	case *search != "":
+		selected, err = cardsBySearch(client, *search, *limit)
+	default:
+		return errors.New("card needs --ids or --search")
+	}
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return errors.New("no Digimon matched")
+	}
+
+	cards := make([]image.Image, len(selected))
+	for i, digimon := range selected {
+		cards[i] = card.Render(digimon, client)
+	}
+
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".pdf":
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer file.Close()
+		if err := card.WritePDF(file, cards, *perPage); err != nil {
+			return err
+		}
+		fmt.Printf("%d cards written to %s\n", len(cards), *out)
+		return file.Close()
+	case ".png":
+		for i, digimon := range selected {
+			path := *out
+			if len(selected) > 1 {
+				path = fmt.Sprintf("%s-%d-%s.png", strings.TrimSuffix(*out, filepath.Ext(*out)), digimon.ID, export.Slug(digimon.Name))
+			}
+			if err := writeCardPNG(path, cards[i]); err != nil {
+				return err
+			}
+			fmt.Println(path)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported output %s, want .pdf or .png", *out)
+}
+
+func writeCardPNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+	if err := card.WritePNG(file, img); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// cardsByKey fetches a comma separated list of IDs and names.
+func cardsByKey(client *services.Client, keys string) ([]*models.DigimonDetail, error) {
+	var selected []*models.DigimonDetail
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		var (
+			digimon *models.DigimonDetail
+			err     error
+		)
+		if id, convErr := strconv.Atoi(key); convErr == nil {
+			digimon, err = client.GetDigimonByID(id)
+		} else {
+			digimon, err = client.GetDigimonByName(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		selected = append(selected, digimon)
+	}
+	return selected, nil
+}
+
+// cardsBySearch fetches up to limit Digimon matching name.
+func cardsBySearch(client *services.Client, name string, limit int) ([]*models.DigimonDetail, error) {
+	var selected []*models.DigimonDetail
+	for page := 0; len(selected) < limit; page++ {
+		resp, err := client.GetDigimonList(models.DigimonSearchQueryParams{Name: name, Page: page, PageSize: min(limit, 100)})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Content {
+			if len(selected) == limit {
+				break
+			}
+			digimon, err := client.GetDigimonByID(item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", item.Name, err)
+			}
+			selected = append(selected, digimon)
+		}
+		if resp.Pageable.NextPage == "" || len(resp.Content) == 0 {
+			break
+		}
+	}
+	return selected, nil
+}
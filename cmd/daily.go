@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/discovery"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+// dailyResult is the JSON output of the daily command.
+type dailyResult struct {
+	Date    string                `json:"date"`
+	Digimon viewmodel.DigimonView `json:"digimon"`
+}
+
+// runDaily prints the Digimon of the day, the one the TUI opens with.
+func runDaily(args []string) error {
+	flags := flag.NewFlagSet("daily", flag.ExitOnError)
+	date := flags.String("date", "", "day to pick for as YYYY-MM-DD, today by default")
+	storePath := flags.String("store", store.DefaultPath, "dataset looked up before the API")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API")
+	asJSON := flags.Bool("json", false, "print the Digimon as JSON")
+	flags.Parse(args)
+
+	day := time.Now()
+	if *date != "" {
+		var err error
+		if day, err = time.Parse(discovery.DateLayout, *date); err != nil {
+			return fmt.Errorf("invalid date %q, want YYYY-MM-DD", *date)
+		}
+	}
+
+	st, err := store.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	client := services.NewClient(*apiURL, nil)
+	maxID, err := discovery.MaxID(client.GetDigimonList, st.All())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not ask the API for the number of Digimon, using IDs up to %d: %v\n", maxID, err)
+	}
+	id := discovery.Daily(day, maxID)
+
+	var detail *models.DigimonDetail
+	if stored, ok := st.Get(id); ok {
+		detail = &stored
+	} else if detail, err = client.GetDigimonByID(id); err != nil {
+		return fmt.Errorf("failed to get Digimon %d: %w", id, err)
+	}
+
+	view := viewmodel.NewDigimonView(detail)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(dailyResult{Date: day.Format(discovery.DateLayout), Digimon: view})
+	}
+
+	fmt.Printf("Digimon of the day for %s: %s (#%d)\n", day.Format(discovery.DateLayout), view.Name, view.ID)
+	fmt.Printf("Level: %s | Attribute: %s | Type: %s\n", view.LevelsText(), view.AttributesText(), view.TypesText())
+	fmt.Printf("Fields: %s\n\n%s\n", view.FieldsText(), view.Description)
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/sangnt1552314/digimontex/internal/export"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+// runExport saves a Digimon's artwork, field icons and shareable pages.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	id := flags.Int("id", 0, "ID of the Digimon to export")
+	name := flags.String("name", "", "name of the Digimon to export")
+	dir := flags.String("dir", export.DefaultDir, "directory to export into")
+	formats := flags.String("format", "html,md", "pages to write next to the images: html, md or both, empty for none")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API")
+	logOpts := addLogFlags(flags)
+	flags.Parse(args)
+
+	logCloser, err := logOpts.setup()
+	if err != nil {
+		return err
+	}
+	defer logCloser.Close()
+
+	opts := export.Options{Dir: *dir}
+	if opts.Formats, err = export.ParseFormats(*formats); err != nil {
+		return err
+	}
+
+	client := services.NewClient(*apiURL, nil)
+	var digimon *models.DigimonDetail
+	switch {
+	case *id > 0:
+		digimon, err = client.GetDigimonByID(*id)
+	case *name != "":
+		digimon, err = client.GetDigimonByName(*name)
+	default:
+		return errors.New("export needs --id or --name")
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := export.Export(client, digimon, opts)
+	if err != nil {
+		return err
+	}
+	for _, asset := range result.Assets {
+		fmt.Printf("%s (%s) -> %s\n", asset.Name, asset.MIME, filepath.Join(result.Dir, asset.Path))
+	}
+	for _, page := range result.Pages {
+		fmt.Println(filepath.Join(result.Dir, page))
+	}
+	for _, err := range result.Errors {
+		fmt.Printf("skipped: %v\n", err)
+	}
+	return nil
+}
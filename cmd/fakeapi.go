@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/sangnt1552314/digimontex/internal/fakeapi"
+)
+
+// runFakeAPI serves recorded cassettes as a stand-in for the Digi-API.
+func runFakeAPI(args []string) error {
+	flags := flag.NewFlagSet("fakeapi", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:8081", "address to listen on")
+	dir := flags.String("cassettes", "", "directory of cassettes to serve (defaults to the bundled sample)")
+	flags.Parse(args)
+
+	var (
+		server *fakeapi.Server
+		err    error
+	)
+	if *dir == "" {
+		server, err = fakeapi.NewDefault()
+	} else {
+		cassettes, loadErr := fakeapi.LoadFS(os.DirFS(*dir), ".")
+		if loadErr != nil {
+			return loadErr
+		}
+		server, err = fakeapi.New(cassettes...)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Serving %d Digimon on http://%s/api/v1\n", server.Len(), *addr)
+	fmt.Printf("Run the TUI against it with: digimontex --api-url http://%s/api/v1\n", *addr)
+	return http.ListenAndServe(*addr, server)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/sangnt1552314/digimontex/internal/logging"
+)
+
+// logOptions are the logging flags shared by every mode
+type logOptions struct {
+	cfg       logging.Config
+	maxSizeMB int64
+}
+
+func addLogFlags(flags *flag.FlagSet) *logOptions {
+	opts := &logOptions{}
+	flags.StringVar(&opts.cfg.File, "log-file", logging.DefaultFile(), "file to write logs to, - for stderr")
+	flags.StringVar(&opts.cfg.Level, "log-level", "info", "minimum level logged: debug, info, warn or error")
+	flags.StringVar(&opts.cfg.Format, "log-format", "text", "log format: text or json")
+	flags.Int64Var(&opts.maxSizeMB, "log-max-size", logging.DefaultMaxSize>>20, "rotate the log file past this many MiB (0 disables)")
+	flags.DurationVar(&opts.cfg.MaxAge, "log-max-age", logging.DefaultMaxAge, "rotate the log file once it is this old (0 disables)")
+	flags.IntVar(&opts.cfg.MaxBackups, "log-max-backups", logging.DefaultMaxBackups, "rotated log files to keep (0 keeps all)")
+	return opts
+}
+
+// setup installs the logger; call it after the flags are parsed.
+func (o *logOptions) setup() (io.Closer, error) {
+	cfg := o.cfg
+	cfg.MaxSize = o.maxSizeMB << 20
+	return logging.Setup(cfg)
+}
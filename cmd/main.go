@@ -1,30 +1,172 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 
 	"github.com/sangnt1552314/digimontex/internal/app"
+	"github.com/sangnt1552314/digimontex/internal/discovery"
+	"github.com/sangnt1552314/digimontex/internal/export"
+	"github.com/sangnt1552314/digimontex/internal/matchup"
+	"github.com/sangnt1552314/digimontex/internal/quiz"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/services/cassette"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/team"
+	"github.com/sangnt1552314/digimontex/internal/termimg"
 )
 
+// commands are the subcommands main runs instead of the TUI.
+const commands = "card, daily, export, fakeapi, matchup, search, serve, stats, sync, team"
+
 func main() {
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "fakeapi":
+			err = runFakeAPI(os.Args[2:])
+		case "serve":
+			err = runServe(os.Args[2:])
+		case "sync":
+			err = runSync(os.Args[2:])
+		case "export":
+			err = runExport(os.Args[2:])
+		case "card":
+			err = runCard(os.Args[2:])
+		case "stats":
+			err = runStats(os.Args[2:])
+		case "search":
+			err = runSearch(os.Args[2:])
+		case "team":
+			err = runTeam(os.Args[2:])
+		case "matchup":
+			err = runMatchup(os.Args[2:])
+		case "daily":
+			err = runDaily(os.Args[2:])
+		default:
+			// Only flags start the TUI, a mistyped command should not
+			if !strings.HasPrefix(os.Args[1], "-") {
+				fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+				printUsage(os.Stderr)
+				os.Exit(2)
+			}
+			runTUI(os.Args[1:])
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	runTUI(nil)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: digimontex [flags]")
+	fmt.Fprintln(w, "       digimontex <command> [flags]")
+	fmt.Fprintf(w, "commands: %s\n", commands)
+}
+
+func runTUI(args []string) {
+	var cfg app.Config
+	flags := flag.NewFlagSet("digimontex", flag.ExitOnError)
+	flags.Usage = func() {
+		printUsage(flags.Output())
+		fmt.Fprintln(flags.Output(), "flags:")
+		flags.PrintDefaults()
+	}
+	flags.IntVar(&cfg.PrefetchWorkers, "prefetch-workers", 4, "number of concurrent detail prefetches for the visible list (0 disables)")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API, e.g. a local `digimontex fakeapi`")
+	recordDir := flags.String("record", "", "record API and image responses into cassettes in this directory")
+	replayDir := flags.String("replay", "", "answer API and image requests from the cassettes in this directory")
+	flags.StringVar(&cfg.ExportDir, "export-dir", export.DefaultDir, "directory the Export action saves assets and pages to")
+	storePath := flags.String("store", store.DefaultPath, "dataset from `digimontex sync` searched by the browsers")
+	teamsPath := flags.String("teams", team.DefaultPath, "file the team builder saves teams to")
+	flags.Int64Var(&cfg.QuizSeed, "quiz-seed", 0, "seed of the quiz questions, the same seed asks the same questions (0 for a new seed per quiz)")
+	scoresPath := flags.String("quiz-scores", quiz.DefaultScoresPath, "file the quiz high scores are kept in")
+	flags.StringVar(&cfg.Start, "default", discovery.DailyStart, "Digimon shown on startup: an ID, an exact name or \"daily\" for the Digimon of the day")
+	rulesPath := flags.String("matchup-rules", "", "file of \"Winner > Loser\" attribute rules for the vs view")
+	imageProtocol := flags.String("image-protocol", "auto", "how artwork is drawn: auto, kitty, iterm2, sixel or blocks")
+	logOpts := addLogFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected argument %q\n", flags.Arg(0))
+		flags.Usage()
+		os.Exit(2)
+	}
+	// Recording over the cassettes the user meant to replay would lose them
+	if *recordDir != "" && *replayDir != "" {
+		fmt.Fprintln(os.Stderr, "--record and --replay cannot be used together")
+		os.Exit(2)
+	}
+
 	// Setup logging
-	if err := os.MkdirAll("storage/logs", 0755); err != nil {
-		panic(fmt.Errorf("failed to create logs directory: %w", err))
+	logCloser, err := logOpts.setup()
+	if err != nil {
+		panic(err)
+	}
+	defer logCloser.Close()
+
+	cfg.ImageProtocol, err = termimg.ParseProtocol(*imageProtocol, os.Getenv)
+	if err != nil {
+		panic(err)
 	}
 
-	logFile, err := os.OpenFile("storage/logs/develop.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	// A broken store only costs the offline searches, so run without it
+	if st, err := store.Open(*storePath); err != nil {
+		slog.Warn("Failed to open the local store", "path", *storePath, "error", err)
+	} else {
+		cfg.Store = st
+	}
+	if *rulesPath != "" {
+		if cfg.Rules, err = matchup.LoadRules(*rulesPath); err != nil {
+			panic(err)
+		}
+	}
+	if scores, err := quiz.OpenHighScores(*scoresPath); err != nil {
+		slog.Warn("Failed to open the quiz high scores", "path", *scoresPath, "error", err)
+	} else {
+		cfg.QuizScores = scores
+	}
+	if book, err := team.OpenBook(*teamsPath); err != nil {
+		slog.Warn("Failed to open the saved teams", "path", *teamsPath, "error", err)
+	} else {
+		cfg.Teams = book
+	}
+
+	// Record or replay through cassettes when asked to
+	var recorder *cassette.Recorder
+	switch {
+	case *recordDir != "":
+		recorder, err = cassette.NewRecorder(*recordDir, cassette.ModeRecord, nil)
+	case *replayDir != "":
+		recorder, err = cassette.NewRecorder(*replayDir, cassette.ModeReplay, nil)
+	}
 	if err != nil {
 		panic(err)
 	}
-	defer logFile.Close()
-	log.SetOutput(logFile)
+
+	httpClient := http.DefaultClient
+	if recorder != nil {
+		httpClient = &http.Client{Transport: recorder}
+	}
 
 	// Initialize the application
-	application := app.NewApp()
+	application := app.NewApp(nil, services.NewClient(*apiURL, httpClient), cfg)
 
 	if err := application.Run(); err != nil {
 		panic(err)
 	}
+
+	if *recordDir != "" {
+		if err := recorder.Save(); err != nil {
+			panic(err)
+		}
+	}
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/matchup"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+// runMatchup compares two Digimon or teams by attribute.
+func runMatchup(args []string) error {
+	flags := flag.NewFlagSet("matchup", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: digimontex matchup [flags] <a> <b>")
+		fmt.Fprintln(flags.Output(), "each side is a Digimon name or ID, a team code, a saved team name or a team JSON file")
+		flags.PrintDefaults()
+	}
+	rulesPath := flags.String("rules", "", "file of \"Winner > Loser\" attribute rules, empty for the Vaccine > Virus > Data triangle")
+	storePath := flags.String("store", store.DefaultPath, "dataset looked up before the API")
+	teamsPath := flags.String("teams", team.DefaultPath, "file of saved teams")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API for Digimon missing from the store")
+	asJSON := flags.Bool("json", false, "print the matchup matrix as JSON")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("matchup needs two sides")
+	}
+
+	rules := matchup.DefaultRules
+	if *rulesPath != "" {
+		var err error
+		if rules, err = matchup.LoadRules(*rulesPath); err != nil {
+			return err
+		}
+	}
+	st, err := store.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	book, err := team.OpenBook(*teamsPath)
+	if err != nil {
+		return err
+	}
+
+	client := services.NewClient(*apiURL, nil)
+	resolver := matchup.Resolver{
+		ByID: func(id int) (models.DigimonDetail, error) {
+			if detail, ok := st.Get(id); ok {
+				return detail, nil
+			}
+			detail, err := client.GetDigimonByID(id)
+			if err != nil {
+				return models.DigimonDetail{}, err
+			}
+			return *detail, nil
+		},
+		ByName: func(name string) (models.DigimonDetail, error) {
+			for _, detail := range st.All() {
+				if strings.EqualFold(detail.Name, name) {
+					return detail, nil
+				}
+			}
+			detail, err := client.GetDigimonByName(name)
+			if err != nil {
+				return models.DigimonDetail{}, err
+			}
+			return *detail, nil
+		},
+		Teams: book,
+	}
+
+	lineupA, err := resolver.Resolve(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	lineupB, err := resolver.Resolve(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	m := matchup.NewMatrix(rules, lineupA.Sides, lineupB.Sides)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	}
+
+	// Two single Digimon get the pair by pair explanation, teams the matrix
+	if !lineupA.Team && !lineupB.Team {
+		fmt.Print(m.Cells[0][0].Text())
+		return nil
+	}
+	fmt.Printf("%s vs %s\n\n", lineupA.Name, lineupB.Name)
+	fmt.Print(m.Text())
+	return nil
+}
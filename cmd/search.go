@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sangnt1552314/digimontex/internal/releasedate"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/timeline"
+)
+
+// searchResult is one line of the search output.
+type searchResult struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Released string   `json:"released,omitempty"`
+	Levels   []string `json:"levels,omitempty"`
+}
+
+// runSearch lists the synced Digimon by name and release date range.
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	storePath := flags.String("store", store.DefaultPath, "dataset written by sync")
+	name := flags.String("name", "", "only Digimon whose name contains this")
+	released := flags.String("released", "", "release date range, e.g. 1997..1999, 2000.. or ..1998-06")
+	sortBy := flags.String("sort", "id", "order of the results: id, name or release")
+	desc := flags.Bool("desc", false, "reverse the order, Digimon without a release date stay last")
+	limit := flags.Int("limit", 0, "print at most this many Digimon (0 prints all)")
+	asJSON := flags.Bool("json", false, "print the results as JSON")
+	flags.Parse(args)
+
+	r, err := releasedate.ParseRange(*released)
+	if err != nil {
+		return err
+	}
+	order, err := timeline.ParseOrder(*sortBy)
+	if err != nil {
+		return err
+	}
+
+	st, err := store.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	if st.Len() == 0 {
+		return fmt.Errorf("no Digimon stored in %s, run `digimontex sync` first", st.Path())
+	}
+
+	entries := timeline.Search(st.All(), timeline.Query{Name: *name, Range: r, Order: order, Descending: *desc})
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[:*limit]
+	}
+
+	results := make([]searchResult, 0, len(entries))
+	for _, entry := range entries {
+		result := searchResult{ID: entry.Digimon.ID, Name: entry.Digimon.Name}
+		if !entry.Released.IsZero() {
+			result.Released = entry.Released.String()
+		}
+		for _, level := range entry.Digimon.Levels {
+			result.Levels = append(result.Levels, level.Level)
+		}
+		results = append(results, result)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tName\tReleased\tLevels")
+	for _, result := range results {
+		released := result.Released
+		if released == "" {
+			released = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", result.ID, result.Name, released, strings.Join(result.Levels, ", "))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/sangnt1552314/digimontex/internal/server"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+// runServe exposes the Digi-API through a caching proxy.
+func runServe(args []string) error {
+	var cfg server.Config
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the upstream Digi-API")
+	flags.IntVar(&cfg.CacheSize, "cache-size", server.DefaultCacheSize, "number of Digimon details kept in memory")
+	flags.IntVar(&cfg.ListCacheSize, "list-cache-size", server.DefaultListCacheSize, "number of list pages kept in memory")
+	flags.DurationVar(&cfg.ListTTL, "list-ttl", server.DefaultListTTL, "how long list pages are served from the cache")
+	flags.DurationVar(&cfg.MaxAge, "max-age", server.DefaultMaxAge, "Cache-Control max-age sent to clients")
+	retries := flags.Int("retries", services.DefaultRetries, "retries for failed upstream requests")
+	flags.BoolVar(&cfg.Pprof, "pprof", false, "serve /debug/pprof")
+	logOpts := addLogFlags(flags)
+	flags.Parse(args)
+
+	logCloser, err := logOpts.setup()
+	if err != nil {
+		return err
+	}
+	defer logCloser.Close()
+
+	client := services.NewClient(*apiURL, nil).SetRetries(*retries, services.DefaultRetryBackoff)
+	handler := server.New(client, cfg)
+
+	fmt.Printf("Proxying %s on http://%s (try /digimon, /digimon/{id|name}, /healthz, /metrics)\n", *apiURL, *addr)
+	return http.ListenAndServe(*addr, handler)
+}
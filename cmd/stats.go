@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sangnt1552314/digimontex/internal/stats"
+	"github.com/sangnt1552314/digimontex/internal/store"
+)
+
+// runStats prints aggregate statistics over the synced dataset.
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	storePath := flags.String("store", store.DefaultPath, "dataset written by sync")
+	asJSON := flags.Bool("json", false, "print the statistics as JSON")
+	top := flags.Int("top", stats.DefaultTop, "Digimon listed for the most evolutions and skills")
+	width := flags.Int("width", 60, "width of the text bar charts")
+	flags.Parse(args)
+
+	st, err := store.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	if st.Len() == 0 {
+		return fmt.Errorf("no Digimon stored in %s, run `digimontex sync` first", st.Path())
+	}
+
+	result := stats.Compute(st.All(), *top)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	return result.WriteText(os.Stdout, *width)
+}
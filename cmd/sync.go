@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/store"
+)
+
+// runSync downloads every Digimon detail into the local store.
+func runSync(args []string) error {
+	var opts store.SyncOptions
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API")
+	storePath := flags.String("store", store.DefaultPath, "file the dataset is saved to")
+	flags.IntVar(&opts.Workers, "workers", 4, "number of concurrent detail requests")
+	flags.BoolVar(&opts.Refresh, "refresh", false, "fetch Digimon that are already stored again")
+	retries := flags.Int("retries", services.DefaultRetries, "retries for failed API requests")
+	metricsAddr := flags.String("metrics-addr", "", "serve /metrics on this address while syncing, e.g. 127.0.0.1:9090")
+	withPprof := flags.Bool("pprof", false, "also serve /debug/pprof on --metrics-addr")
+	logOpts := addLogFlags(flags)
+	flags.Parse(args)
+
+	logCloser, err := logOpts.setup()
+	if err != nil {
+		return err
+	}
+	defer logCloser.Close()
+
+	st, err := store.Open(*storePath)
+	if err != nil {
+		return err
+	}
+
+	if *metricsAddr != "" {
+		mux := http.NewServeMux()
+		metrics.Mount(mux, *withPprof)
+		go func() {
+			if err := http.ListenAndServe(*metricsAddr, mux); err != nil {
+				slog.Error("Metrics server stopped", "error", err)
+			}
+		}()
+		fmt.Printf("Metrics on http://%s/metrics\n", *metricsAddr)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts.Progress = func(done, total, failed int) {
+		fmt.Printf("\rSynced %d/%d (%d failed)", done, total, failed)
+	}
+
+	client := services.NewClient(*apiURL, nil).SetRetries(*retries, services.DefaultRetryBackoff)
+	result, err := store.Sync(ctx, client, st, opts)
+	fmt.Println()
+	fmt.Printf("%d Digimon: %d fetched, %d already stored, %d failed; saved to %s\n",
+		result.Total, result.Fetched, result.Skipped, result.Failed, st.Path())
+	return err
+}
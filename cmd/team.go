@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+// runTeam analyses, converts and saves teams built in the TUI or given as
+// Digimon IDs.
+func runTeam(args []string) error {
+	flags := flag.NewFlagSet("team", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: digimontex team [flags] [code | file.json | saved team name]")
+		flags.PrintDefaults()
+	}
+	ids := flags.String("ids", "", "comma-separated Digimon IDs to build the team from")
+	name := flags.String("name", "", "name of the team, replaces the imported one")
+	storePath := flags.String("store", store.DefaultPath, "dataset looked up before the API")
+	teamsPath := flags.String("teams", team.DefaultPath, "file of saved teams")
+	apiURL := flags.String("api-url", services.DefaultBaseURL, "base URL of the Digi-API for Digimon missing from the store")
+	list := flags.Bool("list", false, "list the saved teams with their codes")
+	save := flags.Bool("save", false, "save the team to the teams file under its name")
+	asJSON := flags.Bool("json", false, "print the team as JSON instead of the analysis")
+	codeOnly := flags.Bool("code", false, "print only the team code")
+	flags.Parse(args)
+
+	book, err := team.OpenBook(*teamsPath)
+	if err != nil {
+		return err
+	}
+	if *list {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, saved := range book.All() {
+			fmt.Fprintf(w, "%s\t%d Digimon\t%s\n", saved.Name, len(saved.Members), team.Encode(saved))
+		}
+		return w.Flush()
+	}
+
+	var t team.Team
+	switch {
+	case *ids != "":
+		for _, field := range strings.Split(*ids, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return fmt.Errorf("invalid Digimon ID %q", field)
+			}
+			if err := t.Add(team.Member{ID: id}); err != nil {
+				return err
+			}
+		}
+	case flags.NArg() == 1:
+		var ok bool
+		if t, ok = book.Get(flags.Arg(0)); !ok {
+			if t, err = team.Import(flags.Arg(0)); err != nil {
+				return err
+			}
+		}
+	default:
+		flags.Usage()
+		return errors.New("team needs --ids, a code, a JSON file or a saved team name")
+	}
+	if *name != "" {
+		t.Name = *name
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	st, err := store.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	client := services.NewClient(*apiURL, nil)
+	resolveErr := t.Resolve(func(id int) (models.DigimonDetail, error) {
+		if detail, ok := st.Get(id); ok {
+			return detail, nil
+		}
+		detail, err := client.GetDigimonByID(id)
+		if err != nil {
+			return models.DigimonDetail{}, err
+		}
+		return *detail, nil
+	})
+	if resolveErr != nil {
+		fmt.Fprintln(os.Stderr, resolveErr)
+	}
+
+	if *save {
+		if err := book.Put(t); err != nil {
+			return err
+		}
+		if err := book.Save(); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "saved %s to %s\n", t.Name, book.Path())
+	}
+
+	switch {
+	case *codeOnly:
+		fmt.Println(team.Encode(t))
+		return nil
+	case *asJSON:
+		return team.WriteJSON(os.Stdout, t)
+	}
+
+	if t.Name != "" {
+		fmt.Println(t.Name)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tID\tName\tLevels\tAttributes\tFields")
+	for i, member := range t.Members {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", i+1, member.ID, member.Name,
+			strings.Join(member.Levels, ", "), strings.Join(member.Attributes, ", "), strings.Join(member.Fields, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Print(team.Analyze(t).Text())
+	fmt.Printf("\nCode: %s\n", team.Encode(t))
+	return nil
+}
@@ -4,7 +4,9 @@ go 1.24.2
 
 require (
 	github.com/gdamore/tcell/v2 v2.7.1
+	github.com/graph-gophers/graphql-go v1.9.0
 	github.com/rivo/tview v0.0.0-20250501113434-0c592cd31026
+	golang.org/x/image v0.30.0
 )
 
 require (
@@ -14,5 +16,5 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/term v0.17.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
 )
@@ -0,0 +1,957 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/discovery"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/quiz"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+const waitTimeout = 3 * time.Second
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeService serves a generated set of Digimon from memory.
+type fakeService struct {
+	mutex      sync.Mutex
+	digimon    []models.DigimonDetail
+	listErrors int
+	detailErrs int
+	detailGate chan struct{}
+	// listGate holds list requests back like detailGate, listPages records
+	// the page of every list request
+	listGate  chan struct{}
+	listPages []int
+}
+
+func newFakeService(count int) *fakeService {
+	service := &fakeService{}
+	for id := 1; id <= count; id++ {
+		detail := models.DigimonDetail{ID: id, Name: fmt.Sprintf("Mon%03d", id), ReleaseDate: "1999"}
+		detail.Levels = append(detail.Levels, struct {
+			ID    int    `json:"id"`
+			Level string `json:"level"`
+		}{ID: 1, Level: "Child"})
+		service.digimon = append(service.digimon, detail)
+	}
+	// The app opens Greymon on startup
+	service.digimon = append(service.digimon, models.DigimonDetail{ID: 1000, Name: "Greymon"})
+	return service
+}
+
+func (s *fakeService) GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+	s.mutex.Lock()
+	s.listPages = append(s.listPages, params.Page)
+	gate := s.listGate
+	s.mutex.Unlock()
+
+	if gate != nil {
+		<-gate
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.listErrors > 0 {
+		s.listErrors--
+		return nil, errors.New("list unavailable")
+	}
+
+	var matches []models.Digimon
+	for _, detail := range s.digimon {
+		if detail.ID >= 1000 || !strings.Contains(strings.ToLower(detail.Name), strings.ToLower(params.Name)) {
+			continue
+		}
+		if !(discovery.Filter{Level: params.Level, Attribute: params.Attribute}).Match(detail) {
+			continue
+		}
+		matches = append(matches, models.Digimon{ID: detail.ID, Name: detail.Name})
+	}
+
+	resp := &models.DigimonResponse{}
+	start := min(params.Page*params.PageSize, len(matches))
+	end := min(start+params.PageSize, len(matches))
+	resp.Content = matches[start:end]
+	resp.Pageable.CurrentPage = params.Page
+	resp.Pageable.TotalElements = len(matches)
+	resp.Pageable.TotalPages = (len(matches) + params.PageSize - 1) / params.PageSize
+	resp.Pageable.ElementsOnPage = len(resp.Content)
+	if params.Page > 0 {
+		resp.Pageable.PreviousPage = "previous"
+	}
+	if end < len(matches) {
+		resp.Pageable.NextPage = "next"
+	}
+	return resp, nil
+}
+
+func (s *fakeService) GetDigimonByName(name string) (*models.DigimonDetail, error) {
+	return s.findDetail(func(d models.DigimonDetail) bool { return d.Name == name })
+}
+
+func (s *fakeService) GetDigimonByID(id int) (*models.DigimonDetail, error) {
+	return s.findDetail(func(d models.DigimonDetail) bool { return d.ID == id })
+}
+
+func (s *fakeService) GetImageByURL(imageUrl string) (image.Image, error) {
+	return nil, errors.New("no images")
+}
+
+func (s *fakeService) GetImageDataByURL(imageUrl string) ([]byte, string, error) {
+	return nil, "", errors.New("no images")
+}
+
+func (s *fakeService) GetFieldList() ([]models.Field, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var fields []models.Field
+	seen := make(map[int]bool)
+	for _, detail := range s.digimon {
+		for _, field := range detail.Fields {
+			if !seen[field.ID] {
+				seen[field.ID] = true
+				fields = append(fields, models.Field{ID: field.ID, Name: field.Field})
+			}
+		}
+	}
+	return fields, nil
+}
+
+func (s *fakeService) findDetail(match func(models.DigimonDetail) bool) (*models.DigimonDetail, error) {
+	s.mutex.Lock()
+	gate := s.detailGate
+	s.mutex.Unlock()
+
+	if gate != nil {
+		<-gate
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.detailErrs > 0 {
+		s.detailErrs--
+		return nil, errors.New("detail unavailable")
+	}
+	for _, detail := range s.digimon {
+		if match(detail) {
+			return &detail, nil
+		}
+	}
+	return nil, services.ErrNotFound
+}
+
+type testApp struct {
+	*App
+	t         *testing.T
+	screen    tcell.SimulationScreen
+	lastClick time.Time
+}
+
+func startTestApp(t *testing.T, service services.Service) *testApp {
+	t.Helper()
+	return startTestAppWithConfig(t, service, Config{})
+}
+
+func startTestAppWithConfig(t *testing.T, service services.Service, cfg Config) *testApp {
+	t.Helper()
+
+	// The Digimon of the day changes with the date, tests start on Greymon
+	if cfg.Start == "" {
+		cfg.Start = "Greymon"
+	}
+	screen := tcell.NewSimulationScreen("UTF-8")
+	app := NewApp(screen, service, cfg)
+	screen.SetSize(200, 40)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run()
+	}()
+	t.Cleanup(func() {
+		app.Stop()
+		if err := <-done; err != nil {
+			t.Errorf("Run() returned error: %v", err)
+		}
+	})
+
+	return &testApp{App: app, t: t, screen: screen}
+}
+
+// contents returns the rendered screen, one string per row. The screen is
+// read from the event loop so it never races with a draw.
+func (ta *testApp) contents() []string {
+	rows := make(chan []string, 1)
+	ta.QueueUpdate(func() {
+		rows <- ta.screenRows()
+	})
+	return <-rows
+}
+
+func (ta *testApp) screenRows() []string {
+	cells, width, height := ta.screen.GetContents()
+	rows := make([]string, height)
+	for y := range height {
+		var row strings.Builder
+		for x := range width {
+			if runes := cells[y*width+x].Runes; len(runes) > 0 {
+				row.WriteRune(runes[0])
+			} else {
+				row.WriteRune(' ')
+			}
+		}
+		rows[y] = row.String()
+	}
+	return rows
+}
+
+// find returns the screen position of text, or ok=false if it is not shown.
+func (ta *testApp) find(text string) (x, y int, ok bool) {
+	for row, line := range ta.contents() {
+		if idx := strings.Index(line, text); idx >= 0 {
+			return len([]rune(line[:idx])), row, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (ta *testApp) waitFor(text string) {
+	ta.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		if _, _, ok := ta.find(text); ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ta.t.Fatalf("timed out waiting for %q, screen:\n%s", text, strings.Join(ta.contents(), "\n"))
+}
+
+func (ta *testApp) waitForGone(text string) {
+	ta.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		if _, _, ok := ta.find(text); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ta.t.Fatalf("timed out waiting for %q to disappear, screen:\n%s", text, strings.Join(ta.contents(), "\n"))
+}
+
+// click presses and releases the left mouse button on the given text.
+func (ta *testApp) click(text string) {
+	ta.t.Helper()
+
+	ta.waitFor(text)
+	x, y, _ := ta.find(text)
+
+	// Clicks closer together than this are reported as double clicks
+	time.Sleep(time.Until(ta.lastClick.Add(tview.DoubleClickInterval + 100*time.Millisecond)))
+	ta.lastClick = time.Now()
+
+	ta.screen.InjectMouse(x, y, tcell.Button1, tcell.ModNone)
+	ta.screen.InjectMouse(x, y, tcell.ButtonNone, tcell.ModNone)
+}
+
+func (ta *testApp) typeText(text string) {
+	for _, r := range text {
+		ta.screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+}
+
+func (ta *testApp) press(key tcell.Key) {
+	ta.screen.InjectKey(key, 0, tcell.ModNone)
+}
+
+// focused returns the focused primitive, read from the event loop.
+func (ta *testApp) focused() tview.Primitive {
+	focused := make(chan tview.Primitive, 1)
+	ta.QueueUpdate(func() {
+		focused <- ta.GetFocus()
+	})
+	return <-focused
+}
+
+// waitForFocus waits until p has the focus.
+func (ta *testApp) waitForFocus(p tview.Primitive) {
+	ta.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		if ta.focused() == p {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ta.t.Fatalf("timed out waiting for the focus on %T", p)
+}
+
+// waitForPage waits until name is the front page.
+func (ta *testApp) waitForPage(name string) {
+	ta.t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		front := make(chan string, 1)
+		ta.QueueUpdate(func() {
+			name, _ := ta.pages.GetFrontPage()
+			front <- name
+		})
+		if <-front == name {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ta.t.Fatalf("timed out waiting for the %s page", name)
+}
+
+func TestListShowsFirstPage(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+
+	ta.waitFor("Mon001")
+	ta.waitFor("Page 1 / 3 (25 results)")
+	ta.waitFor("Name: Greymon")
+}
+
+func TestPagingButtons(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+	ta.waitFor("Mon001")
+
+	ta.click(">>")
+	ta.waitFor("Mon011")
+	ta.waitFor("Page 2 / 3")
+
+	ta.click(">|")
+	ta.waitFor("Mon021")
+	ta.waitFor("Page 3 / 3")
+
+	ta.click("|<")
+	ta.waitFor("Mon001")
+	ta.waitFor("Page 1 / 3")
+}
+
+func TestGoToPageInput(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+	ta.waitFor("Mon001")
+
+	ta.click("Go to:")
+	ta.typeText("3")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Mon025")
+	ta.waitFor("Page 3 / 3")
+}
+
+func TestInfiniteListWaitsForPrefetch(t *testing.T) {
+	service := newFakeService(50)
+	ta := startTestApp(t, service)
+	ta.waitFor("Mon001")
+
+	gate := make(chan struct{})
+	service.mutex.Lock()
+	service.listGate = gate
+	service.listPages = nil
+	service.mutex.Unlock()
+
+	// The first page loads, the prefetch of the second is held back
+	ta.click("Infinite list")
+	gate <- struct{}{}
+	ta.waitFor("Paged list")
+
+	// Scrolling onto the second page waits for the running prefetch
+	ta.click("Mon006")
+	for range 3 {
+		ta.press(tcell.KeyDown)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(gate)
+	ta.waitFor("Mon011")
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	requests := 0
+	for _, page := range service.listPages {
+		if page == 1 {
+			requests++
+		}
+	}
+	if requests != 1 {
+		t.Errorf("page 1 was requested %d times, want once: %v", requests, service.listPages)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+	ta.waitFor("Mon001")
+
+	ta.click("Search:")
+	ta.typeText("Mon02")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Mon020")
+	ta.waitFor("Page 1 / 1 (6 results)")
+	ta.waitForGone("Mon001")
+}
+
+func TestShortcutsLeaveInputFieldsAlone(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+	ta.waitFor("Name: Greymon")
+
+	// Ctrl+A moves to the start of the input instead of adding to the team
+	ta.click("Search:")
+	ta.typeText("02")
+	ta.press(tcell.KeyCtrlA)
+	ta.typeText("Mon")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Page 1 / 1 (6 results)")
+	if _, _, ok := ta.find("Added Greymon to the team"); ok {
+		t.Error("Ctrl+A in the search input added to the team")
+	}
+
+	// Esc leaves the input and the shortcuts work again
+	ta.press(tcell.KeyEscape)
+	ta.waitForFocus(ta.digimonList)
+	ta.press(tcell.KeyCtrlA)
+	ta.waitFor("Added Greymon to the team")
+}
+
+func TestDetailLoadingState(t *testing.T) {
+	service := newFakeService(25)
+	ta := startTestApp(t, service)
+	ta.waitFor("Name: Greymon")
+
+	gate := make(chan struct{})
+	service.mutex.Lock()
+	service.detailGate = gate
+	service.mutex.Unlock()
+
+	ta.click("Mon003")
+	ta.waitFor("Loading Digimon details...")
+
+	close(gate)
+	ta.waitFor("Name: Mon003")
+	ta.waitFor("Levels: Child")
+}
+
+func TestDetailErrorPanelRetry(t *testing.T) {
+	service := newFakeService(25)
+	ta := startTestApp(t, service)
+	ta.waitFor("Name: Greymon")
+
+	service.mutex.Lock()
+	service.detailErrs = 1
+	service.mutex.Unlock()
+
+	ta.click("Mon004")
+	ta.waitFor("Failed to load Digimon details:")
+	ta.waitFor("detail unavailable")
+
+	ta.click("Retry")
+	ta.waitFor("Name: Mon004")
+}
+
+func TestListErrorRetry(t *testing.T) {
+	service := newFakeService(25)
+	service.listErrors = 1
+	ta := startTestApp(t, service)
+
+	ta.waitFor("Failed to fetch digimon list")
+	ta.waitFor("list unavailable")
+
+	ta.click("Retry")
+	ta.waitFor("Mon001")
+}
+
+func TestGoToUnknownDigimon(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlG)
+	ta.waitFor("ID or name:")
+	ta.typeText("Nomon")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("404")
+}
+
+func TestNextID(t *testing.T) {
+	ta := startTestApp(t, newFakeService(25))
+	ta.waitFor("Name: Greymon")
+
+	ta.click("Mon007")
+	ta.waitFor("Name: Mon007")
+
+	ta.press(tcell.KeyCtrlN)
+	ta.waitFor("Name: Mon008")
+}
+
+func TestExportCurrentDigimon(t *testing.T) {
+	dir := t.TempDir()
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{ExportDir: dir})
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlE)
+	ta.waitFor("Exported Greymon")
+
+	for _, page := range []string{"1000-greymon.html", "1000-greymon.md"} {
+		if _, err := os.Stat(filepath.Join(dir, "1000-greymon", page)); err != nil {
+			t.Errorf("%s was not written: %v", page, err)
+		}
+	}
+}
+
+func TestSkillSearchFindsStoredDigimon(t *testing.T) {
+	var details []models.DigimonDetail
+	err := json.Unmarshal([]byte(`[
+		{"id": 1000, "name": "Greymon", "skills": [{"skill": "Mega Flame", "translation": "Nova Blast"}]},
+		{"id": 2, "name": "MetalGreymon", "skills": [{"skill": "Mega Flame", "description": "Stored fire"}]},
+		{"id": 3, "name": "Gabumon", "skills": [{"skill": "Petit Fire"}]}
+	]`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newFakeService(5)
+	service.digimon[len(service.digimon)-1] = details[0]
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "digimon.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, detail := range details[1:] {
+		st.Put(detail)
+	}
+
+	ta := startTestAppWithConfig(t, service, Config{Store: st})
+	ta.waitFor("Name: Greymon")
+	ta.waitFor("Translation")
+	ta.waitFor("Nova Blast")
+
+	// Prefetched details were never viewed, so they are not searched
+	ta.cache.Put(4, &models.DigimonDetail{ID: 4, Name: "Prefetchmon", Skills: details[0].Skills})
+
+	ta.click("Translation")
+	ta.waitFor("Translation ▲")
+
+	ta.click("Mega Flame")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Other Digimon with Mega Flame (1)")
+	ta.waitFor("Stored fire")
+	if _, _, ok := ta.find("Gabumon"); ok {
+		t.Error("Digimon without the skill is listed")
+	}
+	if _, _, ok := ta.find("Prefetchmon"); ok {
+		t.Error("prefetched Digimon that was never viewed is listed")
+	}
+
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Name: Mon002")
+}
+
+func TestSkillsTableFocus(t *testing.T) {
+	ta := startTestApp(t, newFakeService(5))
+	ta.waitFor("Name: Greymon")
+
+	for _, key := range []tcell.Key{tcell.KeyEscape, tcell.KeyTab} {
+		ta.press(tcell.KeyCtrlK)
+		ta.waitForFocus(ta.skillsTable)
+		ta.press(key)
+		ta.waitForFocus(ta.digimonList)
+	}
+
+	// Ctrl+K does nothing over another page
+	ta.press(tcell.KeyCtrlT)
+	ta.waitForPage(statsPageName)
+	ta.press(tcell.KeyCtrlK)
+	time.Sleep(100 * time.Millisecond)
+	if ta.focused() == ta.skillsTable {
+		t.Error("Ctrl+K focused the skills table behind the stats page")
+	}
+}
+
+func TestFieldBrowserFiltersMembers(t *testing.T) {
+	var details []models.DigimonDetail
+	err := json.Unmarshal([]byte(`[
+		{"id": 1000, "name": "Greymon", "levels": [{"level": "Adult"}], "attributes": [{"attribute": "Vaccine"}],
+			"fields": [{"id": 3, "field": "Virus Busters"}]},
+		{"id": 3, "name": "Agumon", "levels": [{"level": "Child"}], "attributes": [{"attribute": "Vaccine"}],
+			"fields": [{"id": 3, "field": "Virus Busters"}]},
+		{"id": 8, "name": "Gabumon", "levels": [{"level": "Child"}], "attributes": [{"attribute": "Data"}],
+			"fields": [{"id": 3, "field": "Virus Busters"}, {"id": 2, "field": "Nature Spirits"}]},
+		{"id": 9, "name": "Devimon", "levels": [{"level": "Adult"}], "attributes": [{"attribute": "Virus"}],
+			"fields": [{"id": 5, "field": "Nightmare Soldiers"}]}
+	]`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newFakeService(10)
+	service.digimon[len(service.digimon)-1] = details[0]
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "digimon.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, detail := range details[1:] {
+		st.Put(detail)
+	}
+
+	ta := startTestAppWithConfig(t, service, Config{Store: st})
+	ta.waitFor("Name: Greymon")
+
+	ta.click("Virus Busters")
+	ta.waitFor("Virus Busters (3)")
+	ta.waitFor("Nightmare Soldiers")
+	ta.waitFor("3 of 3 Digimon from local data")
+
+	// Options are Any, Adult and Child
+	ta.click("Level: Any")
+	ta.press(tcell.KeyDown)
+	ta.press(tcell.KeyDown)
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("2 of 3 Digimon from local data")
+	if _, _, ok := ta.find("Greymon"); ok {
+		t.Error("Adult Digimon is listed with the Child filter")
+	}
+
+	// Tab moves on to the attribute filter and the members, Agumon is first
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyDown)
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Name: Mon008")
+}
+
+func TestStatsPage(t *testing.T) {
+	var details []models.DigimonDetail
+	err := json.Unmarshal([]byte(`[
+		{"id": 3, "name": "Agumon", "releaseDate": "1997", "levels": [{"level": "Child"}],
+			"skills": [{"skill": "Baby Flame"}, {"skill": "Sharp Claw"}]},
+		{"id": 8, "name": "Gabumon", "releaseDate": "1997", "xAntibody": true, "levels": [{"level": "Child"}]}
+	]`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "digimon.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, detail := range details {
+		st.Put(detail)
+	}
+
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{Store: st})
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlT)
+	// Greymon comes from the session cache
+	ta.waitFor("3 Digimon, 1 with X-Antibody (33.3%)")
+	ta.waitFor("Child   2 ")
+	ta.waitFor("1. Agumon (ID 3): 2 skills")
+
+	ta.press(tcell.KeyEscape)
+	ta.waitForGone("Most skills")
+}
+
+// releaseStore returns a store with Digimon released over several years.
+func releaseStore(t *testing.T) *store.Store {
+	t.Helper()
+
+	var details []models.DigimonDetail
+	err := json.Unmarshal([]byte(`[
+		{"id": 2, "name": "Agumon", "releaseDate": "1997"},
+		{"id": 3, "name": "Gabumon", "releaseDate": "1997-03-21"},
+		{"id": 4, "name": "Patamon", "releaseDate": "1999"},
+		{"id": 5, "name": "Gatomon", "releaseDate": "2000-06"},
+		{"id": 40, "name": "Unknownmon"}
+	]`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "digimon.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, detail := range details {
+		st.Put(detail)
+	}
+	return st
+}
+
+func TestListReleaseRangeAndSort(t *testing.T) {
+	ta := startTestAppWithConfig(t, newFakeService(25), Config{Store: releaseStore(t)})
+	ta.waitFor("Mon001")
+
+	ta.click("Released:")
+	ta.typeText("1998..")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Page 1 / 1 (2 results, local data)")
+	ta.waitFor("Patamon")
+	ta.waitForGone("Mon001")
+	if _, _, ok := ta.find("Agumon"); ok {
+		t.Error("Digimon released in 1997 is listed for 1998..")
+	}
+
+	ta.click("Sort: ID")
+	ta.waitFor("Sort: Oldest")
+	ta.click("Sort: Oldest")
+	ta.waitFor("Sort: Newest")
+	_, gatomon, _ := ta.find("Gatomon")
+	_, patamon, _ := ta.find("Patamon")
+	if gatomon > patamon {
+		t.Error("newest first lists Gatomon (2000) after Patamon (1999)")
+	}
+
+	ta.click("Released:")
+	ta.typeText("x")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor(`no release date in "x"`)
+}
+
+func TestTimelinePage(t *testing.T) {
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{Store: releaseStore(t)})
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlL)
+	ta.waitFor("Release timeline")
+	ta.waitFor("1997 (2)")
+	ta.waitFor("Gabumon - 21 March 1997")
+	ta.waitFor("Unknown (2)")
+
+	// The first year is selected, its second Digimon is Gabumon (ID 3) and
+	// is loaded through the API
+	ta.press(tcell.KeyDown)
+	ta.press(tcell.KeyDown)
+	ta.press(tcell.KeyEnter)
+	ta.waitForGone("Release timeline")
+	ta.waitFor("Name: Mon003")
+}
+
+func TestTeamBuilder(t *testing.T) {
+	teams, err := team.OpenBook(filepath.Join(t.TempDir(), "teams.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{Teams: teams})
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlA)
+	ta.waitFor("Added Greymon to the team (1/6)")
+	ta.click("Mon003")
+	ta.waitFor("Name: Mon003")
+	ta.press(tcell.KeyCtrlA)
+	ta.waitFor("Added Mon003 to the team (2/6)")
+
+	ta.press(tcell.KeyCtrlB)
+	ta.waitFor("Team: 2 / 6 Digimon")
+	ta.waitFor("! 4 of 6 slots empty")
+	ta.waitFor("! No Vaccine Digimon")
+
+	// Name the team and save it
+	ta.press(tcell.KeyTab)
+	ta.typeText("Alpha")
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Saved Alpha to")
+	if saved, ok := teams.Get("alpha"); !ok || len(saved.Members) != 2 {
+		t.Errorf("saved team = %+v, %v", saved, ok)
+	}
+
+	// Clear it and import a code, its members are looked up
+	ta.click("Clear")
+	ta.waitFor("Team: 0 / 6 Digimon")
+	ta.click("Code:")
+	ta.typeText(team.Encode(team.Team{Name: "Beta", Members: []team.Member{{ID: 4}, {ID: 4}}}))
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Loaded Beta")
+	ta.waitFor("! Mon004 (ID 4) is in the team 2 times")
+
+	// The saved team is listed and loads on Enter
+	ta.click("Alpha")
+	ta.waitFor("Loaded Alpha")
+	ta.waitFor("Greymon")
+
+	ta.press(tcell.KeyEscape)
+	ta.waitForGone("Saved teams")
+}
+
+func TestVsView(t *testing.T) {
+	var details []models.DigimonDetail
+	err := json.Unmarshal([]byte(`[
+		{"id": 2, "name": "Agumon", "attributes": [{"attribute": "Vaccine"}]},
+		{"id": 3, "name": "Devimon", "attributes": [{"attribute": "Virus"}]},
+		{"id": 4, "name": "Gabumon", "attributes": [{"attribute": "Data"}]}
+	]`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "digimon.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, detail := range details {
+		st.Put(detail)
+	}
+
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{Store: st})
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlV)
+	ta.waitFor("Rules: Vaccine > Virus, Virus > Data, Data > Vaccine")
+	ta.typeText("devimon")
+	ta.press(tcell.KeyBacktab)
+	ta.press(tcell.KeyCtrlU)
+	ta.typeText("Agumon")
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Agumon vs Devimon: advantage for Agumon")
+	ta.waitFor("Vaccine beats Virus")
+
+	// A team on the B side gives a row per A Digimon and a column per member
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyCtrlU)
+	ta.typeText(team.Encode(team.Team{Name: "Rivals", Members: []team.Member{{ID: 3}, {ID: 4}}}))
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Agumon vs Rivals: neutral for Agumon (1 advantage, 0 neutral, 1 disadvantage)")
+	ta.waitFor("Agumon +1 -1")
+
+	ta.press(tcell.KeyEscape)
+	ta.waitForGone("Matchups for A")
+}
+
+func TestQuiz(t *testing.T) {
+	var details []models.DigimonDetail
+	err := json.Unmarshal([]byte(`[
+		{"id": 2, "name": "Agumon", "levels": [{"level": "Child"}], "fields": [{"field": "Virus Busters"}]},
+		{"id": 3, "name": "Greymon", "levels": [{"level": "Adult"}], "priorEvolutions": [{"digimon": "Agumon"}]},
+		{"id": 4, "name": "Gabumon", "levels": [{"level": "Child"}], "fields": [{"field": "Nature Spirits"}]},
+		{"id": 5, "name": "Devimon", "levels": [{"level": "Perfect"}], "fields": [{"field": "Nightmare Soldiers"}]}
+	]`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "digimon.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, detail := range details {
+		st.Put(detail)
+	}
+	scores, err := quiz.OpenHighScores(filepath.Join(t.TempDir(), "scores.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The seed makes the app ask the same questions as this generator, over
+	// the store and the Greymon opened on startup
+	const seed = 7
+	expected, err := quiz.NewGenerator(append(details, models.DigimonDetail{ID: 1000, Name: "Greymon"}), seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{Store: st, QuizSeed: seed, QuizScores: scores})
+	ta.waitFor("Name: Greymon")
+
+	ta.press(tcell.KeyCtrlQ)
+	ta.waitFor("No high scores yet")
+	ta.waitFor("Seed 7")
+
+	first := expected.Next()
+	ta.waitFor(first.Prompt)
+	ta.screen.InjectKey(tcell.KeyRune, rune('1'+first.Answer), tcell.ModNone)
+	ta.waitFor("Right! +10")
+	ta.waitFor("Score 10 | Streak 1 (best 1) | 1/1 right")
+
+	ta.press(tcell.KeyEnter)
+	second := expected.Next()
+	ta.waitFor(second.Prompt)
+	wrong := (second.Answer + 1) % len(second.Choices)
+	ta.screen.InjectKey(tcell.KeyRune, rune('1'+wrong), tcell.ModNone)
+	ta.waitFor("Wrong, the answer was " + second.AnswerText())
+	ta.waitFor("Score 10 | Streak 0 (best 1) | 1/2 right")
+
+	ta.press(tcell.KeyEscape)
+	ta.waitFor("Quiz over: 10 points, 1/2 right, high score #1")
+	if entries := scores.Entries(); len(entries) != 1 || entries[0].Seed != seed {
+		t.Errorf("high scores = %+v", entries)
+	}
+}
+
+func TestStartDigimon(t *testing.T) {
+	service := newFakeService(30)
+	ta := startTestAppWithConfig(t, service, Config{Start: "daily"})
+	day := time.Now()
+	id := discovery.Daily(day, 30)
+	ta.waitFor(fmt.Sprintf("Name: Mon%03d", id))
+	ta.waitFor("Digimon of the day for " + day.Format(discovery.DateLayout))
+
+	ta = startTestAppWithConfig(t, service, Config{Start: "7"})
+	ta.waitFor("Name: Mon007")
+}
+
+func TestRandomFilters(t *testing.T) {
+	service := newFakeService(6)
+	for _, i := range []int{1, 3} {
+		service.digimon[i].Levels[0].Level = "Adult"
+	}
+	ta := startTestApp(t, service)
+	ta.waitFor("Name: Greymon")
+
+	// Level: Any, Baby I, Baby II, Child, Adult
+	ta.press(tcell.KeyCtrlO)
+	ta.waitFor("Random Digimon")
+	ta.press(tcell.KeyEnter)
+	for range 4 {
+		ta.press(tcell.KeyDown)
+	}
+	ta.press(tcell.KeyEnter)
+	ta.waitFor("Level: Adult")
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyTab)
+	ta.press(tcell.KeyEnter)
+	ta.waitForGone("Random Digimon")
+
+	shown := func() string {
+		t.Helper()
+		deadline := time.Now().Add(waitTimeout)
+		for time.Now().Before(deadline) {
+			for _, name := range []string{"Mon002", "Mon004"} {
+				if _, _, ok := ta.find("Name: " + name); ok {
+					return name
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("no Adult Digimon shown, screen:\n%s", strings.Join(ta.contents(), "\n"))
+		return ""
+	}
+	first := shown()
+	ta.press(tcell.KeyCtrlR)
+	ta.waitForGone("Name: " + first)
+	if second := shown(); second == first {
+		t.Fatalf("Ctrl+R repeated %s", first)
+	}
+
+	ta.press(tcell.KeyCtrlR)
+	ta.waitFor("All 2 Adult Digimon were shown this session, starting over")
+}
@@ -1,56 +1,172 @@
 package app
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"image"
 	"image/png"
-	"log"
-	"os"
+	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/assets"
+	"github.com/sangnt1552314/digimontex/internal/discovery"
+	"github.com/sangnt1552314/digimontex/internal/matchup"
 	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/quiz"
 	"github.com/sangnt1552314/digimontex/internal/services"
 	"github.com/sangnt1552314/digimontex/internal/services/cache"
+	"github.com/sangnt1552314/digimontex/internal/services/prefetch"
+	"github.com/sangnt1552314/digimontex/internal/store"
+	"github.com/sangnt1552314/digimontex/internal/team"
+	"github.com/sangnt1552314/digimontex/internal/termimg"
+	"github.com/sangnt1552314/digimontex/internal/timeline"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
 )
 
+// Config holds the options the TUI is started with.
+type Config struct {
+	// PrefetchWorkers is the number of details fetched concurrently in the
+	// background for the visible list items, 0 disables prefetching
+	PrefetchWorkers int
+	// ImageProtocol draws the main artwork with a terminal graphics
+	// protocol, Blocks uses half-block characters
+	ImageProtocol termimg.Protocol
+	// ExportDir is where the Export action writes the current Digimon
+	ExportDir string
+	// Store is the synced dataset searched by the browsers, nil searches
+	// only the Digimon fetched this session
+	Store *store.Store
+	// Teams is where the team builder saves teams, nil disables saving
+	Teams *team.Book
+	// Rules decides attribute matchups in the vs view, nil uses the
+	// Vaccine > Virus > Data > Vaccine triangle
+	Rules *matchup.Rules
+	// QuizSeed fixes the quiz questions, 0 picks a new seed per quiz
+	QuizSeed int64
+	// QuizScores keeps the quiz high scores, nil does not save them
+	QuizScores *quiz.HighScores
+	// Start is the Digimon shown on startup: an ID, an exact name, or
+	// "daily" or "" for the Digimon of the day
+	Start string
+}
+
+// detailCacheSize holds a full page of prefetched details, the largest
+// batch the prefetcher warms, with room for the Digimon opened besides them.
+const detailCacheSize = maxPageSize + 20
+
+// maxFieldLabelWidth caps the column of field icons and labels next to the
+// artwork.
+const maxFieldLabelWidth = 22
+
 type App struct {
 	*tview.Application
+	service      services.Service
+	pages        *tview.Pages
 	digimon      *models.DigimonDetail
 	digimonBlock *tview.Flex
 	cache        *cache.DigimonCache
+	viewed       *viewedDetails
+	imageCache   *cache.ImageCache
+	prefetcher   *prefetch.Prefetcher
+	status       *statusBar
+	images       *imageRenderer
+	exportDir    string
+	store        *store.Store
+	skillsTable  *skillsTable
+	team         team.Team
+	teams        *team.Book
+	teamView     *teamView
+	// teamGen changes with the members of team, so lookups started for an
+	// earlier team are dropped
+	teamGen      int
+	rules        *matchup.Rules
+	quizSeed     int64
+	quizScores   *quiz.HighScores
+	start        discovery.Start
+	picker       *discovery.Picker
+	randomFilter discovery.Filter
+	// randomPool caches the random candidates the API listed per filter
+	randomPool   map[discovery.Filter][]int
 	loadingMutex sync.RWMutex
 	isLoading    bool
 	currentPage  int
 	pageSize     int
 	digimonList  *tview.List
 	searchTerm   string
+	listQuery    timeline.Query
 	previousPage string
 	nextPage     string
+	totalPages   int
+	totalItems   int
+	listAnchor   int
+	pageLabel    *tview.TextView
+	listGen      int
+	infinite     bool
+	scroll       *scrollWindow
+	maxDigimonID int
 }
 
-func NewApp() *App {
+// NewApp builds the TUI on top of service. A nil screen lets tview open the
+// terminal; tests pass a tcell.SimulationScreen instead.
+func NewApp(screen tcell.Screen, service services.Service, cfg Config) *App {
+	digimonCache := cache.NewDigimonCache(detailCacheSize)
+	imageCache := cache.NewImageCache(50)
 	app := &App{
 		Application:  tview.NewApplication(),
+		service:      service,
+		pages:        tview.NewPages(),
 		digimon:      &models.DigimonDetail{},
 		digimonBlock: tview.NewFlex(),
-		cache:        cache.NewDigimonCache(10),
+		cache:        digimonCache,
+		viewed:       newViewedDetails(),
+		imageCache:   imageCache,
+		prefetcher:   prefetch.NewPrefetcher(cfg.PrefetchWorkers, service, digimonCache, imageCache),
+		status:       newStatusBar(),
+		images:       newImageRenderer(cfg.ImageProtocol),
+		exportDir:    cfg.ExportDir,
+		store:        cfg.Store,
+		teams:        cfg.Teams,
+		rules:        cfg.Rules,
+		quizSeed:     cfg.QuizSeed,
+		quizScores:   cfg.QuizScores,
+		start:        discovery.ParseStart(cfg.Start),
+		picker:       discovery.NewPicker(time.Now().UnixNano()),
+		randomPool:   make(map[discovery.Filter][]int),
 		currentPage:  0,
 		pageSize:     10,
 		digimonList:  tview.NewList(),
 		searchTerm:   "",
 		previousPage: "",
 		nextPage:     "",
+		listAnchor:   -1,
+		pageLabel:    tview.NewTextView(),
+		scroll:       newScrollWindow(),
+		maxDigimonID: defaultMaxDigimonID,
 	}
 
+	if app.rules == nil {
+		app.rules = matchup.DefaultRules
+	}
+	if screen != nil {
+		app.SetScreen(screen)
+	}
 	app.EnableMouse(true)
+	app.images.covered = func() bool {
+		return !app.mainInFront()
+	}
+	app.SetAfterDrawFunc(app.images.flush)
 
 	app.setupBindings()
 
 	root := tview.NewFlex()
 	app.setupLayout(root)
 
-	app.Application.SetRoot(root, true)
+	app.pages.AddPage("main", root, true, true)
+	app.Application.SetRoot(app.pages, true)
 
 	return app
 }
@@ -61,15 +177,100 @@ func (a *App) Run() error {
 
 func (a *App) setupBindings() {
 	a.Application.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyCtrlC:
+		if event.Key() == tcell.KeyCtrlC {
 			a.Stop()
 			return nil
 		}
+		// The other shortcuts only apply to the main page, and not while
+		// typing: input fields edit with Ctrl+A, Ctrl+E, Ctrl+K and the like.
+		// A clicked input field focuses its text area.
+		if !a.mainInFront() {
+			return event
+		}
+		switch a.GetFocus().(type) {
+		case *tview.InputField, *tview.TextArea:
+			if event.Key() == tcell.KeyEscape {
+				a.SetFocus(a.digimonList)
+				return nil
+			}
+			return event
+		}
+
+		switch event.Key() {
+		case tcell.KeyCtrlG:
+			a.showGoToPrompt()
+			return nil
+		case tcell.KeyCtrlR:
+			a.loadRandomDigimon()
+			return nil
+		case tcell.KeyCtrlO:
+			a.showRandomFilters()
+			return nil
+		case tcell.KeyCtrlD:
+			a.loadDailyDigimon()
+			return nil
+		case tcell.KeyCtrlN:
+			a.loadAdjacentDigimon(1)
+			return nil
+		case tcell.KeyCtrlP:
+			a.loadAdjacentDigimon(-1)
+			return nil
+		case tcell.KeyCtrlE:
+			a.exportCurrent()
+			return nil
+		case tcell.KeyCtrlF:
+			a.showFieldBrowser(0)
+			return nil
+		case tcell.KeyCtrlT:
+			a.showStats()
+			return nil
+		case tcell.KeyCtrlL:
+			a.showTimeline()
+			return nil
+		case tcell.KeyCtrlA:
+			a.addToTeam()
+			return nil
+		case tcell.KeyCtrlB:
+			a.showTeamBuilder()
+			return nil
+		case tcell.KeyCtrlV:
+			a.showVs()
+			return nil
+		case tcell.KeyCtrlQ:
+			a.showQuiz()
+			return nil
+		case tcell.KeyCtrlK:
+			if a.skillsTable != nil {
+				a.SetFocus(a.skillsTable)
+			}
+			return nil
+		}
 		return event
 	})
 }
 
+// mainInFront reports whether no other page is shown over the main page.
+func (a *App) mainInFront() bool {
+	front, _ := a.pages.GetFrontPage()
+	return front == "main"
+}
+
+// cycleFocus moves the focus to the primitive after the focused one in
+// order, or before it when backwards, wrapping around.
+func (a *App) cycleFocus(order []tview.Primitive, backwards bool) {
+	step := 1
+	if backwards {
+		step = len(order) - 1
+	}
+	for i, primitive := range order {
+		if primitive.HasFocus() {
+			a.SetFocus(order[(i+step)%len(order)])
+			return
+		}
+	}
+	a.SetFocus(order[0])
+}
+
 func (a *App) setupLayout(root *tview.Flex) {
 	root.SetDirection(tview.FlexRow).SetBorder(false)
 
@@ -78,21 +279,108 @@ func (a *App) setupLayout(root *tview.Flex) {
 	mainContent := a.setupMainContent()
 
 	root.AddItem(mainContent, 0, 1, false)
-	root.AddItem(menu, 3, 0, false)
+	root.AddItem(menu, 4, 0, false)
 }
 
 func (a *App) setupMainMenu() tview.Primitive {
-	menuFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
+	menuFlex := tview.NewFlex().SetDirection(tview.FlexRow)
 	menuFlex.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
 	menuFlex.SetTitle("Options").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorWhite)
 
+	listModeButton := tview.NewButton("Infinite list")
+	listModeButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	listModeButton.SetSelectedFunc(func() {
+		a.toggleListMode()
+		if a.infinite {
+			listModeButton.SetLabel("Paged list")
+		} else {
+			listModeButton.SetLabel("Infinite list")
+		}
+	})
+
 	exitButton := tview.NewButton("Exit")
 	exitButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
 	exitButton.SetSelectedFunc(func() {
 		a.Application.Stop()
 	})
 
-	menuFlex.AddItem(exitButton, 9, 0, false)
+	goToButton := tview.NewButton("Go to")
+	goToButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	goToButton.SetSelectedFunc(a.showGoToPrompt)
+
+	randomButton := tview.NewButton("Random")
+	randomButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	randomButton.SetSelectedFunc(a.showRandomFilters)
+
+	todayButton := tview.NewButton("Today")
+	todayButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	todayButton.SetSelectedFunc(a.loadDailyDigimon)
+
+	previousIDButton := tview.NewButton("< ID")
+	previousIDButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	previousIDButton.SetSelectedFunc(func() {
+		a.loadAdjacentDigimon(-1)
+	})
+
+	nextIDButton := tview.NewButton("ID >")
+	nextIDButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	nextIDButton.SetSelectedFunc(func() {
+		a.loadAdjacentDigimon(1)
+	})
+
+	exportButton := tview.NewButton("Export")
+	exportButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	exportButton.SetSelectedFunc(a.exportCurrent)
+
+	fieldsButton := tview.NewButton("Fields")
+	fieldsButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	fieldsButton.SetSelectedFunc(func() {
+		a.showFieldBrowser(0)
+	})
+
+	statsButton := tview.NewButton("Stats")
+	statsButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	statsButton.SetSelectedFunc(a.showStats)
+
+	timelineButton := tview.NewButton("Timeline")
+	timelineButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	timelineButton.SetSelectedFunc(a.showTimeline)
+
+	addTeamButton := tview.NewButton("+ Team")
+	addTeamButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	addTeamButton.SetSelectedFunc(a.addToTeam)
+
+	teamButton := tview.NewButton("Team")
+	teamButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	teamButton.SetSelectedFunc(a.showTeamBuilder)
+
+	vsButton := tview.NewButton("Vs")
+	vsButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	vsButton.SetSelectedFunc(a.showVs)
+
+	quizButton := tview.NewButton("Quiz")
+	quizButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	quizButton.SetSelectedFunc(a.showQuiz)
+
+	buttonsFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
+	buttonsFlex.AddItem(exitButton, 9, 0, false)
+	buttonsFlex.AddItem(listModeButton, 15, 0, false)
+	buttonsFlex.AddItem(goToButton, 9, 0, false)
+	buttonsFlex.AddItem(randomButton, 10, 0, false)
+	buttonsFlex.AddItem(todayButton, 9, 0, false)
+	buttonsFlex.AddItem(previousIDButton, 8, 0, false)
+	buttonsFlex.AddItem(nextIDButton, 8, 0, false)
+	buttonsFlex.AddItem(exportButton, 10, 0, false)
+	buttonsFlex.AddItem(fieldsButton, 10, 0, false)
+	buttonsFlex.AddItem(statsButton, 9, 0, false)
+	buttonsFlex.AddItem(timelineButton, 12, 0, false)
+	buttonsFlex.AddItem(addTeamButton, 10, 0, false)
+	buttonsFlex.AddItem(teamButton, 8, 0, false)
+	buttonsFlex.AddItem(vsButton, 6, 0, false)
+	buttonsFlex.AddItem(quizButton, 8, 0, false)
+
+	menuFlex.AddItem(buttonsFlex, 1, 0, false)
+	menuFlex.AddItem(a.status, 1, 0, false)
 
 	return menuFlex
 }
@@ -106,24 +394,7 @@ func (a *App) setupMainContent() tview.Primitive {
 	digimonListBlock := tview.NewFlex()
 	a.setupListDigimonBlock(digimonListBlock)
 
-	go func() {
-		// Fetch default digimon detail
-		// This could be any digimon, here we use "Greymon" as an example
-		// You can change this to any other digimon name or ID as needed
-		digimonDetail, err := services.GetDigimonByName("Greymon")
-		if err != nil {
-			log.Println("Failed to fetch digimon detail:", err)
-			return
-		}
-
-		a.QueueUpdateDraw(func() {
-			a.digimon = digimonDetail
-			if digimonDetail.ID > 0 {
-				a.cache.Put(digimonDetail.ID, digimonDetail)
-			}
-			a.setupDigimonBlock(a.digimonBlock)
-		})
-	}()
+	a.loadStartDigimon()
 
 	digimonContent.AddItem(digimonListBlock, 0, 2, false)
 	digimonContent.AddItem(a.digimonBlock, 0, 8, false)
@@ -133,6 +404,7 @@ func (a *App) setupMainContent() tview.Primitive {
 
 	return mainContent
 }
+
 func (a *App) setupSearchBlock() tview.Primitive {
 	searchInput := tview.NewInputField().
 		SetFieldBackgroundColor(tcell.ColorNone).
@@ -142,27 +414,19 @@ func (a *App) setupSearchBlock() tview.Primitive {
 
 	searchInput.SetDoneFunc(func(key tcell.Key) {
 		if key == tcell.KeyEnter {
-			searchTerm := searchInput.GetText()
-			if searchTerm != "" {
-				a.searchTerm = searchTerm
-				a.currentPage = 0
-				a.buildDigimonList(a.digimonList, models.DigimonSearchQueryParams{
-					Name:     searchTerm,
-					PageSize: a.pageSize,
-					Page:     a.currentPage,
-				})
-			} else {
-				a.currentPage = 0
-				a.buildDigimonList(a.digimonList, models.DigimonSearchQueryParams{
-					PageSize: a.pageSize,
-					Page:     a.currentPage,
-				})
-				a.searchTerm = ""
-			}
+			a.searchTerm = searchInput.GetText()
+			a.loadPage(0)
 		}
 	})
 
-	return searchInput
+	rangeInput, sortButton := a.setupListFilters()
+
+	searchBlock := tview.NewFlex().
+		AddItem(searchInput, 0, 3, false).
+		AddItem(rangeInput, 0, 2, false).
+		AddItem(sortButton, 14, 0, false)
+
+	return searchBlock
 }
 
 func (a *App) setupListDigimonBlock(block *tview.Flex) {
@@ -170,42 +434,56 @@ func (a *App) setupListDigimonBlock(block *tview.Flex) {
 	block.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
 
 	navigationFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
+	firstButton := tview.NewButton("|<")
+	firstButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack))
 	leftButton := tview.NewButton("<<")
 	leftButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack))
 	rightButton := tview.NewButton(">>")
 	rightButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack))
+	lastButton := tview.NewButton(">|")
+	lastButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack))
+	firstButton.SetSelectedFunc(func() {
+		if a.currentPage > 0 {
+			a.loadPage(0)
+		}
+	})
 	leftButton.SetSelectedFunc(func() {
 		if a.previousPage != "" && a.currentPage > 0 {
-			a.currentPage--
-			a.buildDigimonList(a.digimonList, models.DigimonSearchQueryParams{
-				PageSize: a.pageSize,
-				Page:     a.currentPage,
-				Name:     a.searchTerm,
-			})
+			a.loadPage(a.currentPage - 1)
 		}
 	})
 	rightButton.SetSelectedFunc(func() {
 		if a.nextPage != "" {
-			a.currentPage++
-			a.buildDigimonList(a.digimonList, models.DigimonSearchQueryParams{
-				PageSize: a.pageSize,
-				Page:     a.currentPage,
-				Name:     a.searchTerm,
-			})
+			a.loadPage(a.currentPage + 1)
+		}
+	})
+	lastButton.SetSelectedFunc(func() {
+		if a.totalPages > 0 && a.currentPage < a.totalPages-1 {
+			a.loadPage(a.totalPages - 1)
 		}
 	})
 
+	a.pageLabel.SetTextAlign(tview.AlignCenter).SetTextColor(tcell.ColorSilver).SetWrap(true)
+
+	a.digimonList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		a.onListChanged(index)
+	})
+
 	a.buildDigimonList(a.digimonList, models.DigimonSearchQueryParams{
 		PageSize: a.pageSize,
 		Page:     a.currentPage,
 		Name:     a.searchTerm,
 	})
 
+	navigationFlex.AddItem(firstButton, 0, 1, false)
 	navigationFlex.AddItem(leftButton, 0, 1, false)
 	navigationFlex.AddItem(rightButton, 0, 1, false)
+	navigationFlex.AddItem(lastButton, 0, 1, false)
 
 	block.AddItem(a.digimonList, 0, 1, false)
+	block.AddItem(a.pageLabel, 2, 0, false)
 	block.AddItem(navigationFlex, 1, 0, false)
+	block.AddItem(a.setupPageInputs(), 2, 0, false)
 }
 
 func (a *App) buildDigimonList(list *tview.List, params models.DigimonSearchQueryParams) {
@@ -215,37 +493,72 @@ func (a *App) buildDigimonList(list *tview.List, params models.DigimonSearchQuer
 	// Show loading state
 	list.AddItem("Loading...", "", 0, nil)
 
+	// Responses for superseded requests are dropped
+	a.listGen++
+	generation := a.listGen
+
 	// Use goroutine for API call
+	fetchList := a.listSource()
+	local := a.localList()
+	a.status.requestStarted()
 	go func() {
-		digimonResponse, err := services.GetDigimonList(params)
-
-		a.previousPage = digimonResponse.Pageable.PreviousPage
-		a.nextPage = digimonResponse.Pageable.NextPage
+		digimonResponse, err := fetchList(params)
+		a.status.requestFinished(err)
 
 		a.QueueUpdateDraw(func() {
+			if generation != a.listGen {
+				return
+			}
+
 			list.Clear()
 			list.SetMainTextColor(tcell.ColorOrange)
 			list.SetSelectedTextColor(tcell.ColorBlack)
 			list.SetSelectedBackgroundColor(tcell.ColorWhite)
 
 			if err != nil {
-				log.Println("Failed to fetch digimon list:", err)
-				list.AddItem("Failed to fetch digimon list", "", 0, nil)
+				slog.Error("Failed to fetch digimon list", "page", params.Page, "error", err)
+				list.AddItem("Failed to fetch digimon list", tview.Escape(err.Error()), 0, nil)
+				list.AddItem("Retry", "", 'r', func() {
+					a.buildDigimonList(list, params)
+				})
+				return
 			}
 
-			for _, digimon := range digimonResponse.Content {
-				currentDigimon := digimon
-				list.AddItem(currentDigimon.Name, "", 0, func() {
-					a.loadDigimonDetail(currentDigimon.ID)
-				})
+			page := viewmodel.NewListView(digimonResponse)
+			a.previousPage = digimonResponse.Pageable.PreviousPage
+			a.nextPage = digimonResponse.Pageable.NextPage
+			a.totalPages = page.TotalPages
+			a.totalItems = page.TotalItems
+			if a.searchTerm == "" && !local && a.totalItems > 0 {
+				a.maxDigimonID = a.totalItems
+			}
+			a.updatePageLabel()
+
+			for _, item := range page.Items {
+				list.AddItem(item.Name, "", 0, a.digimonSelectedFunc(item.ID))
+			}
+
+			// Restore the item that was at the top before a page size change
+			if a.listAnchor >= 0 && a.listAnchor < list.GetItemCount() {
+				list.SetCurrentItem(a.listAnchor)
+				list.SetOffset(a.listAnchor, 0)
 			}
+			a.listAnchor = -1
+
+			a.prefetcher.Prefetch(prefetch.PriorityOrder(page.IDs(), list.GetCurrentItem()))
 		})
 	}()
 }
 
+func (a *App) digimonSelectedFunc(digimonID int) func() {
+	return func() {
+		a.loadDigimonDetail(digimonID)
+	}
+}
+
 func (a *App) setupDigimonBlock(block *tview.Flex) {
 	if a.digimon == nil {
-		log.Println("No digimon data available to display")
+		slog.Debug("No digimon data available to display")
 		return
 	}
 
@@ -271,55 +584,75 @@ func (a *App) setupDigimonBlock(block *tview.Flex) {
 	block.SetDirection(tview.FlexColumn)
 	block.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
 
+	view := viewmodel.NewDigimonView(a.digimon)
+
 	leftBlock := tview.NewFlex().SetDirection(tview.FlexRow)
 	rightBlock := tview.NewFlex().SetDirection(tview.FlexRow)
 
 	// Setup left block
 	imagesFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
 
-	imageFlex := tview.NewImage()
-	if image := services.GetImageByURL(a.digimon.Images[0].Href); image != nil {
+	imageFlex := newImageView(a.images)
+	if view.ImageURL == "" {
+		a.loadFallbackImage(imageFlex, imagesFlex)
+	} else if image := a.loadImage(view.ImageURL); image != nil {
 		imageFlex.SetImage(image).SetAlign(0, 0)
 		imagesFlex.AddItem(imageFlex, 0, 8, false)
 	} else {
 		a.loadFallbackImage(imageFlex, imagesFlex)
 	}
 
+	// Wide enough for the labels, which are cut off past maxFieldLabelWidth
 	fieldBlock := tview.NewFlex().SetDirection(tview.FlexRow)
-	for _, field := range a.digimon.Fields {
+	fieldWidth := 0
+	for _, field := range view.Fields {
 		fieldImage := tview.NewImage()
-		if image := services.GetImageByURL(field.Image); image != nil {
+		if field.ImageURL == "" {
+			slog.Debug("No image for field", "field", field.Name)
+		} else if image := a.loadImage(field.ImageURL); image != nil {
 			fieldImage.SetImage(image)
-		} else {
-			log.Println("Failed to load field image:", field.Image)
 		}
 		fieldBlock.AddItem(fieldImage, 0, 1, false)
+
+		// The label opens the field browser on this field
+		fieldID := field.ID
+		fieldLabel := tview.NewButton(field.Name).SetSelectedFunc(func() {
+			a.showFieldBrowser(fieldID)
+		})
+		fieldLabel.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorSilver))
+		fieldLabel.SetActivatedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkCyan))
+		fieldBlock.AddItem(fieldLabel, 1, 0, false)
+		fieldWidth = max(fieldWidth, min(tview.TaggedStringWidth(field.Name)+2, maxFieldLabelWidth))
+	}
+	if fieldWidth > 0 {
+		imagesFlex.AddItem(fieldBlock, fieldWidth, 0, false)
+	} else {
+		imagesFlex.AddItem(fieldBlock, 0, 1, false)
 	}
-	imagesFlex.AddItem(fieldBlock, 0, 1, false)
 	leftBlock.AddItem(imagesFlex, 0, 8, false)
 
 	digimonName := tview.NewTextView().
-		SetText(fmt.Sprintf("Name: %s", a.digimon.Name)).
+		SetText(fmt.Sprintf("Name: %s", view.Name)).
 		SetTextColor(tcell.ColorGold)
 	leftBlock.AddItem(digimonName, 1, 0, false)
 
 	digimonReleaseDate := tview.NewTextView().
-		SetText(fmt.Sprintf("Release Date: %s", a.digimon.ReleaseDate)).
+		SetText(fmt.Sprintf("Release Date: %s", view.ReleaseDate)).
 		SetTextColor(tcell.ColorSilver)
 	leftBlock.AddItem(digimonReleaseDate, 1, 0, false)
 
 	digimonLevel := tview.NewTextView().
-		SetText(fmt.Sprintf("Levels: %s", a.getDigimonLevels())).
+		SetText(fmt.Sprintf("Levels: %s", view.LevelsText())).
 		SetTextColor(tcell.ColorGreen)
 	leftBlock.AddItem(digimonLevel, 1, 0, false)
 
 	digimonTypes := tview.NewTextView().
-		SetText(fmt.Sprintf("Types: %s", a.getDigimonTypes())).
+		SetText(fmt.Sprintf("Types: %s", view.TypesText())).
 		SetTextColor(tcell.ColorPurple)
 	leftBlock.AddItem(digimonTypes, 1, 0, false)
 
 	digimonAttributes := tview.NewTextView().
-		SetText(fmt.Sprintf("Attributes: %s", a.getDigimonAttributes())).
+		SetText(fmt.Sprintf("Attributes: %s", view.AttributesText())).
 		SetTextColor(tcell.ColorLightCyan)
 	leftBlock.AddItem(digimonAttributes, 1, 0, false)
 
@@ -328,7 +661,7 @@ func (a *App) setupDigimonBlock(block *tview.Flex) {
 	descriptionBlock.SetBorder(true).SetBorderColor(tcell.ColorBlue)
 	descriptionBlock.SetTitle("Description").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
 	descriptionBlock.AddItem(tview.NewTextView().
-		SetText(a.getDigimonDescription()).
+		SetText(view.Description).
 		SetTextColor(tcell.ColorLightCyan), 0, 1, false)
 
 	rightBlock.AddItem(descriptionBlock, 0, 1, false)
@@ -338,10 +671,12 @@ func (a *App) setupDigimonBlock(block *tview.Flex) {
 	skillBlock.SetBorder(true).SetBorderColor(tcell.ColorRed)
 	skillBlock.SetTitle("Skills").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
 
-	skillsTextView := tview.NewTextView().
-		SetText(a.getDigimonSkills()).SetWrap(true)
-	skillsTextView.SetTextColor(tcell.ColorYellow)
-	skillBlock.AddItem(skillsTextView, 0, 1, false)
+	a.skillsTable = newSkillsTable(view.Skills, a.showSkillSearch)
+	a.skillsTable.SetDoneFunc(func(key tcell.Key) {
+		// Esc and Tab hand the focus back to the list
+		a.SetFocus(a.digimonList)
+	})
+	skillBlock.AddItem(a.skillsTable, 0, 1, false)
 
 	rightBlock.AddItem(skillBlock, 0, 1, false)
 
@@ -350,47 +685,96 @@ func (a *App) setupDigimonBlock(block *tview.Flex) {
 }
 
 func (a *App) loadDigimonDetail(digimonID int) {
-	// Check if already loading
-	a.loadingMutex.RLock()
-	if a.isLoading {
-		a.loadingMutex.RUnlock()
+	if !a.startLoading() {
 		return
 	}
-	a.loadingMutex.RUnlock()
 
-	// Set loading state
+	// Check cache first
+	if digimonDetail, ok := a.cache.Get(digimonID); ok {
+		a.finishLoading()
+
+		a.showDigimon(digimonDetail)
+		return
+	}
+
+	a.fetchDigimonDetail(func() (*models.DigimonDetail, error) {
+		return a.service.GetDigimonByID(digimonID)
+	}, func() {
+		a.loadDigimonDetail(digimonID)
+	})
+}
+
+// loadDigimonByName shows the digimon with the exact given name.
+func (a *App) loadDigimonByName(name string) {
+	if !a.startLoading() {
+		return
+	}
+
+	a.fetchDigimonDetail(func() (*models.DigimonDetail, error) {
+		return a.service.GetDigimonByName(name)
+	}, func() {
+		a.loadDigimonByName(name)
+	})
+}
+
+// startLoading marks a detail load as in progress. It returns false if
+// another load is already running.
+func (a *App) startLoading() bool {
 	a.loadingMutex.Lock()
+	defer a.loadingMutex.Unlock()
+
+	if a.isLoading {
+		return false
+	}
 	a.isLoading = true
+	return true
+}
+
+func (a *App) finishLoading() {
+	a.loadingMutex.Lock()
+	a.isLoading = false
 	a.loadingMutex.Unlock()
+}
 
-	// Check cache first
+// fetchDigimonDetail runs fetch in the background and shows its result in
+// the detail panel, or an error with a retry button.
+func (a *App) fetchDigimonDetail(fetch func() (*models.DigimonDetail, error), retry func()) {
 	a.setupLoadingState()
 
 	// Use goroutine for API call
+	a.status.requestStarted()
 	go func() {
-		digimonDetail, err := services.GetDigimonByID(digimonID)
+		digimonDetail, err := fetch()
+		a.status.requestFinished(err)
 
 		// Update UI on main thread
 		a.QueueUpdateDraw(func() {
-			a.loadingMutex.Lock()
-			a.isLoading = false
-			a.loadingMutex.Unlock()
+			a.finishLoading()
 
 			if err != nil {
-				log.Println("Failed to fetch digimon detail:", err)
+				slog.Error("Failed to fetch digimon detail", "error", err)
+				a.setupErrorState(err, retry)
 				return
 			}
 
 			// Cache the result
-			a.cache.Put(digimonID, digimonDetail)
+			if digimonDetail.ID > 0 {
+				a.cache.Put(digimonDetail.ID, digimonDetail)
+			}
 
 			// Update UI
-			a.digimon = digimonDetail
-			a.setupDigimonBlock(a.digimonBlock)
+			a.showDigimon(digimonDetail)
 		})
 	}()
 }
 
+// showDigimon makes digimon the shown Digimon and remembers it as viewed.
+func (a *App) showDigimon(digimon *models.DigimonDetail) {
+	a.digimon = digimon
+	a.viewed.add(*digimon)
+	a.setupDigimonBlock(a.digimonBlock)
+}
+
 func (a *App) setupLoadingState() {
 	a.digimonBlock.Clear()
 	a.digimonBlock.SetDirection(tview.FlexColumn)
@@ -404,101 +788,65 @@ func (a *App) setupLoadingState() {
 	a.digimonBlock.AddItem(loadingText, 0, 1, false)
 }
 
-func (a *App) getDigimonDescription() string {
-	var description string
-	for _, descriptionItem := range a.digimon.Descriptions {
-		if descriptionItem.Language == "en_us" {
-			description = descriptionItem.Description
-			break
-		} else {
-			description = "No description available in English"
-		}
-	}
-	if description == "" {
-		description = "No description available"
-	}
-	return description
-}
+// setupErrorState replaces the detail panel with the error and a button that
+// runs retry.
+func (a *App) setupErrorState(err error, retry func()) {
+	a.digimonBlock.Clear()
+	a.digimonBlock.SetDirection(tview.FlexRow)
+	a.digimonBlock.SetBorder(true).SetBorderColor(tcell.ColorRed)
 
-func (a *App) getDigimonSkills() string {
-	var skillsText string
-	if len(a.digimon.Skills) == 0 {
-		skillsText = "No skills available"
-	} else {
-		skillsText = ""
-	}
-	for _, skill := range a.digimon.Skills {
-		if skill.Skill == "" {
-			continue
-		}
-		var skillText string
-		if skill.Description == "" {
-			skillText = fmt.Sprintf("- %s", skill.Skill)
-		} else {
-			skillText = fmt.Sprintf("- %s: %s", skill.Skill, skill.Description)
-		}
-		skillsText += skillText + "\n"
+	// Retrying will not make an unknown Digimon appear
+	if errors.Is(err, services.ErrNotFound) {
+		notFoundText := tview.NewTextView().
+			SetText(fmt.Sprintf("404\n\n%s", err)).
+			SetTextAlign(tview.AlignCenter).
+			SetTextColor(tcell.ColorYellow).
+			SetWrap(true)
+		a.digimonBlock.AddItem(notFoundText, 0, 1, false)
+		return
 	}
-	return skillsText
-}
 
-func (a *App) getDigimonLevels() string {
-	var levels string
-	if len(a.digimon.Levels) > 0 {
-		for i, t := range a.digimon.Levels {
-			if i > 0 {
-				levels += ", "
-			}
-			levels += t.Level
-		}
-	} else {
-		levels = "Unknown"
-	}
-	return levels
-}
+	errorText := tview.NewTextView().
+		SetText(fmt.Sprintf("Failed to load Digimon details:\n%s", err)).
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(tcell.ColorRed).
+		SetWrap(true)
 
-func (a *App) getDigimonTypes() string {
-	var types string
-	if len(a.digimon.Types) > 0 {
-		for i, t := range a.digimon.Types {
-			if i > 0 {
-				types += ", "
-			}
-			types += t.Type
-		}
-	} else {
-		types = "Unknown"
-	}
-	return types
+	retryButton := tview.NewButton("Retry")
+	retryButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(tcell.ColorBlack))
+	retryButton.SetSelectedFunc(retry)
+
+	buttonRow := tview.NewFlex().SetDirection(tview.FlexColumn)
+	buttonRow.AddItem(nil, 0, 1, false)
+	buttonRow.AddItem(retryButton, 9, 0, true)
+	buttonRow.AddItem(nil, 0, 1, false)
+
+	a.digimonBlock.AddItem(errorText, 0, 1, false)
+	a.digimonBlock.AddItem(buttonRow, 1, 0, true)
+	a.SetFocus(retryButton)
 }
 
-func (a *App) getDigimonAttributes() string {
-	var attributes string
-	if len(a.digimon.Attributes) > 0 {
-		for i, t := range a.digimon.Attributes {
-			if i > 0 {
-				attributes += ", "
-			}
-			attributes += t.Attribute
-		}
-	} else {
-		attributes = "Unknown"
+// loadImage returns the image at url, using the image cache that the
+// prefetcher warms, or nil if it cannot be loaded.
+func (a *App) loadImage(url string) image.Image {
+	if img, ok := a.imageCache.Get(url); ok {
+		return img
+	}
+	img, err := a.service.GetImageByURL(url)
+	if err != nil {
+		slog.Warn("Failed to load image", "url", url, "error", err)
+		return nil
 	}
-	return attributes
+	a.imageCache.Put(url, img)
+	return img
 }
 
-func (a *App) loadFallbackImage(imageFlex *tview.Image, imagesFlex *tview.Flex) {
-	noImageFile, err := os.Open("assets/no-image.png")
+func (a *App) loadFallbackImage(imageFlex *imageView, imagesFlex *tview.Flex) {
+	noImage, err := png.Decode(bytes.NewReader(assets.NoImage))
 	if err != nil {
-		log.Println("Failed to open no-image.png:", err)
+		slog.Error("Failed to decode no-image.png", "error", err)
 	} else {
-		defer noImageFile.Close()
-		noImage, err := png.Decode(noImageFile)
-		if err != nil {
-			log.Println("Failed to decode no-image.png:", err)
-		} else {
-			imageFlex.SetImage(noImage).SetAlign(0, 0)
-		}
+		imageFlex.SetImage(noImage).SetAlign(0, 0)
 	}
 	imagesFlex.AddItem(imageFlex, 0, 8, false)
 }
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/sangnt1552314/digimontex/internal/export"
+)
+
+// exportCurrent saves the artwork, field icons and HTML and Markdown pages
+// of the shown Digimon to the export directory in the background.
+func (a *App) exportCurrent() {
+	digimon := a.digimon
+	if digimon == nil || digimon.ID == 0 {
+		return
+	}
+	opts := export.Options{
+		Dir:     a.exportDir,
+		Formats: []export.Format{export.HTML, export.Markdown},
+	}
+	if opts.Dir == "" {
+		opts.Dir = export.DefaultDir
+	}
+
+	a.status.requestStarted()
+	go func() {
+		result, err := export.Export(a.service, digimon, opts)
+		a.status.requestFinished(err)
+
+		if err != nil {
+			slog.Error("Failed to export digimon", "id", digimon.ID, "error", err)
+		} else {
+			for _, imageErr := range result.Errors {
+				slog.Warn("Image left out of export", "id", digimon.ID, "error", imageErr)
+			}
+			message := fmt.Sprintf("Exported %s to %s", digimon.Name, result.Dir)
+			if len(result.Errors) > 0 {
+				message += fmt.Sprintf(" (%d images missing)", len(result.Errors))
+			}
+			a.status.notify(message)
+		}
+		a.QueueUpdateDraw(func() {})
+	}()
+}
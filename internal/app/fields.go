@@ -0,0 +1,388 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/fields"
+)
+
+const fieldsPageName = "fields"
+
+// anyOption is the filter choice that matches every Digimon
+const anyOption = "Any"
+
+const (
+	fieldRowHeight = 3
+	fieldIconWidth = 6
+)
+
+// fieldList lists fields as rows of icon, name and member count.
+type fieldList struct {
+	*tview.Box
+	fields  []fields.Field
+	icons   []*tview.Image
+	current int
+	offset  int
+	changed func(field fields.Field)
+}
+
+func newFieldList(changed func(field fields.Field)) *fieldList {
+	return &fieldList{
+		Box:     tview.NewBox(),
+		changed: changed,
+	}
+}
+
+// setFields replaces the rows, keeping the field with selectedID current.
+func (l *fieldList) setFields(list []fields.Field, icons map[string]image.Image, selectedID int) {
+	l.fields = list
+	l.icons = make([]*tview.Image, len(list))
+	l.current = 0
+	for i, field := range list {
+		l.icons[i] = tview.NewImage()
+		if img, ok := icons[field.Image]; ok {
+			l.icons[i].SetImage(img)
+		}
+		if field.ID == selectedID {
+			l.current = i
+		}
+	}
+	l.selectIndex(l.current)
+}
+
+// selected returns the current field, false when the list is empty.
+func (l *fieldList) selected() (fields.Field, bool) {
+	if l.current < 0 || l.current >= len(l.fields) {
+		return fields.Field{}, false
+	}
+	return l.fields[l.current], true
+}
+
+func (l *fieldList) selectIndex(index int) {
+	if len(l.fields) == 0 {
+		return
+	}
+	l.current = max(0, min(index, len(l.fields)-1))
+	if l.changed != nil {
+		l.changed(l.fields[l.current])
+	}
+}
+
+func (l *fieldList) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+	x, y, width, height := l.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	rows := max(height/fieldRowHeight, 1)
+	if l.current < l.offset {
+		l.offset = l.current
+	}
+	if l.current >= l.offset+rows {
+		l.offset = l.current - rows + 1
+	}
+
+	for i := l.offset; i < len(l.fields) && (i-l.offset)*fieldRowHeight < height; i++ {
+		rowY := y + (i-l.offset)*fieldRowHeight
+		rowHeight := min(fieldRowHeight, y+height-rowY)
+
+		if i == l.current {
+			background := tcell.ColorDarkSlateGray
+			if l.HasFocus() {
+				background = tcell.ColorDarkCyan
+			}
+			for dy := 0; dy < rowHeight; dy++ {
+				for dx := 0; dx < width; dx++ {
+					screen.SetContent(x+dx, rowY+dy, ' ', nil, tcell.StyleDefault.Background(background))
+				}
+			}
+		}
+
+		l.icons[i].SetRect(x, rowY, fieldIconWidth, rowHeight)
+		l.icons[i].Draw(screen)
+
+		field := l.fields[i]
+		textX, textWidth := x+fieldIconWidth+1, width-fieldIconWidth-1
+		tview.Print(screen, tview.Escape(field.Name), textX, rowY, textWidth, tview.AlignLeft, tcell.ColorGold)
+		if rowHeight > 1 {
+			tview.Print(screen, fmt.Sprintf("%d Digimon", field.Count), textX, rowY+1, textWidth, tview.AlignLeft, tcell.ColorSilver)
+		}
+	}
+}
+
+func (l *fieldList) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyUp:
+			l.selectIndex(l.current - 1)
+		case tcell.KeyDown:
+			l.selectIndex(l.current + 1)
+		case tcell.KeyHome:
+			l.selectIndex(0)
+		case tcell.KeyEnd:
+			l.selectIndex(len(l.fields) - 1)
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'k':
+				l.selectIndex(l.current - 1)
+			case 'j':
+				l.selectIndex(l.current + 1)
+			}
+		}
+	})
+}
+
+func (l *fieldList) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+	return l.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+		x, y := event.Position()
+		if !l.InRect(x, y) {
+			return false, nil
+		}
+		switch action {
+		case tview.MouseLeftClick:
+			setFocus(l)
+			_, top, _, _ := l.GetInnerRect()
+			if y >= top {
+				if index := l.offset + (y-top)/fieldRowHeight; index < len(l.fields) {
+					l.selectIndex(index)
+				}
+			}
+			return true, nil
+		case tview.MouseScrollUp:
+			l.selectIndex(l.current - 1)
+			return true, nil
+		case tview.MouseScrollDown:
+			l.selectIndex(l.current + 1)
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+// fieldBrowser is the page listing every field and the Digimon of the
+// selected one, narrowed by level and attribute.
+type fieldBrowser struct {
+	app        *App
+	index      *fields.Index
+	icons      map[string]image.Image
+	list       *fieldList
+	levels     *tview.DropDown
+	attributes *tview.DropDown
+	members    *tview.Table
+	footer     *tview.TextView
+	right      *tview.Flex
+	// level and attribute are the chosen filters, empty for any
+	level     string
+	attribute string
+	shown     []fields.Member
+	// updating is set while the filter options are replaced, so their
+	// selected callbacks do not refresh the table on the way
+	updating bool
+}
+
+func newFieldBrowser(a *App) *fieldBrowser {
+	b := &fieldBrowser{
+		app:        a,
+		icons:      make(map[string]image.Image),
+		levels:     tview.NewDropDown().SetLabel("Level: "),
+		attributes: tview.NewDropDown().SetLabel("Attribute: "),
+		members:    tview.NewTable(),
+		footer:     tview.NewTextView(),
+		right:      tview.NewFlex().SetDirection(tview.FlexRow),
+	}
+	b.list = newFieldList(b.showField)
+	b.list.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	b.list.SetTitle("Fields").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+
+	for _, dropDown := range []*tview.DropDown{b.levels, b.attributes} {
+		dropDown.SetLabelColor(tcell.ColorLightCyan).
+			SetFieldBackgroundColor(tcell.ColorDarkSlateGray).
+			SetFieldTextColor(tcell.ColorWhite)
+	}
+
+	b.members.SetFixed(1, 0).SetSelectable(true, false)
+	b.members.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite))
+	b.members.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(b.shown) {
+			a.pages.RemovePage(fieldsPageName)
+			a.loadDigimonDetail(b.shown[row-1].ID)
+		}
+	})
+	b.footer.SetTextColor(tcell.ColorSilver)
+
+	b.right.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan).
+		SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	filters := tview.NewFlex().
+		AddItem(b.levels, 0, 1, false).
+		AddItem(b.attributes, 0, 1, false)
+	b.right.AddItem(filters, 1, 0, false).
+		AddItem(b.members, 0, 1, false).
+		AddItem(b.footer, 1, 0, false)
+
+	return b
+}
+
+// layout returns the page, with Tab cycling the focus and Esc closing it.
+func (b *fieldBrowser) layout() tview.Primitive {
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("Tab: next panel | Enter: open Digimon | Esc: back")
+	page := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(b.list, 32, 0, true).
+			AddItem(b.right, 0, 1, false), 0, 1, true).
+		AddItem(help, 1, 0, false)
+
+	order := []tview.Primitive{b.list, b.levels, b.attributes, b.members}
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if b.levels.IsOpen() || b.attributes.IsOpen() {
+			return event
+		}
+		switch event.Key() {
+		case tcell.KeyEscape:
+			b.app.pages.RemovePage(fieldsPageName)
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			b.app.cycleFocus(order, event.Key() == tcell.KeyBacktab)
+			return nil
+		}
+		return event
+	})
+	return page
+}
+
+// setIndex shows index, keeping selectedID as the current field.
+func (b *fieldBrowser) setIndex(index *fields.Index, selectedID int) {
+	b.index = index
+	b.list.setFields(index.Fields(), b.icons, selectedID)
+	if len(index.Fields()) == 0 {
+		b.right.SetTitle("No fields")
+		b.footer.SetText("No fields known yet. Run `digimontex sync` or view some Digimon first.")
+	}
+}
+
+// showField fills the filters and members for field, keeping the chosen
+// filters when the field has them.
+func (b *fieldBrowser) showField(field fields.Field) {
+	b.right.SetTitle(fmt.Sprintf("%s (%d)", tview.Escape(field.Name), field.Count))
+
+	b.updating = true
+	b.level = b.setFilterOptions(b.levels, b.index.Levels(field.ID), b.level, func(value string) {
+		b.level = value
+	})
+	b.attribute = b.setFilterOptions(b.attributes, b.index.Attributes(field.ID), b.attribute, func(value string) {
+		b.attribute = value
+	})
+	b.updating = false
+
+	b.refresh()
+}
+
+// setFilterOptions offers Any followed by values on dropDown, calling set
+// with the choice, and returns the filter that stays selected: current if
+// values have it, otherwise any.
+func (b *fieldBrowser) setFilterOptions(dropDown *tview.DropDown, values []string, current string, set func(value string)) string {
+	selected := 0
+	for i, value := range values {
+		if strings.EqualFold(value, current) {
+			selected = i + 1
+		}
+	}
+	dropDown.SetOptions(append([]string{anyOption}, values...), func(text string, index int) {
+		if b.updating {
+			return
+		}
+		set(filterValue(text))
+		b.refresh()
+	})
+	dropDown.SetCurrentOption(selected)
+	if selected == 0 {
+		return ""
+	}
+	return current
+}
+
+func (b *fieldBrowser) refresh() {
+	field, ok := b.list.selected()
+	if !ok {
+		return
+	}
+
+	b.shown = b.index.Members(field.ID, fields.Filter{Level: b.level, Attribute: b.attribute})
+
+	b.members.Clear()
+	for column, title := range []string{"Digimon", "ID", "Levels", "Attributes"} {
+		b.members.SetCell(0, column, tview.NewTableCell(title).SetTextColor(tcell.ColorOrange).SetSelectable(false))
+	}
+	for i, member := range b.shown {
+		b.members.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(member.Name)).SetTextColor(tcell.ColorGold))
+		b.members.SetCell(i+1, 1, tview.NewTableCell(strconv.Itoa(member.ID)).SetTextColor(tcell.ColorSilver))
+		b.members.SetCell(i+1, 2, tview.NewTableCell(strings.Join(member.Levels, ", ")).SetTextColor(tcell.ColorGreen))
+		b.members.SetCell(i+1, 3, tview.NewTableCell(strings.Join(member.Attributes, ", ")).SetTextColor(tcell.ColorLightCyan).SetExpansion(1))
+	}
+	b.members.Select(1, 0).ScrollToBeginning()
+
+	footer := fmt.Sprintf("%d of %d Digimon from local data", len(b.shown), field.Count)
+	if b.app.store == nil || b.app.store.Len() == 0 {
+		footer += "; run `digimontex sync` to list every member"
+	}
+	b.footer.SetText(footer)
+}
+
+// showFieldBrowser opens the field browser with fieldID selected, 0 selects
+// the first field. The Digimon come from the synced store and this session;
+// the /field reference endpoint and the icons are fetched in the background.
+func (a *App) showFieldBrowser(fieldID int) {
+	if a.pages.HasPage(fieldsPageName) {
+		return
+	}
+
+	details := a.localDetails()
+	browser := newFieldBrowser(a)
+	browser.setIndex(fields.NewIndex(nil, details), fieldID)
+
+	a.pages.AddPage(fieldsPageName, browser.layout(), true, true)
+	a.SetFocus(browser.list)
+
+	a.status.requestStarted()
+	go func() {
+		reference, err := a.service.GetFieldList()
+		a.status.requestFinished(err)
+		if err != nil {
+			slog.Warn("Failed to fetch fields", "error", err)
+		}
+
+		index := fields.NewIndex(reference, details)
+		icons := make(map[string]image.Image)
+		for _, field := range index.Fields() {
+			if field.Image == "" {
+				continue
+			}
+			if img := a.loadImage(field.Image); img != nil {
+				icons[field.Image] = img
+			}
+		}
+
+		a.QueueUpdateDraw(func() {
+			browser.icons = icons
+			selected := fieldID
+			if field, ok := browser.list.selected(); ok {
+				selected = field.ID
+			}
+			browser.setIndex(index, selected)
+		})
+	}()
+}
+
+func filterValue(option string) string {
+	if option == anyOption {
+		return ""
+	}
+	return option
+}
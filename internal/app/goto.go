@@ -0,0 +1,65 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const goToPageName = "goto"
+
+// showGoToPrompt opens a prompt that accepts a numeric ID or an exact name.
+func (a *App) showGoToPrompt() {
+	if a.pages.HasPage(goToPageName) {
+		return
+	}
+
+	input := tview.NewInputField().
+		SetFieldBackgroundColor(tcell.ColorNone).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabel("ID or name: ").
+		SetLabelColor(tcell.ColorLightCyan)
+	input.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	input.SetTitle("Go to Digimon").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		query := strings.TrimSpace(input.GetText())
+		a.pages.RemovePage(goToPageName)
+		if key != tcell.KeyEnter || query == "" {
+			return
+		}
+		a.goToDigimon(query)
+	})
+
+	// Center the prompt over the main layout
+	prompt := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(input, 3, 0, true).
+			AddItem(nil, 0, 1, false), 50, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	a.pages.AddPage(goToPageName, prompt, true, true)
+	a.SetFocus(input)
+}
+
+// goToDigimon loads the detail for a numeric ID or an exact name.
+func (a *App) goToDigimon(query string) {
+	if id, err := strconv.Atoi(query); err == nil {
+		a.loadDigimonDetail(id)
+		return
+	}
+	a.loadDigimonByName(query)
+}
+
+// loadAdjacentDigimon walks the ID range from the current detail by step.
+func (a *App) loadAdjacentDigimon(step int) {
+	id := a.digimon.ID + step
+	if a.digimon.ID == 0 || id < 1 || id > a.maxDigimonID {
+		return
+	}
+	a.loadDigimonDetail(id)
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"log/slog"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/termimg"
+)
+
+// kittyImageID is the Kitty image number of the main artwork. Sending it
+// again replaces the previous Digimon's image.
+const kittyImageID = 1
+
+// imageView shows the main Digimon artwork. Without a graphics protocol it
+// is a plain tview.Image drawing half blocks. Otherwise it leaves its area
+// blank and the renderer writes the image over it once the frame is on the
+// terminal.
+type imageView struct {
+	*tview.Image
+	image    image.Image
+	renderer *imageRenderer
+	// fittedSize is the panel size the half-block image was shrunk for
+	fittedSize [2]int
+}
+
+func newImageView(renderer *imageRenderer) *imageView {
+	return &imageView{
+		Image:    tview.NewImage(),
+		renderer: renderer,
+	}
+}
+
+func (v *imageView) SetImage(img image.Image) *imageView {
+	v.image = img
+	v.fittedSize = [2]int{}
+	v.Image.SetImage(img)
+	return v
+}
+
+func (v *imageView) Draw(screen tcell.Screen) {
+	if v.renderer.protocol == termimg.Blocks || v.image == nil {
+		v.fitToPanel()
+		v.Image.Draw(screen)
+		return
+	}
+	v.Box.DrawForSubclass(screen, v)
+	if v.renderer.covered != nil && v.renderer.covered() {
+		// Placing nothing makes flush take the image off the terminal, it
+		// would show through the blank cells of the page on top
+		return
+	}
+	x, y, width, height := v.GetInnerRect()
+	v.renderer.place(v.image, x, y, width, height)
+}
+
+// fitToPanel hands tview.Image a copy of the artwork shrunk to the panel,
+// so large images are not resampled from full size on every draw.
+func (v *imageView) fitToPanel() {
+	_, _, width, height := v.GetInnerRect()
+	if v.image == nil || v.fittedSize == [2]int{width, height} || width <= 0 || height <= 0 {
+		return
+	}
+	v.fittedSize = [2]int{width, height}
+	v.Image.SetImage(imaging.Fit(v.image, width*termimg.DefaultCellWidth, height*termimg.DefaultCellHeight))
+}
+
+type imagePlacement struct {
+	image               image.Image
+	x, y, width, height int
+}
+
+// imageRenderer writes the image placed during a frame with a terminal
+// graphics protocol. Escape sequences are only sent when the placement
+// changes, as tcell leaves the blank cells under the image alone.
+type imageRenderer struct {
+	protocol termimg.Protocol
+	// output returns the terminal to write to and its cell size in pixels,
+	// nil when the screen is not a terminal
+	output func(screen tcell.Screen) (io.Writer, int, int)
+	// covered reports whether another page is drawn over the image view
+	covered    func() bool
+	pending    *imagePlacement
+	shown      *imagePlacement
+	screenSize [2]int
+}
+
+func newImageRenderer(protocol termimg.Protocol) *imageRenderer {
+	return &imageRenderer{
+		protocol: protocol,
+		output:   ttyOutput,
+	}
+}
+
+func ttyOutput(screen tcell.Screen) (io.Writer, int, int) {
+	tty, ok := screen.Tty()
+	if !ok {
+		return nil, 0, 0
+	}
+	size, err := tty.WindowSize()
+	if err != nil {
+		return tty, 0, 0
+	}
+	cellWidth, cellHeight := size.CellDimensions()
+	return tty, cellWidth, cellHeight
+}
+
+func (r *imageRenderer) place(img image.Image, x, y, width, height int) {
+	r.pending = &imagePlacement{image: img, x: x, y: y, width: width, height: height}
+}
+
+// flush runs after every frame is drawn, on the draw goroutine.
+func (r *imageRenderer) flush(screen tcell.Screen) {
+	pending := r.pending
+	r.pending = nil
+
+	// A resize clears the terminal, so the image has to be sent again
+	width, height := screen.Size()
+	resized := r.screenSize != [2]int{width, height}
+	r.screenSize = [2]int{width, height}
+
+	if !resized && placementEqual(pending, r.shown) {
+		return
+	}
+	out, cellWidth, cellHeight := r.output(screen)
+	if out == nil {
+		r.shown = pending
+		return
+	}
+
+	// Put the frame on the terminal first so its cells do not overwrite
+	// the image. Other protocols paint pixels into the cells, so a full
+	// redraw is the way to remove the previous image.
+	if r.shown != nil && r.protocol != termimg.Kitty {
+		screen.Sync()
+	} else {
+		screen.Show()
+	}
+	if r.shown != nil && r.protocol == termimg.Kitty && pending == nil {
+		out.Write(termimg.KittyDelete(kittyImageID))
+	}
+	r.shown = pending
+	if pending == nil {
+		return
+	}
+
+	data, err := termimg.Encode(r.protocol, pending.image, kittyImageID, pending.width, pending.height, cellWidth, cellHeight)
+	if err != nil {
+		slog.Warn("Failed to encode image", "protocol", r.protocol, "error", err)
+		return
+	}
+	// Save the cursor tcell thinks it has, draw at the placement, restore
+	fmt.Fprintf(out, "\x1b7\x1b[%d;%dH", pending.y+1, pending.x+1)
+	out.Write(data)
+	io.WriteString(out, "\x1b8")
+}
+
+func placementEqual(a, b *imagePlacement) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/sangnt1552314/digimontex/internal/termimg"
+)
+
+func TestImageRendererSendsChangedPlacements(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(80, 24)
+
+	var out bytes.Buffer
+	renderer := newImageRenderer(termimg.Kitty)
+	renderer.output = func(tcell.Screen) (io.Writer, int, int) {
+		return &out, 8, 16
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	view := newImageView(renderer).SetImage(img)
+	view.SetRect(2, 3, 10, 5)
+
+	view.Draw(screen)
+	renderer.flush(screen)
+	if got := out.String(); !strings.HasPrefix(got, "\x1b7\x1b[4;3H\x1b_Ga=T,") || !strings.HasSuffix(got, "\x1b8") {
+		t.Fatalf("first frame wrote %q", got[:min(len(got), 40)])
+	}
+
+	// An unchanged frame leaves the terminal alone
+	out.Reset()
+	view.Draw(screen)
+	renderer.flush(screen)
+	if out.Len() != 0 {
+		t.Errorf("unchanged frame wrote %q", out.String())
+	}
+
+	// Without the view the image is deleted
+	renderer.flush(screen)
+	if got := out.String(); got != string(termimg.KittyDelete(kittyImageID)) {
+		t.Errorf("removing the image wrote %q", got)
+	}
+}
+
+func TestImageViewFallsBackToBlocks(t *testing.T) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(20, 10)
+
+	renderer := newImageRenderer(termimg.Blocks)
+	renderer.output = func(tcell.Screen) (io.Writer, int, int) {
+		t.Fatal("blocks rendering wrote to the terminal")
+		return nil, 0, 0
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	view := newImageView(renderer).SetImage(img)
+	view.SetRect(0, 0, 20, 10)
+	view.Draw(screen)
+
+	if renderer.pending != nil {
+		t.Error("blocks rendering placed an image")
+	}
+	if _, _, style, _ := screen.GetContent(5, 5); style == tcell.StyleDefault {
+		t.Error("half blocks were not drawn")
+	}
+}
+
+func TestOverlayRemovesImage(t *testing.T) {
+	ta := startTestAppWithConfig(t, newFakeService(5), Config{ImageProtocol: termimg.Kitty})
+	ta.waitFor("Name: Greymon")
+
+	// The simulation screen is no terminal, so shown is what would be on it
+	waitForPlacement := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(waitTimeout)
+		for time.Now().Before(deadline) {
+			placed := make(chan bool, 1)
+			ta.QueueUpdateDraw(func() {
+				placed <- ta.images.shown != nil
+			})
+			if <-placed == want {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for the image placement to be %v", want)
+	}
+	waitForPlacement(true)
+
+	ta.press(tcell.KeyCtrlG)
+	ta.waitForPage(goToPageName)
+	waitForPlacement(false)
+
+	ta.press(tcell.KeyEscape)
+	ta.waitForPage("main")
+	waitForPlacement(true)
+}
@@ -0,0 +1,280 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services/prefetch"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+const (
+	// scrollThreshold is how close to either end of the list the selection
+	// has to get before the adjacent page is loaded
+	scrollThreshold = 3
+	// maxLoadedPages bounds how many pages the infinite list keeps at once
+	maxLoadedPages = 5
+)
+
+// scrollWindow tracks the contiguous range of pages loaded into the list in
+// infinite mode. It is only touched from the UI goroutine.
+type scrollWindow struct {
+	firstPage int
+	pageItems []int
+	loading   bool
+	// prefetched holds pages fetched ahead of the window; a nil entry marks a
+	// prefetch that is still in flight
+	prefetched map[int]*models.DigimonResponse
+	// awaited is the page waiting for its running prefetch to be inserted
+	awaited *awaitedPage
+}
+
+type awaitedPage struct {
+	page       int
+	appendPage bool
+}
+
+func newScrollWindow() *scrollWindow {
+	return &scrollWindow{
+		prefetched: make(map[int]*models.DigimonResponse),
+	}
+}
+
+func (w *scrollWindow) lastPage() int {
+	return w.firstPage + len(w.pageItems) - 1
+}
+
+func (a *App) toggleListMode() {
+	page := a.firstLoadedPage()
+	a.infinite = !a.infinite
+	a.loadPage(page)
+}
+
+// resetScroll drops every loaded page and starts the window at page.
+func (a *App) resetScroll(page int) {
+	a.listGen++
+	a.scroll.firstPage = page
+	a.scroll.pageItems = a.scroll.pageItems[:0]
+	a.scroll.loading = false
+	a.scroll.prefetched = make(map[int]*models.DigimonResponse)
+	a.scroll.awaited = nil
+
+	a.digimonList.Clear()
+	a.digimonList.AddItem("Loading...", "", 0, nil)
+
+	a.fetchScrollPage(page, true)
+}
+
+// onListChanged loads the neighbouring page when the selection gets close to
+// either end of the window.
+func (a *App) onListChanged(index int) {
+	if !a.infinite || a.scroll.loading || len(a.scroll.pageItems) == 0 {
+		return
+	}
+
+	count := a.digimonList.GetItemCount()
+	switch {
+	case index >= count-scrollThreshold && a.scroll.lastPage() < a.totalPages-1:
+		a.fetchScrollPage(a.scroll.lastPage()+1, true)
+	case index < scrollThreshold && a.scroll.firstPage > 0:
+		a.fetchScrollPage(a.scroll.firstPage-1, false)
+	}
+}
+
+// fetchScrollPage loads page and appends or prepends it to the window. The
+// list is always updated from a queued update so that it never changes while
+// tview is still dispatching the event that triggered the load.
+func (a *App) fetchScrollPage(page int, appendPage bool) {
+	a.scroll.loading = true
+	generation := a.listGen
+
+	resp, prefetched := a.scroll.prefetched[page]
+	if prefetched && resp == nil {
+		// The prefetch inserts the page when it arrives
+		a.scroll.awaited = &awaitedPage{page: page, appendPage: appendPage}
+		return
+	}
+	if prefetched {
+		delete(a.scroll.prefetched, page)
+		go a.QueueUpdateDraw(func() {
+			if generation != a.listGen {
+				return
+			}
+			a.scroll.loading = false
+			a.insertScrollPage(page, resp, appendPage)
+		})
+		return
+	}
+
+	params := a.pageParams(page)
+	fetchList := a.listSource()
+	a.status.requestStarted()
+	go func() {
+		resp, err := fetchList(params)
+		a.status.requestFinished(err)
+
+		a.QueueUpdateDraw(func() {
+			if generation != a.listGen {
+				return
+			}
+			a.scroll.loading = false
+
+			if err != nil {
+				slog.Error("Failed to fetch digimon list", "page", page, "error", err)
+				if len(a.scroll.pageItems) == 0 {
+					a.digimonList.Clear()
+					a.digimonList.AddItem("Failed to fetch digimon list", tview.Escape(err.Error()), 0, nil)
+					a.digimonList.AddItem("Retry", "", 'r', func() {
+						a.resetScroll(page)
+					})
+				}
+				return
+			}
+
+			a.insertScrollPage(page, resp, appendPage)
+		})
+	}()
+}
+
+func (a *App) insertScrollPage(page int, resp *models.DigimonResponse, appendPage bool) {
+	list := a.digimonList
+	if len(a.scroll.pageItems) == 0 {
+		list.Clear()
+		a.scroll.firstPage = page
+	}
+
+	view := viewmodel.NewListView(resp)
+	a.totalPages = view.TotalPages
+	a.totalItems = view.TotalItems
+	if a.searchTerm == "" && !a.localList() && a.totalItems > 0 {
+		a.maxDigimonID = a.totalItems
+	}
+	offset, horizontal := list.GetOffset()
+
+	ids := view.IDs()
+
+	if appendPage {
+		for _, item := range view.Items {
+			list.AddItem(item.Name, "", 0, a.digimonSelectedFunc(item.ID))
+		}
+		a.scroll.pageItems = append(a.scroll.pageItems, len(view.Items))
+
+		if len(a.scroll.pageItems) > maxLoadedPages {
+			dropped := a.scroll.pageItems[0]
+			for range dropped {
+				list.RemoveItem(0)
+			}
+			a.scroll.pageItems = a.scroll.pageItems[1:]
+			a.scroll.firstPage++
+			offset = max(offset-dropped, 0)
+		}
+	} else {
+		for i := len(view.Items) - 1; i >= 0; i-- {
+			item := view.Items[i]
+			list.InsertItem(0, item.Name, "", 0, a.digimonSelectedFunc(item.ID))
+		}
+		a.scroll.pageItems = append([]int{len(view.Items)}, a.scroll.pageItems...)
+		a.scroll.firstPage = page
+		offset += len(view.Items)
+
+		if len(a.scroll.pageItems) > maxLoadedPages {
+			last := len(a.scroll.pageItems) - 1
+			for range a.scroll.pageItems[last] {
+				list.RemoveItem(-1)
+			}
+			a.scroll.pageItems = a.scroll.pageItems[:last]
+		}
+	}
+	// Keep the same items on screen; the selection is shifted by tview itself
+	list.SetOffset(offset, horizontal)
+
+	if page == a.scroll.firstPage {
+		a.currentPage = page
+		a.previousPage = resp.Pageable.PreviousPage
+		a.nextPage = resp.Pageable.NextPage
+	}
+
+	// Restore the item that was at the top before a page size change
+	if a.listAnchor >= 0 && a.listAnchor < list.GetItemCount() {
+		list.SetCurrentItem(a.listAnchor)
+		list.SetOffset(a.listAnchor, 0)
+	}
+	a.listAnchor = -1
+
+	a.updatePageLabel()
+
+	// Forget prefetched pages that are no longer next to the window
+	for prefetchedPage := range a.scroll.prefetched {
+		if prefetchedPage != a.scroll.firstPage-1 && prefetchedPage != a.scroll.lastPage()+1 {
+			delete(a.scroll.prefetched, prefetchedPage)
+		}
+	}
+
+	// Warm details starting from the end of the page nearest the selection
+	if appendPage {
+		a.prefetcher.Prefetch(prefetch.PriorityOrder(ids, 0))
+		a.prefetchScrollPage(a.scroll.lastPage() + 1)
+	} else {
+		a.prefetcher.Prefetch(prefetch.PriorityOrder(ids, len(ids)-1))
+		a.prefetchScrollPage(a.scroll.firstPage - 1)
+	}
+}
+
+// prefetchScrollPage fetches page in the background so the next scroll step
+// does not have to wait for the API.
+func (a *App) prefetchScrollPage(page int) {
+	if page < 0 || page >= a.totalPages {
+		return
+	}
+	if _, ok := a.scroll.prefetched[page]; ok {
+		return
+	}
+
+	a.scroll.prefetched[page] = nil
+	generation := a.listGen
+	params := a.pageParams(page)
+	fetchList := a.listSource()
+	a.status.requestStarted()
+	go func() {
+		resp, err := fetchList(params)
+		a.status.requestFinished(err)
+
+		a.QueueUpdateDraw(func() {
+			if generation != a.listGen {
+				return
+			}
+			awaited := a.scroll.awaited
+			if awaited != nil && awaited.page == page {
+				a.scroll.awaited = nil
+			} else {
+				awaited = nil
+			}
+			if err != nil {
+				slog.Warn("Failed to prefetch digimon list", "page", page, "error", err)
+				delete(a.scroll.prefetched, page)
+				if awaited != nil {
+					a.fetchScrollPage(page, awaited.appendPage)
+				}
+				return
+			}
+			if awaited != nil {
+				delete(a.scroll.prefetched, page)
+				a.scroll.loading = false
+				a.insertScrollPage(page, resp, awaited.appendPage)
+				return
+			}
+			if page == a.scroll.firstPage-1 || page == a.scroll.lastPage()+1 {
+				a.scroll.prefetched[page] = resp
+			}
+		})
+	}()
+}
+
+func (a *App) pageParams(page int) models.DigimonSearchQueryParams {
+	return models.DigimonSearchQueryParams{
+		PageSize: a.pageSize,
+		Page:     page,
+		Name:     a.searchTerm,
+	}
+}
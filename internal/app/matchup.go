@@ -0,0 +1,206 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/matchup"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+const vsPageName = "vs"
+
+// outcomeColors colors the matrix cells by outcome for the row side.
+var outcomeColors = map[matchup.Outcome]tcell.Color{
+	matchup.Advantage:    tcell.ColorGreen,
+	matchup.Neutral:      tcell.ColorSilver,
+	matchup.Disadvantage: tcell.ColorRed,
+}
+
+// matchupResolver resolves the inputs of the vs view. It is used off the UI
+// goroutine, so it works on a copy of the team being built.
+func (a *App) matchupResolver() matchup.Resolver {
+	current := a.team
+	current.Members = append([]team.Member(nil), a.team.Members...)
+	details := a.localDetails()
+	return matchup.Resolver{
+		ByID: a.lookupDetail,
+		ByName: func(name string) (models.DigimonDetail, error) {
+			for _, detail := range details {
+				if strings.EqualFold(detail.Name, name) {
+					return detail, nil
+				}
+			}
+			detail, err := a.service.GetDigimonByName(name)
+			if err != nil {
+				return models.DigimonDetail{}, err
+			}
+			return *detail, nil
+		},
+		Teams:   a.teams,
+		Current: &current,
+	}
+}
+
+// vsView is the page matching two Digimon or teams against each other.
+type vsView struct {
+	app     *App
+	inputA  *tview.InputField
+	inputB  *tview.InputField
+	matrix  *tview.Table
+	detail  *tview.TextView
+	summary *tview.TextView
+	result  matchup.Matrix
+	// gen tells the latest comparison apart from slower earlier ones
+	gen int
+}
+
+// showVs opens the vs view with the shown Digimon on the left.
+func (a *App) showVs() {
+	if a.pages.HasPage(vsPageName) {
+		return
+	}
+
+	newInput := func(label string) *tview.InputField {
+		return tview.NewInputField().
+			SetFieldBackgroundColor(tcell.ColorNone).
+			SetFieldTextColor(tcell.ColorWhite).
+			SetLabel(label).
+			SetLabelColor(tcell.ColorLightCyan).
+			SetPlaceholder("Digimon name or ID, team code, saved team or \"team\"").
+			SetPlaceholderTextColor(tcell.ColorGray)
+	}
+	v := &vsView{
+		app:     a,
+		inputA:  newInput("A: "),
+		inputB:  newInput("B: "),
+		matrix:  tview.NewTable().SetFixed(1, 1).SetSelectable(true, true),
+		detail:  tview.NewTextView().SetTextColor(tcell.ColorYellow),
+		summary: tview.NewTextView().SetTextColor(tcell.ColorSilver),
+	}
+	if a.digimon != nil && a.digimon.ID != 0 {
+		v.inputA.SetText(a.digimon.Name)
+	}
+
+	v.matrix.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.matrix.SetTitle("Matchups for A").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	v.matrix.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite))
+	v.matrix.SetSelectionChangedFunc(func(row, column int) {
+		v.showDetail(row-1, column-1)
+	})
+	v.detail.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.detail.SetTitle("Matchup").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+
+	for _, input := range []*tview.InputField{v.inputA, v.inputB} {
+		input.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEnter {
+				v.compare()
+			}
+		})
+	}
+
+	rules := make([]string, 0, len(a.rules.Rules()))
+	for _, rule := range a.rules.Rules() {
+		rules = append(rules, rule.String())
+	}
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("Rules: " + strings.Join(rules, ", ") + " | Enter: compare | Tab: next control | Esc: back")
+
+	inputs := tview.NewFlex().
+		AddItem(v.inputA, 0, 1, false).
+		AddItem(v.inputB, 0, 1, false)
+	body := tview.NewFlex().
+		AddItem(v.matrix, 0, 1, false).
+		AddItem(v.detail, 0, 1, false)
+	page := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(inputs, 1, 0, true).
+		AddItem(v.summary, 1, 0, false).
+		AddItem(body, 0, 1, false).
+		AddItem(help, 1, 0, false)
+
+	order := []tview.Primitive{v.inputB, v.matrix, v.inputA}
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.RemovePage(vsPageName)
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			a.cycleFocus(order, event.Key() == tcell.KeyBacktab)
+			return nil
+		}
+		return event
+	})
+
+	v.summary.SetText("Enter what A is matched against in B")
+	a.pages.AddPage(vsPageName, page, true, true)
+	a.SetFocus(v.inputB)
+}
+
+// compare resolves both sides in the background and shows their matrix.
+func (v *vsView) compare() {
+	a := v.app
+	inputA, inputB := v.inputA.GetText(), v.inputB.GetText()
+	resolver := a.matchupResolver()
+	rules := a.rules
+
+	v.gen++
+	gen := v.gen
+	v.summary.SetText("Looking up both sides...").SetTextColor(tcell.ColorSilver)
+	a.status.requestStarted()
+	go func() {
+		lineupA, err := resolver.Resolve(inputA)
+		var lineupB matchup.Lineup
+		if err == nil {
+			lineupB, err = resolver.Resolve(inputB)
+		}
+		a.status.requestFinished(err)
+
+		a.QueueUpdateDraw(func() {
+			if gen != v.gen {
+				return
+			}
+			if err != nil {
+				v.summary.SetText(err.Error()).SetTextColor(tcell.ColorRed)
+				return
+			}
+			v.show(matchup.NewMatrix(rules, lineupA.Sides, lineupB.Sides), lineupA, lineupB)
+		})
+	}()
+}
+
+func (v *vsView) show(m matchup.Matrix, lineupA, lineupB matchup.Lineup) {
+	v.result = m
+	v.matrix.Clear()
+	v.matrix.SetCell(0, 0, tview.NewTableCell("A \\ B").SetTextColor(tcell.ColorOrange).SetSelectable(false))
+	for j, col := range m.Cols {
+		v.matrix.SetCell(0, j+1, tview.NewTableCell(tview.Escape(col.Name)).
+			SetTextColor(tcell.ColorOrange).SetSelectable(false))
+	}
+	for i, row := range m.Rows {
+		tally := m.RowTally(i)
+		v.matrix.SetCell(i+1, 0, tview.NewTableCell(fmt.Sprintf("%s +%d -%d", tview.Escape(row.Name), tally.Advantages, tally.Disadvantages)).
+			SetTextColor(tcell.ColorGold).SetSelectable(false))
+		for j, cell := range m.Cells[i] {
+			v.matrix.SetCell(i+1, j+1, tview.NewTableCell(cell.Outcome.Symbol()).
+				SetAlign(tview.AlignCenter).
+				SetTextColor(outcomeColors[cell.Outcome]))
+		}
+	}
+	v.matrix.Select(1, 1)
+	v.showDetail(0, 0)
+
+	tally := m.Tally()
+	v.summary.SetText(fmt.Sprintf("%s vs %s: %s for %s (%s)", lineupA.Name, lineupB.Name, tally.Outcome(), lineupA.Name, tally)).
+		SetTextColor(outcomeColors[tally.Outcome()])
+}
+
+// showDetail explains the matchup of row i against column j.
+func (v *vsView) showDetail(i, j int) {
+	if i < 0 || i >= len(v.result.Rows) || j < 0 || j >= len(v.result.Cols) {
+		return
+	}
+	v.detail.SetText(tview.Escape(v.result.Cells[i][j].Text()))
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const maxPageSize = 100
+
+// loadPage fetches the given zero-based page for the current search term.
+func (a *App) loadPage(page int) {
+	a.prefetcher.Cancel()
+	a.currentPage = page
+	if a.infinite {
+		a.resetScroll(page)
+		return
+	}
+	a.buildDigimonList(a.digimonList, a.pageParams(page))
+}
+
+// setPageSize reloads the list with a new page size, picking the page that
+// contains the item currently at the top of the list so it stays in view.
+func (a *App) setPageSize(size int) {
+	if size == a.pageSize {
+		return
+	}
+
+	offset, _ := a.digimonList.GetOffset()
+	firstVisible := a.firstLoadedPage()*a.pageSize + offset
+
+	a.pageSize = size
+	a.listAnchor = firstVisible % size
+	a.loadPage(firstVisible / size)
+}
+
+// firstLoadedPage returns the page shown at the top of the list.
+func (a *App) firstLoadedPage() int {
+	if a.infinite {
+		return a.scroll.firstPage
+	}
+	return a.currentPage
+}
+
+// updatePageLabel shows the position in the results, and whether they come
+// from the local data rather than the API.
+func (a *App) updatePageLabel() {
+	source := ""
+	if a.localList() {
+		source = ", local data"
+	}
+	if a.totalPages == 0 {
+		if a.localList() && (a.store == nil || a.store.Len() == 0) {
+			a.pageLabel.SetText("No results; run `digimontex sync` to filter every Digimon")
+			return
+		}
+		a.pageLabel.SetText("No results" + source)
+		return
+	}
+	if a.infinite && len(a.scroll.pageItems) > 1 {
+		a.pageLabel.SetText(fmt.Sprintf("Pages %d-%d / %d (%d results%s)",
+			a.scroll.firstPage+1, a.scroll.lastPage()+1, a.totalPages, a.totalItems, source))
+		return
+	}
+	a.pageLabel.SetText(fmt.Sprintf("Page %d / %d (%d results%s)", a.currentPage+1, a.totalPages, a.totalItems, source))
+}
+
+func (a *App) setupPageInputs() tview.Primitive {
+	inputs := tview.NewFlex().SetDirection(tview.FlexRow)
+
+	goToInput := tview.NewInputField().
+		SetFieldBackgroundColor(tcell.ColorNone).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabel("Go to: ").
+		SetLabelColor(tcell.ColorLightCyan).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	goToInput.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		page, err := strconv.Atoi(goToInput.GetText())
+		goToInput.SetText("")
+		if err != nil {
+			return
+		}
+		page = max(page, 1)
+		if a.totalPages > 0 {
+			page = min(page, a.totalPages)
+		}
+		a.loadPage(page - 1)
+	})
+
+	sizeInput := tview.NewInputField().
+		SetFieldBackgroundColor(tcell.ColorNone).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabel("Size: ").
+		SetLabelColor(tcell.ColorLightCyan).
+		SetText(strconv.Itoa(a.pageSize)).
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	sizeInput.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		size, err := strconv.Atoi(sizeInput.GetText())
+		if err != nil || size < 1 {
+			sizeInput.SetText(strconv.Itoa(a.pageSize))
+			return
+		}
+		size = min(size, maxPageSize)
+		sizeInput.SetText(strconv.Itoa(size))
+		a.setPageSize(size)
+	})
+
+	inputs.AddItem(goToInput, 1, 0, false)
+	inputs.AddItem(sizeInput, 1, 0, false)
+
+	return inputs
+}
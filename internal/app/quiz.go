@@ -0,0 +1,237 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/quiz"
+)
+
+const quizPageName = "quiz"
+
+// quizView is the quiz page: one multiple choice question at a time with
+// the running score and the high scores.
+type quizView struct {
+	app        *App
+	generator  *quiz.Generator
+	seed       int64
+	question   quiz.Question
+	score      quiz.Score
+	answered   bool
+	status     *tview.TextView
+	prompt     *tview.TextView
+	artwork    *tview.Image
+	choices    *tview.List
+	feedback   *tview.TextView
+	highScores *tview.TextView
+	body       *tview.Flex
+	// gen tells the current question's artwork apart from earlier ones
+	gen int
+}
+
+// showQuiz starts a quiz over the synced dataset and the Digimon viewed
+// this session.
+func (a *App) showQuiz() {
+	if a.pages.HasPage(quizPageName) {
+		return
+	}
+
+	seed := a.quizSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	v := &quizView{
+		app:        a,
+		seed:       seed,
+		status:     tview.NewTextView().SetTextColor(tcell.ColorGold),
+		prompt:     tview.NewTextView().SetTextColor(tcell.ColorWhite),
+		artwork:    tview.NewImage(),
+		choices:    tview.NewList().ShowSecondaryText(false),
+		feedback:   tview.NewTextView(),
+		highScores: tview.NewTextView().SetTextColor(tcell.ColorSilver),
+		body:       tview.NewFlex().SetDirection(tview.FlexRow),
+	}
+	v.choices.SetMainTextColor(tcell.ColorYellow).SetShortcutColor(tcell.ColorLightCyan)
+	v.choices.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.choices.SetTitle("Choices").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	v.highScores.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.highScores.SetTitle("High scores").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	v.body.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.body.SetTitle("Quiz").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("1-4 or Enter: answer | Enter or n: next question | Esc: finish and save the score")
+	left := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.status, 1, 0, false).
+		AddItem(v.body, 0, 1, true).
+		AddItem(help, 1, 0, false)
+	page := tview.NewFlex().
+		AddItem(left, 0, 3, true).
+		AddItem(v.highScores, 0, 1, false)
+
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			v.finish()
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'n' && v.answered:
+			v.next()
+			return nil
+		}
+		return event
+	})
+
+	v.showHighScores()
+	a.pages.AddPage(quizPageName, page, true, true)
+
+	generator, err := quiz.NewGenerator(a.localDetails(), seed)
+	if err != nil {
+		v.prompt.SetText(err.Error() + "; view some Digimon or run `digimontex sync` first")
+		v.body.AddItem(v.prompt, 0, 1, false)
+		a.SetFocus(v.body)
+		return
+	}
+	v.generator = generator
+	v.next()
+}
+
+// next asks the next question.
+func (v *quizView) next() {
+	a := v.app
+	v.question = v.generator.Next()
+	v.answered = false
+	v.gen++
+
+	v.choices.Clear()
+	for i, choice := range v.question.Choices {
+		index := i
+		v.choices.AddItem(tview.Escape(choice), "", rune('1'+i), func() {
+			v.choose(index)
+		})
+	}
+	v.prompt.SetText(tview.Escape(v.question.Prompt))
+	v.feedback.SetText("")
+
+	v.body.Clear()
+	v.body.AddItem(v.prompt, 1, 0, false)
+	if v.question.Kind == quiz.Silhouette {
+		v.artwork.SetImage(nil)
+		v.body.AddItem(v.artwork, 0, 1, false)
+		v.body.AddItem(v.choices, len(v.question.Choices)+2, 0, true)
+		v.loadSilhouette(v.question.ImageURL)
+	} else {
+		v.body.AddItem(v.choices, len(v.question.Choices)+2, 0, true)
+		v.body.AddItem(nil, 0, 1, false)
+	}
+	v.body.AddItem(v.feedback, 1, 0, false)
+	v.showStatus()
+	a.SetFocus(v.choices)
+}
+
+// loadSilhouette fetches the artwork of a silhouette question in the
+// background and shows its silhouette.
+func (v *quizView) loadSilhouette(url string) {
+	a := v.app
+	gen := v.gen
+	a.status.requestStarted()
+	go func() {
+		img := a.loadImage(url)
+		var silhouette image.Image
+		if img != nil {
+			silhouette = imaging.Silhouette(img, color.Black, color.White)
+		}
+		a.status.requestFinished(nil)
+
+		a.QueueUpdateDraw(func() {
+			if gen != v.gen {
+				return
+			}
+			if silhouette == nil {
+				v.prompt.SetText(tview.Escape(v.question.Prompt) + " (the artwork could not be loaded)")
+				return
+			}
+			v.artwork.SetImage(silhouette)
+		})
+	}()
+}
+
+// choose answers the question with the choice at index, or moves on once
+// it is answered.
+func (v *quizView) choose(index int) {
+	if v.answered {
+		v.next()
+		return
+	}
+	v.answered = true
+
+	correct := v.question.Correct(index)
+	points := v.score.Record(correct)
+	for i, choice := range v.question.Choices {
+		switch {
+		case i == v.question.Answer:
+			v.choices.SetItemText(i, "[green]"+tview.Escape(choice)+" ✓", "")
+		case i == index:
+			v.choices.SetItemText(i, "[red]"+tview.Escape(choice)+" ✗", "")
+		}
+	}
+	if correct {
+		v.feedback.SetText(fmt.Sprintf("Right! +%d", points)).SetTextColor(tcell.ColorGreen)
+	} else {
+		v.feedback.SetText("Wrong, the answer was " + tview.Escape(v.question.AnswerText())).SetTextColor(tcell.ColorRed)
+	}
+	if v.question.Kind == quiz.Silhouette {
+		v.prompt.SetText("It is " + tview.Escape(v.question.Subject.Name))
+	}
+	v.showStatus()
+}
+
+func (v *quizView) showStatus() {
+	v.status.SetText(fmt.Sprintf("%s | Seed %d", v.score, v.seed))
+}
+
+func (v *quizView) showHighScores() {
+	scores := v.app.quizScores
+	if scores == nil {
+		v.highScores.SetText("High scores are not saved, see --quiz-scores")
+		return
+	}
+	entries := scores.Entries()
+	if len(entries) == 0 {
+		v.highScores.SetText("No high scores yet")
+		return
+	}
+	var b strings.Builder
+	for i, entry := range entries {
+		fmt.Fprintf(&b, "%2d. %d points, best streak %d, %d/%d right\n    %s, seed %d\n",
+			i+1, entry.Points, entry.BestStreak, entry.Correct, entry.Answered, entry.At.Format("2006-01-02 15:04"), entry.Seed)
+	}
+	v.highScores.SetText(b.String())
+}
+
+// finish closes the quiz and records its score.
+func (v *quizView) finish() {
+	a := v.app
+	a.pages.RemovePage(quizPageName)
+	if v.score.Answered == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Quiz over: %d points, %d/%d right", v.score.Points, v.score.Correct, v.score.Answered)
+	if a.quizScores != nil {
+		if rank := a.quizScores.Add(quiz.Entry{Score: v.score, Seed: v.seed, At: time.Now()}); rank > 0 {
+			message += fmt.Sprintf(", high score #%d", rank)
+			if err := a.quizScores.Save(); err != nil {
+				slog.Error("Failed to save quiz high scores", "path", a.quizScores.Path(), "error", err)
+				a.status.showError(err)
+			}
+		}
+	}
+	a.status.notify(message)
+}
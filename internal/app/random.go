@@ -0,0 +1,226 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/discovery"
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// defaultMaxDigimonID is used as the upper bound of the ID range until the
+// unfiltered list has reported the real number of Digimon.
+const defaultMaxDigimonID = discovery.DefaultMaxID
+
+const randomPageName = "random"
+
+// loadStartDigimon shows the Digimon the app was started with, the Digimon
+// of the day unless --default names another.
+func (a *App) loadStartDigimon() {
+	switch {
+	case a.start.ID > 0:
+		a.picker.Seen(a.start.ID)
+		a.loadDigimonDetail(a.start.ID)
+	case a.start.Name != "":
+		a.loadDigimonByName(a.start.Name)
+	default:
+		a.loadDailyDigimon()
+	}
+}
+
+// loadDailyDigimon shows the Digimon of the day. The ID range is asked from
+// the API first, so everyone gets the same Digimon on a date.
+func (a *App) loadDailyDigimon() {
+	var stored []models.DigimonDetail
+	if a.store != nil {
+		stored = a.store.All()
+	}
+
+	a.status.requestStarted()
+	go func() {
+		today := time.Now()
+		maxID, err := discovery.MaxID(a.service.GetDigimonList, stored)
+		a.status.requestFinished(err)
+		if err != nil {
+			slog.Warn("Failed to get the number of Digimon, the Digimon of the day may differ", "maxID", maxID, "error", err)
+		}
+		id := discovery.Daily(today, maxID)
+
+		a.QueueUpdateDraw(func() {
+			if err == nil {
+				a.maxDigimonID = maxID
+			}
+			a.picker.Seen(id)
+			a.status.notify(fmt.Sprintf("Digimon of the day for %s: #%d", today.Format(discovery.DateLayout), id))
+			a.loadDigimonDetail(id)
+		})
+	}()
+}
+
+// loadRandomDigimon shows a random Digimon matching the random filter that
+// was not shown by a random pick this session.
+func (a *App) loadRandomDigimon() {
+	filter := a.randomFilter
+	switch {
+	case filter.IsZero():
+		a.pickRandom(filter, discovery.Range(a.maxDigimonID))
+		return
+	case a.store != nil && a.store.Len() > 0:
+		// The synced dataset has every Digimon, no need to ask the API
+		a.pickRandom(filter, discovery.Matching(a.localDetails(), filter))
+		return
+	}
+	if ids, ok := a.randomPool[filter]; ok {
+		a.pickRandom(filter, ids)
+		return
+	}
+
+	a.status.requestStarted()
+	go func() {
+		ids, err := discovery.Candidates(a.service.GetDigimonList, filter)
+		a.status.requestFinished(err)
+		if err != nil {
+			slog.Error("Failed to list random candidates", "filter", filter.String(), "error", err)
+			a.QueueUpdateDraw(func() {})
+			return
+		}
+		a.QueueUpdateDraw(func() {
+			a.randomPool[filter] = ids
+			if a.randomFilter == filter {
+				a.pickRandom(filter, ids)
+			}
+		})
+	}()
+}
+
+func (a *App) pickRandom(filter discovery.Filter, candidates []int) {
+	id, restarted, err := a.picker.Pick(candidates)
+	if errors.Is(err, discovery.ErrNoCandidates) {
+		a.status.notify(fmt.Sprintf("No %s to pick from", filter))
+		return
+	}
+	if restarted {
+		a.status.notify(fmt.Sprintf("All %d %s were shown this session, starting over", len(candidates), filter))
+	}
+	a.loadDigimonDetail(id)
+}
+
+// showRandomFilters opens a dialog that sets the level and attribute random
+// picks are made from, then picks one.
+func (a *App) showRandomFilters() {
+	if a.pages.HasPage(randomPageName) {
+		return
+	}
+
+	local := a.localDetails()
+	levels := tview.NewDropDown().SetLabel("Level: ")
+	attributes := tview.NewDropDown().SetLabel("Attribute: ")
+	setRandomOptions(levels, discovery.Levels, local, func(d models.DigimonDetail) []string {
+		var values []string
+		for _, level := range d.Levels {
+			values = append(values, level.Level)
+		}
+		return values
+	}, a.randomFilter.Level)
+	setRandomOptions(attributes, discovery.Attributes, local, func(d models.DigimonDetail) []string {
+		var values []string
+		for _, attribute := range d.Attributes {
+			values = append(values, attribute.Attribute)
+		}
+		return values
+	}, a.randomFilter.Attribute)
+	for _, dropDown := range []*tview.DropDown{levels, attributes} {
+		dropDown.SetLabelColor(tcell.ColorLightCyan).
+			SetFieldBackgroundColor(tcell.ColorDarkSlateGray).
+			SetFieldTextColor(tcell.ColorWhite)
+	}
+
+	pickButton := tview.NewButton("Pick")
+	pickButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	pickButton.SetSelectedFunc(func() {
+		_, level := levels.GetCurrentOption()
+		_, attribute := attributes.GetCurrentOption()
+		a.randomFilter = discovery.Filter{Level: filterValue(level), Attribute: filterValue(attribute)}
+		a.pages.RemovePage(randomPageName)
+		a.loadRandomDigimon()
+	})
+
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("Ctrl+R picks again with these filters | Tab: next control | Esc: back")
+	buttons := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(pickButton, 8, 0, false)
+	dialog := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(levels, 1, 0, true).
+		AddItem(attributes, 1, 0, false).
+		AddItem(buttons, 1, 0, false).
+		AddItem(help, 1, 0, false)
+	dialog.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	dialog.SetTitle("Random Digimon").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+
+	order := []tview.Primitive{levels, attributes, pickButton}
+	dialog.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.RemovePage(randomPageName)
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			a.cycleFocus(order, event.Key() == tcell.KeyBacktab)
+			return nil
+		}
+		return event
+	})
+
+	// Center the dialog over the main layout
+	page := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(dialog, 6, 0, true).
+			AddItem(nil, 0, 1, false), 60, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	a.pages.AddPage(randomPageName, page, true, true)
+	a.SetFocus(levels)
+}
+
+// setRandomOptions offers Any, the known values and any other value of the
+// local data on dropDown, with current selected.
+func setRandomOptions(dropDown *tview.DropDown, known []string, local []models.DigimonDetail, values func(models.DigimonDetail) []string, current string) {
+	options := append([]string(nil), known...)
+	var extra []string
+	for _, d := range local {
+		for _, value := range values(d) {
+			value = strings.TrimSpace(value)
+			if value != "" && !containsFold(options, value) && !containsFold(extra, value) {
+				extra = append(extra, value)
+			}
+		}
+	}
+	sort.Strings(extra)
+	options = append([]string{anyOption}, append(options, extra...)...)
+
+	selected := 0
+	for i, option := range options {
+		if current != "" && strings.EqualFold(option, current) {
+			selected = i
+		}
+	}
+	dropDown.SetOptions(options, nil)
+	dropDown.SetCurrentOption(selected)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
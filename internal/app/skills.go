@@ -0,0 +1,228 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/skills"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+const skillSearchPageName = "skill-search"
+
+var skillColumns = []string{"Skill", "Translation", "Description"}
+
+// skillsTable lists the skills of the shown Digimon. Clicking a header or
+// pressing 1-3 sorts by that column, again to reverse it. Selecting a skill
+// calls onSelect.
+type skillsTable struct {
+	*tview.Table
+	skills     []viewmodel.SkillView
+	sortColumn int
+	descending bool
+	// sorted tells whether the user picked an order, the API order is kept
+	// until then
+	sorted bool
+}
+
+func newSkillsTable(skillViews []viewmodel.SkillView, onSelect func(viewmodel.SkillView)) *skillsTable {
+	t := &skillsTable{
+		Table:  tview.NewTable(),
+		skills: append([]viewmodel.SkillView(nil), skillViews...),
+	}
+	t.SetFixed(1, 0).SetSelectable(len(skillViews) > 0, false)
+	t.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite))
+	t.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(t.skills) && onSelect != nil {
+			onSelect(t.skills[row-1])
+		}
+	})
+	t.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() >= '1' && int(event.Rune()-'1') < len(skillColumns) {
+			t.sortBy(int(event.Rune() - '1'))
+			return nil
+		}
+		return event
+	})
+	t.render()
+	return t
+}
+
+// sortBy orders the rows by column, reversing the order when it is already
+// sorted by it.
+func (t *skillsTable) sortBy(column int) {
+	if t.sorted && t.sortColumn == column {
+		t.descending = !t.descending
+	} else {
+		t.sortColumn, t.descending, t.sorted = column, false, true
+	}
+
+	sort.SliceStable(t.skills, func(i, j int) bool {
+		a, b := skillColumn(t.skills[i], column), skillColumn(t.skills[j], column)
+		if t.descending {
+			return strings.ToLower(a) > strings.ToLower(b)
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	t.render()
+}
+
+func skillColumn(skill viewmodel.SkillView, column int) string {
+	switch column {
+	case 1:
+		return skill.Translation
+	case 2:
+		return skill.Description
+	}
+	return skill.Name
+}
+
+func (t *skillsTable) render() {
+	t.Clear()
+
+	for column, title := range skillColumns {
+		if t.sorted && column == t.sortColumn {
+			if t.descending {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		t.SetCell(0, column, tview.NewTableCell(title).
+			SetTextColor(tcell.ColorOrange).
+			SetSelectable(false).
+			SetClickedFunc(func() bool {
+				t.sortBy(column)
+				return true
+			}))
+	}
+
+	if len(t.skills) == 0 {
+		t.SetCell(1, 0, tview.NewTableCell("No skills available").SetTextColor(tcell.ColorYellow).SetSelectable(false))
+		return
+	}
+	for i, skill := range t.skills {
+		t.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(skill.Name)).SetTextColor(tcell.ColorYellow))
+		t.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(skill.Translation)).SetTextColor(tcell.ColorLightCyan))
+		t.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(skill.Description)).SetExpansion(1))
+	}
+}
+
+// viewedDetails are the Digimon shown in the detail panel this session, in
+// the order they were first shown. Unlike the detail cache, prefetching
+// does not add to them and nothing is evicted. It is only touched from the
+// UI goroutine.
+type viewedDetails struct {
+	details map[int]models.DigimonDetail
+	order   []int
+}
+
+func newViewedDetails() *viewedDetails {
+	return &viewedDetails{details: make(map[int]models.DigimonDetail)}
+}
+
+func (v *viewedDetails) add(d models.DigimonDetail) {
+	if d.ID <= 0 {
+		return
+	}
+	if _, ok := v.details[d.ID]; !ok {
+		v.order = append(v.order, d.ID)
+	}
+	v.details[d.ID] = d
+}
+
+func (v *viewedDetails) values() []models.DigimonDetail {
+	details := make([]models.DigimonDetail, 0, len(v.order))
+	for _, id := range v.order {
+		details = append(details, v.details[id])
+	}
+	return details
+}
+
+// localDetails returns every detail available without the API: the synced
+// store and the Digimon viewed this session.
+func (a *App) localDetails() []models.DigimonDetail {
+	seen := make(map[int]bool)
+	var details []models.DigimonDetail
+	if a.store != nil {
+		for _, digimon := range a.store.All() {
+			seen[digimon.ID] = true
+			details = append(details, digimon)
+		}
+	}
+	for _, digimon := range a.viewed.values() {
+		if !seen[digimon.ID] {
+			seen[digimon.ID] = true
+			details = append(details, digimon)
+		}
+	}
+	return details
+}
+
+// showSkillSearch lists the other Digimon in the local data with a skill of
+// the same name. Selecting one opens it.
+func (a *App) showSkillSearch(skill viewmodel.SkillView) {
+	details := a.localDetails()
+	usages := skills.NewIndex(details).Shared(skill.Name, a.digimon.ID)
+
+	table := tview.NewTable().SetFixed(1, 0).SetSelectable(len(usages) > 0, false)
+	table.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	table.SetTitle(fmt.Sprintf("Other Digimon with %s (%d)", tview.Escape(skill.Name), len(usages))).
+		SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite))
+
+	for column, title := range []string{"Digimon", "ID", "Translation", "Description"} {
+		table.SetCell(0, column, tview.NewTableCell(title).SetTextColor(tcell.ColorOrange).SetSelectable(false))
+	}
+	for i, usage := range usages {
+		table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(usage.Digimon)).SetTextColor(tcell.ColorGold))
+		table.SetCell(i+1, 1, tview.NewTableCell(strconv.Itoa(usage.DigimonID)).SetTextColor(tcell.ColorSilver))
+		table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(usage.Translation)).SetTextColor(tcell.ColorLightCyan))
+		table.SetCell(i+1, 3, tview.NewTableCell(tview.Escape(usage.Description)).SetExpansion(1))
+	}
+
+	footer := tview.NewTextView().SetDynamicColors(true).SetTextColor(tcell.ColorSilver)
+	searched := fmt.Sprintf("Searched %d Digimon", len(details))
+	if a.store == nil || a.store.Len() == 0 {
+		searched += " viewed this session; run `digimontex sync` to search them all"
+	}
+	if len(usages) == 0 {
+		footer.SetText(searched + ". Esc closes.")
+	} else {
+		footer.SetText(searched + ". Enter opens a Digimon, Esc closes.")
+	}
+
+	closePage := func() {
+		a.pages.RemovePage(skillSearchPageName)
+	}
+	table.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(usages) {
+			closePage()
+			a.loadDigimonDetail(usages[row-1].DigimonID)
+		}
+	})
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			closePage()
+		}
+	})
+
+	body := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(body, 0, 3, true).
+			AddItem(nil, 0, 1, false), 0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	a.pages.AddPage(skillSearchPageName, modal, true, true)
+	a.SetFocus(table)
+}
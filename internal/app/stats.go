@@ -0,0 +1,107 @@
+package app
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/stats"
+)
+
+const statsPageName = "stats"
+
+// barChart is a scrollable text bar chart that is laid out again for the
+// width it is drawn at.
+type barChart struct {
+	*tview.TextView
+	counts []stats.Count
+	width  int
+}
+
+func newBarChart(title string, counts []stats.Count) *barChart {
+	chart := &barChart{
+		TextView: tview.NewTextView(),
+		counts:   counts,
+		width:    -1,
+	}
+	chart.SetTextColor(tcell.ColorLightCyan).SetWrap(false)
+	chart.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	chart.SetTitle(title).SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	return chart
+}
+
+func (c *barChart) Draw(screen tcell.Screen) {
+	_, _, width, _ := c.GetInnerRect()
+	if width != c.width {
+		c.width = width
+		c.SetText(stats.Chart(c.counts, width))
+	}
+	c.TextView.Draw(screen)
+}
+
+// showStats opens the statistics page for the synced dataset and the
+// Digimon fetched this session.
+func (a *App) showStats() {
+	if a.pages.HasPage(statsPageName) {
+		return
+	}
+
+	details := a.localDetails()
+	result := stats.Compute(details, stats.DefaultTop)
+
+	summary := result.Summary()
+	if a.store == nil || a.store.Len() == 0 {
+		summary += " viewed this session; run `digimontex sync` for the whole dataset"
+	}
+	header := tview.NewTextView().SetText(summary).SetTextColor(tcell.ColorGold)
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("Tab: next panel | Arrows: scroll | Esc: back")
+
+	ranked := func(title, text string) *tview.TextView {
+		view := tview.NewTextView().SetText(text).SetTextColor(tcell.ColorYellow).SetWrap(false)
+		view.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+		view.SetTitle(title).SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+		return view
+	}
+
+	panels := []tview.Primitive{
+		newBarChart("Levels", result.Levels),
+		newBarChart("Attributes", result.Attributes),
+		newBarChart("Fields", result.Fields),
+		newBarChart("Types", result.Types),
+		newBarChart("Release years", result.ReleaseYears),
+		ranked("Most evolutions", stats.RankedText(result.MostEvolutions, "evolution")),
+		ranked("Most skills", stats.RankedText(result.MostSkills, "skill")),
+	}
+
+	top := tview.NewFlex().
+		AddItem(panels[0], 0, 1, true).
+		AddItem(panels[1], 0, 1, false).
+		AddItem(panels[2], 0, 1, false)
+	rankings := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panels[5], 0, 1, false).
+		AddItem(panels[6], 0, 1, false)
+	bottom := tview.NewFlex().
+		AddItem(panels[3], 0, 1, false).
+		AddItem(panels[4], 0, 1, false).
+		AddItem(rankings, 0, 1, false)
+
+	page := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(header, 1, 0, false).
+		AddItem(top, 0, 1, true).
+		AddItem(bottom, 0, 1, false).
+		AddItem(help, 1, 0, false)
+
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.RemovePage(statsPageName)
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			a.cycleFocus(panels, event.Key() == tcell.KeyBacktab)
+			return nil
+		}
+		return event
+	})
+
+	a.pages.AddPage(statsPageName, page, true, true)
+	a.SetFocus(panels[0])
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+// statusBar shows request activity, the last error, cache usage and
+// connectivity. Its text is rebuilt on every draw so background goroutines
+// only need to update the counters and queue a redraw. API and cache totals
+// come from the shared metrics, the same counters serve mode exports.
+type statusBar struct {
+	*tview.TextView
+	mutex     sync.RWMutex
+	inFlight  int
+	lastError error
+	lastErrAt time.Time
+	message   string
+	messageAt time.Time
+	online    bool
+}
+
+func newStatusBar() *statusBar {
+	bar := &statusBar{
+		TextView: tview.NewTextView(),
+		online:   true,
+	}
+	bar.SetDynamicColors(true)
+	bar.SetTextColor(tcell.ColorSilver)
+	return bar
+}
+
+func (s *statusBar) requestStarted() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.inFlight++
+}
+
+// requestFinished records the outcome of a request. Transport failures mark
+// the app offline, any answer from the API marks it online again.
+func (s *statusBar) requestFinished(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.inFlight > 0 {
+		s.inFlight--
+	}
+	if err == nil {
+		s.online = true
+		return
+	}
+	s.lastError = err
+	s.lastErrAt = time.Now()
+	s.online = !services.IsNetworkError(err)
+}
+
+// showError shows err as the last error without counting it as a request.
+func (s *statusBar) showError(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.lastError = err
+	s.lastErrAt = time.Now()
+}
+
+// notify shows message until the next error or message replaces it.
+func (s *statusBar) notify(message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.message = message
+	s.messageAt = time.Now()
+}
+
+func (s *statusBar) Draw(screen tcell.Screen) {
+	s.SetText(s.text())
+	s.TextView.Draw(screen)
+}
+
+func (s *statusBar) text() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	parts := make([]string, 0, 5)
+
+	if s.inFlight > 0 {
+		parts = append(parts, fmt.Sprintf("[yellow]Requests: %d[-]", s.inFlight))
+	} else {
+		parts = append(parts, "Requests: 0")
+	}
+
+	parts = append(parts, fmt.Sprintf("API: %.0f calls / %.0f retries",
+		metrics.UpstreamRequests.Sum(), metrics.UpstreamRetries.Sum()))
+	parts = append(parts, fmt.Sprintf("Cache: %.0f hit / %.0f miss",
+		metrics.CacheHits.Value("detail"), metrics.CacheMisses.Value("detail")))
+
+	if s.online {
+		parts = append(parts, "[green]Online[-]")
+	} else {
+		parts = append(parts, "[red]Offline[-]")
+	}
+
+	if s.message != "" && (s.lastError == nil || s.messageAt.After(s.lastErrAt)) {
+		parts = append(parts, fmt.Sprintf("[green]%s %s[-]",
+			s.messageAt.Format("15:04:05"), tview.Escape(s.message)))
+	} else if s.lastError != nil {
+		parts = append(parts, fmt.Sprintf("[red]%s %s[-]",
+			s.lastErrAt.Format("15:04:05"), tview.Escape(s.lastError.Error())))
+	}
+
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,344 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/export"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+const teamPageName = "team"
+
+var teamColumns = []string{"#", "Name", "Levels", "Attributes", "Fields"}
+
+// addToTeam adds the shown Digimon to the team being built.
+func (a *App) addToTeam() {
+	digimon := a.digimon
+	if digimon == nil || digimon.ID == 0 {
+		return
+	}
+	if err := a.team.Add(team.NewMember(*digimon)); err != nil {
+		a.status.showError(err)
+		return
+	}
+	a.teamGen++
+	a.status.notify(fmt.Sprintf("Added %s to the team (%d/%d)", digimon.Name, len(a.team.Members), team.Size))
+	if a.teamView != nil {
+		a.teamView.refresh()
+	}
+}
+
+// lookupDetail finds a Digimon in the local data, or fetches it. It is
+// called off the UI goroutine.
+func (a *App) lookupDetail(id int) (models.DigimonDetail, error) {
+	if a.store != nil {
+		if detail, ok := a.store.Get(id); ok {
+			return detail, nil
+		}
+	}
+	if detail, ok := a.cache.Get(id); ok {
+		return *detail, nil
+	}
+	detail, err := a.service.GetDigimonByID(id)
+	if err != nil {
+		return models.DigimonDetail{}, err
+	}
+	a.cache.Put(id, detail)
+	return *detail, nil
+}
+
+// teamView is the team builder page: the saved teams, the members of the
+// team being built and its analysis.
+type teamView struct {
+	app       *App
+	saved     *tview.List
+	nameInput *tview.InputField
+	members   *tview.Table
+	analysis  *tview.TextView
+	codeInput *tview.InputField
+	footer    *tview.TextView
+}
+
+// showTeamBuilder opens the team builder.
+func (a *App) showTeamBuilder() {
+	if a.pages.HasPage(teamPageName) {
+		return
+	}
+
+	v := &teamView{
+		app:   a,
+		saved: tview.NewList().ShowSecondaryText(false),
+		nameInput: tview.NewInputField().
+			SetFieldBackgroundColor(tcell.ColorNone).
+			SetFieldTextColor(tcell.ColorWhite).
+			SetLabel("Team: ").
+			SetLabelColor(tcell.ColorLightCyan).
+			SetPlaceholder("name to save it under").
+			SetPlaceholderTextColor(tcell.ColorGray).
+			SetText(a.team.Name),
+		members:  tview.NewTable().SetFixed(1, 0).SetSelectable(true, false),
+		analysis: tview.NewTextView().SetTextColor(tcell.ColorYellow),
+		codeInput: tview.NewInputField().
+			SetFieldBackgroundColor(tcell.ColorNone).
+			SetFieldTextColor(tcell.ColorWhite).
+			SetLabel("Code: ").
+			SetLabelColor(tcell.ColorLightCyan).
+			SetPlaceholder("paste a team code or the path of a team JSON file").
+			SetPlaceholderTextColor(tcell.ColorGray),
+		footer: tview.NewTextView().SetTextColor(tcell.ColorSilver),
+	}
+
+	v.saved.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.saved.SetTitle("Saved teams").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	v.members.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.members.SetTitle("Members").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	v.members.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite))
+	v.analysis.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.analysis.SetTitle("Composition").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+
+	v.nameInput.SetChangedFunc(func(text string) {
+		a.team.Name = strings.TrimSpace(text)
+		v.showCode()
+	})
+	v.members.SetSelectedFunc(func(row, column int) {
+		if row > 0 && row <= len(a.team.Members) {
+			a.pages.RemovePage(teamPageName)
+			a.teamView = nil
+			a.loadDigimonDetail(a.team.Members[row-1].ID)
+		}
+	})
+	v.members.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyDelete, tcell.KeyBackspace, tcell.KeyBackspace2:
+			row, _ := v.members.GetSelection()
+			a.team.Remove(row - 1)
+			a.teamGen++
+			v.refresh()
+			return nil
+		}
+		return event
+	})
+	v.codeInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			v.importTeam(v.codeInput.GetText())
+		}
+	})
+
+	saveButton := tview.NewButton("Save")
+	saveButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	saveButton.SetSelectedFunc(v.save)
+	exportButton := tview.NewButton("Export JSON")
+	exportButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	exportButton.SetSelectedFunc(v.exportJSON)
+	clearButton := tview.NewButton("Clear")
+	clearButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorRed))
+	clearButton.SetSelectedFunc(func() {
+		a.team = team.Team{}
+		a.teamGen++
+		v.nameInput.SetText("")
+		v.refresh()
+	})
+
+	buttons := tview.NewFlex().
+		AddItem(saveButton, 8, 0, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(exportButton, 13, 0, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(clearButton, 9, 0, false).
+		AddItem(nil, 0, 1, false)
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("Ctrl+A: add the shown Digimon | Tab: next control | Enter: open member or load saved team | Delete: remove member | Esc: back")
+
+	center := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.nameInput, 1, 0, false).
+		AddItem(v.members, 0, 1, true)
+	body := tview.NewFlex().
+		AddItem(v.saved, 24, 0, false).
+		AddItem(center, 0, 3, true).
+		AddItem(v.analysis, 0, 2, false)
+	page := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(v.codeInput, 1, 0, false).
+		AddItem(buttons, 1, 0, false).
+		AddItem(v.footer, 1, 0, false).
+		AddItem(help, 1, 0, false)
+
+	order := []tview.Primitive{v.members, v.nameInput, v.codeInput, saveButton, exportButton, clearButton, v.saved}
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.RemovePage(teamPageName)
+			a.teamView = nil
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			a.cycleFocus(order, event.Key() == tcell.KeyBacktab)
+			return nil
+		}
+		return event
+	})
+
+	a.teamView = v
+	v.refreshSaved()
+	v.refresh()
+	a.pages.AddPage(teamPageName, page, true, true)
+	a.SetFocus(v.members)
+}
+
+// refresh redraws the members, analysis and code of the team.
+func (v *teamView) refresh() {
+	current := v.app.team
+	v.members.Clear()
+	for column, title := range teamColumns {
+		v.members.SetCell(0, column, tview.NewTableCell(title).SetTextColor(tcell.ColorOrange).SetSelectable(false))
+	}
+	if len(current.Members) == 0 {
+		v.members.SetCell(1, 1, tview.NewTableCell("Press Ctrl+A on the main screen to add the shown Digimon").
+			SetTextColor(tcell.ColorSilver).SetSelectable(false))
+	}
+	for i, member := range current.Members {
+		name := member.Name
+		if !member.Resolved() {
+			name = member.Label()
+		}
+		v.members.SetCell(i+1, 0, tview.NewTableCell(fmt.Sprint(i+1)).SetTextColor(tcell.ColorSilver))
+		v.members.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(name)).SetTextColor(tcell.ColorGold))
+		v.members.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(strings.Join(member.Levels, ", "))))
+		v.members.SetCell(i+1, 3, tview.NewTableCell(tview.Escape(strings.Join(member.Attributes, ", "))).
+			SetTextColor(tcell.ColorLightCyan))
+		v.members.SetCell(i+1, 4, tview.NewTableCell(tview.Escape(strings.Join(member.Fields, ", "))).SetExpansion(1))
+	}
+	if row, _ := v.members.GetSelection(); row > len(current.Members) {
+		v.members.Select(max(len(current.Members), 1), 0)
+	}
+
+	v.analysis.SetText(tview.Escape(team.Analyze(current).Text()))
+	v.showCode()
+}
+
+// showCode puts the code of the team into the code input.
+func (v *teamView) showCode() {
+	if len(v.app.team.Members) == 0 {
+		v.codeInput.SetText("")
+		return
+	}
+	v.codeInput.SetText(team.Encode(v.app.team))
+}
+
+func (v *teamView) refreshSaved() {
+	v.saved.Clear()
+	if v.app.teams == nil {
+		return
+	}
+	for _, saved := range v.app.teams.All() {
+		v.saved.AddItem(tview.Escape(saved.Name), "", 0, func() {
+			v.load(saved)
+		})
+	}
+}
+
+func (v *teamView) setFooter(text string, color tcell.Color) {
+	v.footer.SetText(text).SetTextColor(color)
+}
+
+// load makes t the team being built and looks up its unresolved members.
+func (v *teamView) load(t team.Team) {
+	a := v.app
+	// The lookups fill in t off the UI goroutine, the shown team is a copy
+	a.team = t
+	a.team.Members = append([]team.Member(nil), t.Members...)
+	a.teamGen++
+	gen := a.teamGen
+	v.nameInput.SetText(t.Name)
+	v.refresh()
+
+	unresolved := false
+	for _, member := range t.Members {
+		unresolved = unresolved || !member.Resolved()
+	}
+	if !unresolved {
+		v.setFooter(fmt.Sprintf("Loaded %s", t.Name), tcell.ColorSilver)
+		return
+	}
+
+	v.setFooter("Looking up the members...", tcell.ColorSilver)
+	a.status.requestStarted()
+	go func() {
+		err := t.Resolve(a.lookupDetail)
+		a.status.requestFinished(err)
+		a.QueueUpdateDraw(func() {
+			// The team may have been replaced or edited in the meantime
+			if a.teamView != v || a.teamGen != gen {
+				return
+			}
+			a.team.Members = t.Members
+			v.refresh()
+			if err != nil {
+				slog.Warn("Failed to resolve team members", "team", t.Name, "error", err)
+				v.setFooter(err.Error(), tcell.ColorRed)
+				return
+			}
+			v.setFooter(fmt.Sprintf("Loaded %s", t.Name), tcell.ColorSilver)
+		})
+	}()
+}
+
+// importTeam loads a team from a code or a JSON file.
+func (v *teamView) importTeam(input string) {
+	if strings.TrimSpace(input) == "" || input == team.Encode(v.app.team) {
+		return
+	}
+	t, err := team.Import(input)
+	if err != nil {
+		v.setFooter(err.Error(), tcell.ColorRed)
+		return
+	}
+	v.load(t)
+}
+
+// save stores the team in the saved teams file under its name.
+func (v *teamView) save() {
+	a := v.app
+	if a.teams == nil {
+		v.setFooter("No teams file configured, see --teams", tcell.ColorRed)
+		return
+	}
+	err := a.teams.Put(a.team)
+	if err == nil {
+		err = a.teams.Save()
+	}
+	if err != nil {
+		v.setFooter(err.Error(), tcell.ColorRed)
+		return
+	}
+	v.setFooter(fmt.Sprintf("Saved %s to %s", a.team.Name, a.teams.Path()), tcell.ColorSilver)
+	v.refreshSaved()
+}
+
+// exportJSON writes the team to the export directory.
+func (v *teamView) exportJSON() {
+	a := v.app
+	if len(a.team.Members) == 0 {
+		v.setFooter("The team is empty", tcell.ColorRed)
+		return
+	}
+	name := "team"
+	if a.team.Name != "" {
+		name = export.Slug(a.team.Name)
+	}
+	dir := a.exportDir
+	if dir == "" {
+		dir = export.DefaultDir
+	}
+	path := filepath.Join(dir, "teams", name+".json")
+	if err := team.Export(path, a.team); err != nil {
+		v.setFooter(err.Error(), tcell.ColorRed)
+		return
+	}
+	v.setFooter("Exported the team to "+path, tcell.ColorSilver)
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/releasedate"
+	"github.com/sangnt1552314/digimontex/internal/timeline"
+)
+
+const timelinePageName = "timeline"
+
+// listSorts are the orders the list panel's sort button cycles through
+var listSorts = []struct {
+	label string
+	order timeline.Order
+	desc  bool
+}{
+	{"Sort: ID", timeline.ByID, false},
+	{"Sort: Oldest", timeline.ByRelease, false},
+	{"Sort: Newest", timeline.ByRelease, true},
+	{"Sort: Name", timeline.ByName, false},
+}
+
+// localList reports whether the list panel shows the local data, which it
+// does for a release range or an order the API does not offer.
+func (a *App) localList() bool {
+	return !a.listQuery.IsZero()
+}
+
+// listSource returns the function the list panel fetches pages with. It is
+// taken on the UI goroutine so the fetch does not race with filter changes.
+func (a *App) listSource() func(models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+	if !a.localList() {
+		return a.service.GetDigimonList
+	}
+
+	query := a.listQuery
+	details := a.localDetails()
+	return func(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+		query.Name = params.Name
+		return timeline.Page(timeline.Search(details, query), params.Page, params.PageSize), nil
+	}
+}
+
+// setupListFilters returns the release range input and sort button of the
+// list panel.
+func (a *App) setupListFilters() (*tview.InputField, *tview.Button) {
+	rangeInput := tview.NewInputField().
+		SetFieldBackgroundColor(tcell.ColorNone).
+		SetFieldTextColor(tcell.ColorWhite).
+		SetLabel("Released: ").
+		SetLabelColor(tcell.ColorLightCyan).
+		SetPlaceholder("1997..1999").
+		SetPlaceholderTextColor(tcell.ColorGray)
+	rangeInput.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		r, err := releasedate.ParseRange(rangeInput.GetText())
+		if err != nil {
+			a.status.showError(err)
+			return
+		}
+		rangeInput.SetText(r.String())
+		if r != a.listQuery.Range {
+			a.listQuery.Range = r
+			a.loadPage(0)
+		}
+	})
+
+	sortIndex := 0
+	sortButton := tview.NewButton(listSorts[0].label)
+	sortButton.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	sortButton.SetSelectedFunc(func() {
+		sortIndex = (sortIndex + 1) % len(listSorts)
+		sortButton.SetLabel(listSorts[sortIndex].label)
+		a.listQuery.Order = listSorts[sortIndex].order
+		a.listQuery.Descending = listSorts[sortIndex].desc
+		a.loadPage(0)
+	})
+
+	return rangeInput, sortButton
+}
+
+// timelineView is the page that groups the local Digimon by release year.
+type timelineView struct {
+	app        *App
+	tree       *tview.TreeView
+	rangeInput *tview.InputField
+	order      *tview.Button
+	summary    *tview.TextView
+	query      timeline.Query
+}
+
+// showTimeline opens the release timeline of the synced dataset and the
+// Digimon fetched this session.
+func (a *App) showTimeline() {
+	if a.pages.HasPage(timelinePageName) {
+		return
+	}
+
+	v := &timelineView{
+		app:  a,
+		tree: tview.NewTreeView(),
+		rangeInput: tview.NewInputField().
+			SetFieldBackgroundColor(tcell.ColorNone).
+			SetFieldTextColor(tcell.ColorWhite).
+			SetLabel("Range: ").
+			SetLabelColor(tcell.ColorLightCyan).
+			SetPlaceholder("1997..1999").
+			SetPlaceholderTextColor(tcell.ColorGray),
+		order:   tview.NewButton("Oldest first"),
+		summary: tview.NewTextView().SetTextColor(tcell.ColorSilver),
+		query:   timeline.Query{Order: timeline.ByRelease},
+	}
+
+	v.tree.SetBorder(true).SetBorderColor(tcell.ColorDarkCyan)
+	v.tree.SetTitle("Release timeline").SetTitleAlign(tview.AlignLeft).SetTitleColor(tcell.ColorOrange)
+	v.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		if id, ok := node.GetReference().(int); ok {
+			a.pages.RemovePage(timelinePageName)
+			a.loadDigimonDetail(id)
+			return
+		}
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	v.rangeInput.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		r, err := releasedate.ParseRange(v.rangeInput.GetText())
+		if err != nil {
+			v.summary.SetText(err.Error()).SetTextColor(tcell.ColorRed)
+			return
+		}
+		v.rangeInput.SetText(r.String())
+		v.query.Range = r
+		v.refresh()
+	})
+
+	v.order.SetStyle(tcell.StyleDefault.Foreground(tcell.ColorGreen))
+	v.order.SetSelectedFunc(func() {
+		v.query.Descending = !v.query.Descending
+		if v.query.Descending {
+			v.order.SetLabel("Newest first")
+		} else {
+			v.order.SetLabel("Oldest first")
+		}
+		v.refresh()
+	})
+
+	help := tview.NewTextView().SetTextColor(tcell.ColorSilver).
+		SetText("Tab: next control | Enter: open Digimon or fold year | Esc: back")
+	controls := tview.NewFlex().
+		AddItem(v.rangeInput, 0, 1, false).
+		AddItem(v.order, 14, 0, false)
+	page := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(controls, 1, 0, false).
+		AddItem(v.summary, 1, 0, false).
+		AddItem(v.tree, 0, 1, true).
+		AddItem(help, 1, 0, false)
+
+	order := []tview.Primitive{v.tree, v.rangeInput, v.order}
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.pages.RemovePage(timelinePageName)
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			a.cycleFocus(order, event.Key() == tcell.KeyBacktab)
+			return nil
+		}
+		return event
+	})
+
+	v.refresh()
+	a.pages.AddPage(timelinePageName, page, true, true)
+	a.SetFocus(v.tree)
+}
+
+// refresh rebuilds the tree for the current range and order.
+func (v *timelineView) refresh() {
+	details := v.app.localDetails()
+	entries := timeline.Search(details, v.query)
+	years := timeline.Group(entries)
+
+	root := tview.NewTreeNode(fmt.Sprintf("%d Digimon", len(entries))).
+		SetColor(tcell.ColorWhite).SetSelectable(false)
+	for _, year := range years {
+		yearNode := tview.NewTreeNode(fmt.Sprintf("%s (%d)", year.Label(), len(year.Entries))).
+			SetColor(tcell.ColorOrange)
+		for _, entry := range year.Entries {
+			text := entry.Digimon.Name
+			if entry.Released.Precision() > releasedate.Year {
+				text += " - " + entry.Released.Text()
+			}
+			yearNode.AddChild(tview.NewTreeNode(text).
+				SetReference(entry.Digimon.ID).
+				SetColor(tcell.ColorGold))
+		}
+		root.AddChild(yearNode)
+	}
+	v.tree.SetRoot(root).SetTopLevel(1)
+	if children := root.GetChildren(); len(children) > 0 {
+		v.tree.SetCurrentNode(children[0])
+	}
+
+	summary := fmt.Sprintf("%d of %d Digimon in %d years", len(entries), len(details), len(years))
+	if v.app.store == nil || v.app.store.Len() == 0 {
+		summary += "; only Digimon viewed this session, run `digimontex sync` for the whole dataset"
+	}
+	v.summary.SetText(summary).SetTextColor(tcell.ColorSilver)
+}
@@ -0,0 +1,209 @@
+// Package card composes Digimon details into printable cards: artwork,
+// name, levels, types, attributes, field icons and the first skills. Cards
+// are written as PNG or laid out on PDF sheets.
+package card
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+// Cards are drawn at 10 pixels per millimetre of a 63 x 88 mm trading card
+const (
+	Width  = 630
+	Height = 880
+
+	border   = 14
+	padding  = 32
+	maxSkill = 3
+
+	// footerY is the footer's baseline, skillsBottom the lowest baseline
+	// of the skills above it
+	footerY      = Height - border - 8
+	skillsBottom = footerY - 18
+)
+
+var (
+	background = color.RGBA{0x18, 0x1e, 0x24, 0xff}
+	panel      = color.RGBA{0x24, 0x2c, 0x35, 0xff}
+	textColor  = color.RGBA{0xd8, 0xde, 0xe4, 0xff}
+	mutedColor = color.RGBA{0x8b, 0x94, 0x9e, 0xff}
+	goldColor  = color.RGBA{0xe3, 0xb3, 0x41, 0xff}
+	skillColor = color.RGBA{0x58, 0xa6, 0xff, 0xff}
+
+	// attributeColors frame the card by the Digimon's first attribute
+	attributeColors = map[string]color.RGBA{
+		"Vaccine":  {0x3b, 0x82, 0xf6, 0xff},
+		"Data":     {0x22, 0xc5, 0x5e, 0xff},
+		"Virus":    {0xa8, 0x55, 0xf7, 0xff},
+		"Free":     {0xf5, 0x9e, 0x0b, 0xff},
+		"Variable": {0xef, 0x44, 0x44, 0xff},
+	}
+	defaultFrame = color.RGBA{0x1f, 0x7a, 0x8c, 0xff}
+)
+
+// ImageSource loads artwork and field icons, services.Client implements it.
+type ImageSource interface {
+	GetImageByURL(imageUrl string) (image.Image, error)
+}
+
+// FrameColor returns the border colour of a card for its attributes.
+func FrameColor(attributes []string) color.RGBA {
+	for _, attribute := range attributes {
+		if c, ok := attributeColors[attribute]; ok {
+			return c
+		}
+	}
+	return defaultFrame
+}
+
+// Render draws the card of digimon. Images that cannot be loaded are left
+// out rather than failing the card.
+func Render(digimon *models.DigimonDetail, images ImageSource) *image.RGBA {
+	view := viewmodel.NewDigimonView(digimon)
+	card := image.NewRGBA(image.Rect(0, 0, Width, Height))
+
+	fill(card, card.Bounds(), FrameColor(view.Attributes))
+	fill(card, card.Bounds().Inset(border), background)
+
+	contentWidth := Width - 2*padding
+
+	// Images are loaded before the faces are locked, so renders do not
+	// wait on each other's downloads
+	artwork := load(images, view.ImageURL)
+	icons := make([]image.Image, len(view.Fields))
+	for i, field := range view.Fields {
+		icons[i] = load(images, field.ImageURL)
+	}
+
+	facesMutex.Lock()
+	defer facesMutex.Unlock()
+
+	// Header: name and ID
+	id := fmt.Sprintf("#%04d", view.ID)
+	idWidth := textWidth(largeFace, id)
+	drawText(card, nameFace, goldColor, padding, 74, contentWidth-idWidth-12, view.Name)
+	drawText(card, largeFace, mutedColor, Width-padding-idWidth, 74, 0, id)
+
+	// Artwork
+	art := image.Rect(padding, 96, Width-padding, 456)
+	fill(card, art, panel)
+	if artwork != nil {
+		drawFitted(card, art.Inset(8), artwork)
+	} else {
+		text := "No image"
+		drawText(card, largeFace, mutedColor, art.Min.X+(art.Dx()-textWidth(largeFace, text))/2, art.Min.Y+art.Dy()/2, 0, text)
+	}
+	if view.XAntibody {
+		badge := "X-Antibody"
+		bw := textWidth(badgeFace, badge) + 16
+		fill(card, image.Rect(art.Max.X-bw-8, art.Min.Y+8, art.Max.X-8, art.Min.Y+36), FrameColor(nil))
+		drawText(card, badgeFace, textColor, art.Max.X-bw, art.Min.Y+29, 0, badge)
+	}
+
+	// Levels, types and attributes
+	y := 492
+	for _, row := range [][2]string{
+		{"Level", view.LevelsText()},
+		{"Type", view.TypesText()},
+		{"Attribute", view.AttributesText()},
+	} {
+		drawText(card, labelFace, mutedColor, padding, y, 0, row[0])
+		drawText(card, valueFace, textColor, padding+110, y, contentWidth-110, row[1])
+		y += 30
+	}
+
+	// Field icons with their names, wrapped over as many rows as needed
+	y += 4
+	x := padding
+	for i, field := range view.Fields {
+		width := 30 + textWidth(smallFace, field.Name) + 18
+		if x+width > Width-padding && x > padding {
+			x, y = padding, y+34
+		}
+		if icons[i] != nil {
+			drawFitted(card, image.Rect(x, y, x+26, y+26), icons[i])
+		}
+		drawText(card, smallFace, textColor, x+30, y+19, Width-padding-x-30, field.Name)
+		x += width
+	}
+	if len(view.Fields) > 0 {
+		y += 34
+	}
+
+	// The first skills, one wrapped description line or two each
+	y += 8
+	fill(card, image.Rect(padding, y, Width-padding, y+2), FrameColor(view.Attributes))
+	y += 30
+	for i, skill := range view.Skills {
+		// A skill needs room for its name and a description line
+		if i == maxSkill || y+22 > skillsBottom {
+			break
+		}
+		name := skill.Name
+		if skill.Translation != "" && !strings.EqualFold(skill.Translation, skill.Name) {
+			name += " (" + skill.Translation + ")"
+		}
+		drawText(card, skillFace, skillColor, padding, y, contentWidth, name)
+		y += 22
+		lines := min(2, (skillsBottom-y)/20+1)
+		for _, line := range wrap(smallFace, skill.Description, contentWidth, lines) {
+			drawText(card, smallFace, textColor, padding, y, contentWidth, line)
+			y += 20
+		}
+		y += 10
+	}
+	if len(view.Skills) == 0 {
+		drawText(card, smallFace, mutedColor, padding, y, contentWidth, "No skills available")
+	}
+
+	// Footer
+	drawText(card, footerFace, mutedColor, padding, footerY, contentWidth, "Released "+view.ReleaseDate)
+
+	return card
+}
+
+// WritePNG writes a rendered card as PNG.
+func WritePNG(w io.Writer, card image.Image) error {
+	if err := png.Encode(w, card); err != nil {
+		return fmt.Errorf("failed to encode card: %w", err)
+	}
+	return nil
+}
+
+func load(images ImageSource, url string) image.Image {
+	if images == nil || url == "" {
+		return nil
+	}
+	img, err := images.GetImageByURL(url)
+	if err != nil {
+		slog.Warn("Card image left out", "url", url, "error", err)
+		return nil
+	}
+	return img
+}
+
+func fill(dst draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(dst, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawFitted draws img shrunk or enlarged to fit r, centred and blended
+// over what is already there.
+func drawFitted(dst draw.Image, r image.Rectangle, img image.Image) {
+	bounds := img.Bounds()
+	width, height := imaging.FitSize(bounds.Dx(), bounds.Dy(), r.Dx(), r.Dy())
+	scaled := imaging.Resize(img, width, height)
+
+	at := image.Pt(r.Min.X+(r.Dx()-width)/2, r.Min.Y+(r.Dy()-height)/2)
+	draw.Draw(dst, image.Rectangle{Min: at, Max: at.Add(image.Pt(width, height))}, scaled, scaled.Bounds().Min, draw.Over)
+}
@@ -0,0 +1,230 @@
+package card
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+type fakeImages map[string]image.Image
+
+func (f fakeImages) GetImageByURL(imageUrl string) (image.Image, error) {
+	if img, ok := f[imageUrl]; ok {
+		return img, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func solid(width, height int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b, a := c.RGBA()
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+	}
+	return img
+}
+
+func greymon(t *testing.T) *models.DigimonDetail {
+	t.Helper()
+
+	var digimon models.DigimonDetail
+	err := json.Unmarshal([]byte(`{
+		"id": 4,
+		"name": "Greymon",
+		"images": [{"href": "art"}],
+		"levels": [{"level": "Adult"}],
+		"types": [{"type": "Dinosaur"}],
+		"attributes": [{"attribute": "Vaccine"}],
+		"fields": [{"field": "Nature Spirits", "image": "ns"}, {"field": "Virus Busters", "image": "missing"}],
+		"skills": [
+			{"skill": "Mega Flame", "translation": "Mega Flame", "description": "Spits a great fireball."},
+			{"skill": "Great Antler", "description": "Rams with its horns."}
+		],
+		"releaseDate": "1997"
+	}`), &digimon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &digimon
+}
+
+func TestRender(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	card := Render(greymon(t), fakeImages{
+		"art": solid(200, 100, red),
+		"ns":  solid(10, 10, color.RGBA{0, 0xff, 0, 0xff}),
+	})
+
+	if card.Bounds() != image.Rect(0, 0, Width, Height) {
+		t.Fatalf("bounds = %v", card.Bounds())
+	}
+	if got := card.RGBAAt(2, 2); got != attributeColors["Vaccine"] {
+		t.Errorf("frame = %v, want the Vaccine colour", got)
+	}
+	// The 2:1 artwork is centred in the art panel
+	if got := card.RGBAAt(Width/2, 276); got != red {
+		t.Errorf("artwork centre = %v, want red", got)
+	}
+	if got := card.RGBAAt(Width/2, 110); got != panel {
+		t.Errorf("above the artwork = %v, want the panel", got)
+	}
+
+	// The name is drawn in gold in the header
+	gold := 0
+	for y := 40; y < 80; y++ {
+		for x := padding; x < Width/2; x++ {
+			if card.RGBAAt(x, y) == goldColor {
+				gold++
+			}
+		}
+	}
+	if gold == 0 {
+		t.Error("the name was not drawn")
+	}
+
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, card); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Errorf("PNG does not decode: %v", err)
+	}
+}
+
+func TestRenderWithoutData(t *testing.T) {
+	card := Render(&models.DigimonDetail{}, nil)
+	if got := card.RGBAAt(2, 2); got != defaultFrame {
+		t.Errorf("frame = %v, want the default colour", got)
+	}
+}
+
+// blockingImages holds every image load until release is closed.
+type blockingImages struct {
+	loading chan struct{}
+	release chan struct{}
+}
+
+func (b blockingImages) GetImageByURL(imageUrl string) (image.Image, error) {
+	b.loading <- struct{}{}
+	<-b.release
+	return nil, errors.New("not found")
+}
+
+func TestRenderDoesNotWaitOnOtherLoads(t *testing.T) {
+	images := blockingImages{loading: make(chan struct{}, 10), release: make(chan struct{})}
+	first, second := greymon(t), greymon(t)
+	slow := make(chan *image.RGBA)
+	go func() {
+		slow <- Render(first, images)
+	}()
+	<-images.loading
+
+	done := make(chan struct{})
+	go func() {
+		Render(second, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("a render waited for another render's image load")
+	}
+	close(images.release)
+	<-slow
+}
+
+func TestSkillsStayAboveFooter(t *testing.T) {
+	// Six rows of fields put the first skill just above the footer
+	digimon := greymon(t)
+	var fields []string
+	for i := range 6 {
+		fields = append(fields, `{"field": "Nature Spirits and Virus Busters `+strconv.Itoa(i)+`"}`)
+	}
+	digimon.Fields = nil
+	if err := json.Unmarshal([]byte(`{"fields": [`+strings.Join(fields, ",")+`]}`), digimon); err != nil {
+		t.Fatal(err)
+	}
+	long := strings.Repeat("A very long description. ", 20)
+	for i := range digimon.Skills {
+		digimon.Skills[i].Description = long
+	}
+
+	withSkills := Render(digimon, nil)
+	digimon.Skills = nil
+	withoutSkills := Render(digimon, nil)
+
+	// The footer band looks the same with or without skills above it
+	for y := footerY - 14; y < Height-border; y++ {
+		for x := border; x < Width-border; x++ {
+			if withSkills.RGBAAt(x, y) != withoutSkills.RGBAAt(x, y) {
+				t.Fatalf("skills drawn over the footer at (%d, %d)", x, y)
+			}
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	face := smallFace
+	lines := wrap(face, strings.Repeat("fire ", 100), 200, 2)
+	if len(lines) != 2 || !strings.HasSuffix(lines[1], "…") {
+		t.Errorf("lines = %q, want two with the last truncated", lines)
+	}
+	for _, line := range lines {
+		if textWidth(face, line) > 200 {
+			t.Errorf("%q is wider than 200 pixels", line)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	cards := make([]image.Image, 5)
+	for i := range cards {
+		cards[i] = solid(63, 88, color.White)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, cards, 4); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if got := bytes.Count(pdf, []byte("/Type /Page ")); got != 2 {
+		t.Errorf("%d pages, want 2", got)
+	}
+	if got := bytes.Count(pdf, []byte("/Subtype /Image")); got != 5 {
+		t.Errorf("%d images, want 5", got)
+	}
+
+	// Every cross-reference entry points at its object
+	xref := regexp.MustCompile(`(?s)xref\n0 (\d+)\n0000000000 65535 f \n(.*)trailer`).FindSubmatch(pdf)
+	if xref == nil {
+		t.Fatal("no xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(xref[2], -1)
+	if size, _ := strconv.Atoi(string(xref[1])); len(entries) != size-1 {
+		t.Fatalf("%d xref entries for size %s", len(entries), xref[1])
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, pdf[offset:offset+10])
+		}
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if offset, _ := strconv.Atoi(string(startxref[1])); !bytes.HasPrefix(pdf[offset:], []byte("xref\n")) {
+		t.Error("startxref does not point at the xref table")
+	}
+}
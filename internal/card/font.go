@@ -0,0 +1,107 @@
+package card
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// The Go fonts are compiled into the binary, so cards look the same on
+// every machine
+var (
+	regularFont = mustParse(goregular.TTF)
+	boldFont    = mustParse(gobold.TTF)
+)
+
+// Faces are made once per font and size. They cache glyph data and are not
+// safe for concurrent use, so Render holds facesMutex while it draws, after
+// loading the images.
+var (
+	facesMutex sync.Mutex
+
+	nameFace  = newFace(boldFont, 40)
+	labelFace = newFace(boldFont, 20)
+	skillFace = newFace(boldFont, 19)
+	badgeFace = newFace(boldFont, 18)
+
+	largeFace  = newFace(regularFont, 22)
+	valueFace  = newFace(regularFont, 20)
+	smallFace  = newFace(regularFont, 16)
+	footerFace = newFace(regularFont, 14)
+)
+
+func mustParse(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func newFace(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+// textWidth returns the width of text in pixels.
+func textWidth(face font.Face, text string) int {
+	return font.MeasureString(face, text).Ceil()
+}
+
+// drawText draws text with its baseline at y, truncated with an ellipsis
+// to maxWidth pixels, and returns the x after the last glyph.
+func drawText(dst draw.Image, face font.Face, c color.Color, x, y, maxWidth int, text string) int {
+	text = truncate(face, text, maxWidth)
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+	return d.Dot.X.Ceil()
+}
+
+func truncate(face font.Face, text string, maxWidth int) string {
+	if maxWidth <= 0 || textWidth(face, text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && textWidth(face, string(runes)+"…") > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + "…"
+}
+
+// wrap breaks text into at most maxLines lines of maxWidth pixels. The last
+// line is truncated when text does not fit.
+func wrap(face font.Face, text string, maxWidth, maxLines int) []string {
+	words := strings.Fields(text)
+	var lines []string
+	for len(words) > 0 && len(lines) < maxLines {
+		line := words[0]
+		used := 1
+		for used < len(words) && textWidth(face, line+" "+words[used]) <= maxWidth {
+			line += " " + words[used]
+			used++
+		}
+		words = words[used:]
+		if len(lines) == maxLines-1 && len(words) > 0 {
+			line = truncate(face, line+" "+strings.Join(words, " "), maxWidth)
+			words = nil
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
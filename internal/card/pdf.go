@@ -0,0 +1,153 @@
+package card
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+)
+
+// A4 in PDF points, with the printable margin kept clear
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	pageMargin = 18.0
+	cardGap    = 6.0
+
+	// cardWidthPt and cardHeightPt are 63 x 88 mm, the printed card size
+	cardWidthPt  = 63 / 25.4 * 72
+	cardHeightPt = 88 / 25.4 * 72
+)
+
+// DefaultPerPage fits cards at their real size on an A4 page
+const DefaultPerPage = 9
+
+// WritePDF lays cards out on A4 pages, perPage per page in a grid. Cards
+// are printed at 63 x 88 mm, or smaller when that many do not fit.
+func WritePDF(w io.Writer, cards []image.Image, perPage int) error {
+	if len(cards) == 0 {
+		return fmt.Errorf("no cards to write")
+	}
+	perPage = max(perPage, 1)
+
+	// Grid with roughly square cells for the page, then the largest card
+	// size that fits a cell
+	cols := max(1, int(math.Round(math.Sqrt(float64(perPage)*(pageWidth*cardHeightPt)/(pageHeight*cardWidthPt)))))
+	cols = min(cols, perPage)
+	rows := (perPage + cols - 1) / cols
+	cellWidth := (pageWidth - 2*pageMargin) / float64(cols)
+	cellHeight := (pageHeight - 2*pageMargin) / float64(rows)
+	scale := min(1, (cellWidth-cardGap)/cardWidthPt, (cellHeight-cardGap)/cardHeightPt)
+	width, height := cardWidthPt*scale, cardHeightPt*scale
+
+	// Centre the grid on the page
+	left := (pageWidth - float64(cols)*cellWidth) / 2
+	top := pageHeight - (pageHeight-float64(rows)*cellHeight)/2
+
+	pdf := &pdfWriter{w: w}
+	pages := (len(cards) + perPage - 1) / perPage
+
+	// Objects 1 and 2 are the catalog and the page tree, then every page
+	// takes a page, a content stream and one image object per card
+	pageIDs := make([]int, pages)
+	next := 3
+	for page := range pages {
+		pageIDs[page] = next
+		next += 2 + min(perPage, len(cards)-page*perPage)
+	}
+
+	pdf.header()
+	pdf.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := ""
+	for _, id := range pageIDs {
+		kids += fmt.Sprintf("%d 0 R ", id)
+	}
+	pdf.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, pages))
+
+	for page, pageID := range pageIDs {
+		onPage := cards[page*perPage : min(len(cards), (page+1)*perPage)]
+
+		var content bytes.Buffer
+		resources := ""
+		for i := range onPage {
+			col, row := i%cols, i/cols
+			x := left + float64(col)*cellWidth + (cellWidth-width)/2
+			y := top - float64(row+1)*cellHeight + (cellHeight-height)/2
+			fmt.Fprintf(&content, "q %.2f 0 0 %.2f %.2f %.2f cm /Card%d Do Q\n", width, height, x, y, i)
+			resources += fmt.Sprintf("/Card%d %d 0 R ", i, pageID+2+i)
+		}
+
+		pdf.object(pageID, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Contents %d 0 R /Resources << /XObject << %s>> >> >>",
+			pageWidth, pageHeight, pageID+1, resources))
+		pdf.stream(pageID+1, "", content.Bytes())
+
+		for i, card := range onPage {
+			var data bytes.Buffer
+			if err := jpeg.Encode(&data, card, &jpeg.Options{Quality: 92}); err != nil {
+				return fmt.Errorf("failed to encode card: %w", err)
+			}
+			bounds := card.Bounds()
+			pdf.stream(pageID+2+i, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode ",
+				bounds.Dx(), bounds.Dy()), data.Bytes())
+		}
+	}
+
+	pdf.trailer(next - 1)
+	return pdf.err
+}
+
+// pdfWriter writes numbered objects and remembers their offsets for the
+// cross-reference table. The first error sticks and stops further writes.
+type pdfWriter struct {
+	w       io.Writer
+	offset  int64
+	offsets map[int]int64
+	err     error
+}
+
+func (p *pdfWriter) write(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(p.w, format, args...)
+	p.offset += int64(n)
+	p.err = err
+}
+
+func (p *pdfWriter) writeBytes(data []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(data)
+	p.offset += int64(n)
+	p.err = err
+}
+
+func (p *pdfWriter) header() {
+	p.offsets = make(map[int]int64)
+	// The binary comment marks the file as binary for transfer tools
+	p.write("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+}
+
+func (p *pdfWriter) object(id int, body string) {
+	p.offsets[id] = p.offset
+	p.write("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (p *pdfWriter) stream(id int, dict string, data []byte) {
+	p.offsets[id] = p.offset
+	p.write("%d 0 obj\n<< %s/Length %d >>\nstream\n", id, dict, len(data))
+	p.writeBytes(data)
+	p.write("\nendstream\nendobj\n")
+}
+
+func (p *pdfWriter) trailer(last int) {
+	xref := p.offset
+	p.write("xref\n0 %d\n0000000000 65535 f \n", last+1)
+	for id := 1; id <= last; id++ {
+		p.write("%010d 00000 n \n", p.offsets[id])
+	}
+	p.write("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", last+1, xref)
+}
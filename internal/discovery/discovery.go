@@ -0,0 +1,234 @@
+// Package discovery picks Digimon to look at: the Digimon of the day, the
+// same for everyone on a date, and random Digimon filtered by level and
+// attribute that do not repeat within a session.
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// DefaultMaxID is the upper bound of the ID range when neither the API nor
+// the local data tell the real one.
+const DefaultMaxID = 1400
+
+// DailyStart is the start option for the Digimon of the day.
+const DailyStart = "daily"
+
+// DateLayout is how dates are written for the Digimon of the day.
+const DateLayout = "2006-01-02"
+
+// listPageSize is the page size candidates are fetched with
+const listPageSize = 100
+
+// Levels and Attributes are the values the Digi-API filters by.
+var (
+	Levels     = []string{"Baby I", "Baby II", "Child", "Adult", "Perfect", "Ultimate", "Armor", "Hybrid", "Unknown"}
+	Attributes = []string{"Vaccine", "Data", "Virus", "Free", "Variable", "No Data", "Unknown"}
+)
+
+// ErrNoCandidates is returned when no Digimon matches a filter.
+var ErrNoCandidates = errors.New("no Digimon to pick from")
+
+// Daily returns the ID of the Digimon of day's date, between 1 and maxID.
+// The time of day and the location do not matter, only the calendar date.
+func Daily(day time.Time, maxID int) int {
+	if maxID < 1 {
+		maxID = DefaultMaxID
+	}
+	h := fnv.New64a()
+	h.Write([]byte(day.Format(DateLayout)))
+	return int(h.Sum64()%uint64(maxID)) + 1
+}
+
+// Start is the Digimon the TUI opens with.
+type Start struct {
+	Daily bool
+	ID    int
+	Name  string
+}
+
+// ParseStart reads "daily" (or "") as the Digimon of the day, a number as an
+// ID and anything else as an exact name.
+func ParseStart(s string) Start {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, DailyStart) {
+		return Start{Daily: true}
+	}
+	if id, err := strconv.Atoi(s); err == nil && id > 0 {
+		return Start{ID: id}
+	}
+	return Start{Name: s}
+}
+
+// ListFunc fetches a page of the Digimon list, like
+// services.Service.GetDigimonList.
+type ListFunc func(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error)
+
+// MaxID returns the highest Digimon ID. IDs run from 1 without gaps, so it
+// is the number of Digimon the list reports. When the list fails, it is the
+// highest ID in details, or DefaultMaxID, along with the error.
+func MaxID(list ListFunc, details []models.DigimonDetail) (int, error) {
+	resp, err := list(models.DigimonSearchQueryParams{PageSize: 1})
+	if err == nil && resp.Pageable.TotalElements > 0 {
+		return resp.Pageable.TotalElements, nil
+	}
+	if err == nil {
+		err = errors.New("the Digimon list is empty")
+	}
+
+	highest := 0
+	for _, d := range details {
+		highest = max(highest, d.ID)
+	}
+	if highest == 0 {
+		highest = DefaultMaxID
+	}
+	return highest, err
+}
+
+// Filter narrows random picks. Empty values match everything, the others
+// are compared case-insensitively.
+type Filter struct {
+	Level     string
+	Attribute string
+}
+
+// IsZero reports whether the filter matches every Digimon.
+func (f Filter) IsZero() bool {
+	return f.Level == "" && f.Attribute == ""
+}
+
+// Match reports whether d has the level and the attribute.
+func (f Filter) Match(d models.DigimonDetail) bool {
+	var levels, attributes []string
+	for _, level := range d.Levels {
+		levels = append(levels, level.Level)
+	}
+	for _, attribute := range d.Attributes {
+		attributes = append(attributes, attribute.Attribute)
+	}
+	return matchesAny(levels, f.Level) && matchesAny(attributes, f.Attribute)
+}
+
+func (f Filter) String() string {
+	var parts []string
+	for _, value := range []string{f.Level, f.Attribute} {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) == 0 {
+		return "Digimon"
+	}
+	return strings.Join(parts, " ") + " Digimon"
+}
+
+func matchesAny(values []string, want string) bool {
+	if want == "" {
+		return true
+	}
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matching returns the IDs of the details the filter matches, in order.
+func Matching(details []models.DigimonDetail, f Filter) []int {
+	var ids []int
+	for _, d := range details {
+		if f.Match(d) {
+			ids = append(ids, d.ID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Candidates fetches the IDs of every Digimon the filter matches through
+// the list's level and attribute parameters.
+func Candidates(list ListFunc, f Filter) ([]int, error) {
+	var ids []int
+	for page := 0; ; page++ {
+		resp, err := list(models.DigimonSearchQueryParams{
+			Level:     f.Level,
+			Attribute: f.Attribute,
+			Page:      page,
+			PageSize:  listPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", f, err)
+		}
+		for _, digimon := range resp.Content {
+			ids = append(ids, digimon.ID)
+		}
+		if len(resp.Content) == 0 || page+1 >= resp.Pageable.TotalPages {
+			break
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Range returns the IDs 1 to maxID.
+func Range(maxID int) []int {
+	ids := make([]int, maxID)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+// Picker picks random Digimon, each at most once until every candidate has
+// been picked.
+type Picker struct {
+	rng  *rand.Rand
+	seen map[int]bool
+}
+
+// NewPicker returns a picker whose picks the seed fixes.
+func NewPicker(seed int64) *Picker {
+	return &Picker{rng: rand.New(rand.NewSource(seed)), seen: make(map[int]bool)}
+}
+
+// Seen keeps id from being picked, e.g. because it was shown already.
+func (p *Picker) Seen(id int) {
+	p.seen[id] = true
+}
+
+// Pick returns a random candidate that was not picked or seen before. When
+// every candidate was, it forgets them and starts over, which restarted
+// reports.
+func (p *Picker) Pick(candidates []int) (id int, restarted bool, err error) {
+	if len(candidates) == 0 {
+		return 0, false, ErrNoCandidates
+	}
+
+	var fresh []int
+	for _, candidate := range candidates {
+		if !p.seen[candidate] {
+			fresh = append(fresh, candidate)
+		}
+	}
+	if len(fresh) == 0 {
+		for _, candidate := range candidates {
+			delete(p.seen, candidate)
+		}
+		fresh, restarted = candidates, true
+	}
+
+	id = fresh[p.rng.Intn(len(fresh))]
+	p.seen[id] = true
+	return id, restarted, nil
+}
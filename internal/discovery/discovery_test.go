@@ -0,0 +1,161 @@
+package discovery
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+func TestDaily(t *testing.T) {
+	day := time.Date(2024, 3, 21, 8, 0, 0, 0, time.UTC)
+	id := Daily(day, 1400)
+	if id < 1 || id > 1400 {
+		t.Fatalf("Daily() = %d, outside 1..1400", id)
+	}
+	evening := time.Date(2024, 3, 21, 23, 59, 0, 0, time.FixedZone("JST", 9*60*60))
+	if got := Daily(evening, 1400); got != id {
+		t.Errorf("Daily() = %d later on the same date, want %d", got, id)
+	}
+
+	ids := make(map[int]bool)
+	for i := range 30 {
+		got := Daily(day.AddDate(0, 0, i), 1400)
+		if got < 1 || got > 1400 {
+			t.Fatalf("Daily() = %d, outside 1..1400", got)
+		}
+		ids[got] = true
+	}
+	if len(ids) < 25 {
+		t.Errorf("30 days gave only %d different Digimon", len(ids))
+	}
+	if got := Daily(day, 1); got != 1 {
+		t.Errorf("Daily() = %d with one Digimon", got)
+	}
+}
+
+func TestParseStart(t *testing.T) {
+	tests := map[string]Start{
+		"":         {Daily: true},
+		" Daily ":  {Daily: true},
+		"42":       {ID: 42},
+		"Greymon":  {Name: "Greymon"},
+		"Agumon 2": {Name: "Agumon 2"},
+		"-3":       {Name: "-3"},
+	}
+	for input, want := range tests {
+		if got := ParseStart(input); got != want {
+			t.Errorf("ParseStart(%q) = %+v, want %+v", input, got, want)
+		}
+	}
+}
+
+func TestMaxID(t *testing.T) {
+	list := func(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+		resp := &models.DigimonResponse{}
+		resp.Pageable.TotalElements = 1460
+		return resp, nil
+	}
+	if got, err := MaxID(list, nil); got != 1460 || err != nil {
+		t.Errorf("MaxID() = %d, %v, want 1460", got, err)
+	}
+
+	offline := func(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+		return nil, errors.New("offline")
+	}
+	details := []models.DigimonDetail{{ID: 3}, {ID: 812}, {ID: 40}}
+	if got, err := MaxID(offline, details); got != 812 || err == nil {
+		t.Errorf("MaxID(offline) = %d, %v, want 812 and the error", got, err)
+	}
+	if got, _ := MaxID(offline, nil); got != DefaultMaxID {
+		t.Errorf("MaxID(offline, no data) = %d, want %d", got, DefaultMaxID)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	details := fixtures.Details(t, "Greymon", "Agumon", "Devimon", "Gabumon")
+
+	tests := []struct {
+		filter Filter
+		want   []int
+	}{
+		{Filter{}, []int{3, 4, 13, 20}},
+		{Filter{Level: "adult"}, []int{4, 13}},
+		{Filter{Attribute: "Vaccine"}, []int{3, 4}},
+		{Filter{Level: "Child", Attribute: "data"}, []int{20}},
+		{Filter{Level: "Perfect"}, nil},
+	}
+	for _, tt := range tests {
+		if got := Matching(details, tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Matching(%s) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+	if got := (Filter{Level: "Child", Attribute: "Vaccine"}).String(); got != "Child Vaccine Digimon" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	var requests []models.DigimonSearchQueryParams
+	list := func(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+		requests = append(requests, params)
+		resp := &models.DigimonResponse{}
+		resp.Pageable.TotalPages = 2
+		for i := range 3 {
+			resp.Content = append(resp.Content, models.Digimon{ID: 10 - params.Page*5 - i})
+		}
+		return resp, nil
+	}
+
+	ids, err := Candidates(list, Filter{Level: "Child", Attribute: "Virus"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 4, 5, 8, 9, 10}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Candidates() = %v, want %v", ids, want)
+	}
+	if len(requests) != 2 || requests[1].Page != 1 || requests[0].Level != "Child" || requests[0].Attribute != "Virus" {
+		t.Errorf("requests = %+v", requests)
+	}
+}
+
+func TestPickerDoesNotRepeat(t *testing.T) {
+	p := NewPicker(1)
+	p.Seen(2)
+
+	candidates := Range(5)
+	picked := make(map[int]bool)
+	for range 4 {
+		id, restarted, err := p.Pick(candidates)
+		if err != nil || restarted {
+			t.Fatalf("Pick() = %d, %v, %v", id, restarted, err)
+		}
+		if id == 2 || picked[id] {
+			t.Fatalf("Pick() repeated %d after %v", id, picked)
+		}
+		picked[id] = true
+	}
+
+	if _, restarted, _ := p.Pick(candidates); !restarted {
+		t.Error("Pick() did not start over once every candidate was picked")
+	}
+	if _, _, err := p.Pick(nil); !errors.Is(err, ErrNoCandidates) {
+		t.Errorf("Pick(nil) = %v, want ErrNoCandidates", err)
+	}
+
+	sequence := func() []int {
+		p := NewPicker(7)
+		var ids []int
+		for range 5 {
+			id, _, _ := p.Pick(candidates)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	if a, b := sequence(), sequence(); !reflect.DeepEqual(a, b) {
+		t.Errorf("the same seed picked %v and %v", a, b)
+	}
+}
@@ -0,0 +1,242 @@
+// Package export saves a Digimon's artwork and field icons to disk and
+// renders self-contained HTML and Markdown pages with the images inlined as
+// data URIs, so they can be shared and read offline.
+package export
+
+import (
+	"embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+// DefaultDir is where exports go unless told otherwise
+const DefaultDir = "exports"
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	htmlPage = htmltemplate.Must(htmltemplate.New("digimon.html").Funcs(htmltemplate.FuncMap{
+		// Data URIs are built from sniffed image data, mark them safe for src
+		"dataURI": func(a *Asset) htmltemplate.URL { return htmltemplate.URL(a.DataURI()) },
+	}).ParseFS(templates, "templates/digimon.html"))
+	markdownPage = template.Must(template.New("digimon.md").Funcs(template.FuncMap{
+		"escape": escapeMarkdown,
+	}).ParseFS(templates, "templates/digimon.md"))
+)
+
+// Fetcher downloads image data, services.Client implements it.
+type Fetcher interface {
+	GetImageDataByURL(imageUrl string) ([]byte, string, error)
+}
+
+type Format string
+
+const (
+	HTML     Format = "html"
+	Markdown Format = "md"
+)
+
+// ParseFormats parses a comma separated list of page formats. An empty list
+// means no pages.
+func ParseFormats(list string) ([]Format, error) {
+	var formats []Format
+	for _, name := range strings.Split(list, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "html":
+			formats = append(formats, HTML)
+		case "md", "markdown":
+			formats = append(formats, Markdown)
+		default:
+			return nil, fmt.Errorf("invalid export format %q, want html or md", name)
+		}
+	}
+	return formats, nil
+}
+
+type Options struct {
+	// Dir is the directory each Digimon gets a subdirectory in
+	Dir string
+	// Formats are the pages written next to the images
+	Formats []Format
+}
+
+// Asset is one image saved by Export.
+type Asset struct {
+	// Name is the Digimon or field the image belongs to
+	Name string
+	URL  string
+	MIME string
+	// Path is the file written, relative to Result.Dir
+	Path string
+	data []byte
+}
+
+// DataURI returns the image as a base64 data URI.
+func (a Asset) DataURI() string {
+	return fmt.Sprintf("data:%s;base64,%s", a.MIME, base64.StdEncoding.EncodeToString(a.data))
+}
+
+type Result struct {
+	// Dir is the directory the Digimon was exported to
+	Dir    string
+	Assets []Asset
+	// Pages are the page files written, relative to Dir
+	Pages []string
+	// Errors holds the images that could not be fetched. They are left out
+	// of the export instead of failing it.
+	Errors []error
+}
+
+// Export writes the artwork and field icons of digimon to
+// <opts.Dir>/<id>-<name>/, named after their detected MIME type, followed
+// by the pages in opts.Formats.
+func Export(fetcher Fetcher, digimon *models.DigimonDetail, opts Options) (*Result, error) {
+	if digimon == nil || digimon.ID == 0 {
+		return nil, errors.New("no Digimon to export")
+	}
+	view := viewmodel.NewDigimonView(digimon)
+	slug := fmt.Sprintf("%d-%s", view.ID, Slug(view.Name))
+
+	result := &Result{Dir: filepath.Join(opts.Dir, slug)}
+	if err := os.MkdirAll(filepath.Join(result.Dir, "fields"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	page := pageData{DigimonView: view}
+	if view.ImageURL != "" {
+		if asset, err := save(fetcher, result, view.Name, view.ImageURL, Slug(view.Name)); err != nil {
+			result.Errors = append(result.Errors, err)
+		} else {
+			page.Image = &asset
+		}
+	}
+	for _, field := range view.Fields {
+		icon := pageField{FieldView: field}
+		if field.ImageURL != "" {
+			if asset, err := save(fetcher, result, field.Name, field.ImageURL, filepath.Join("fields", Slug(field.Name))); err != nil {
+				result.Errors = append(result.Errors, err)
+			} else {
+				icon.Image = &asset
+			}
+		}
+		page.Fields = append(page.Fields, icon)
+	}
+
+	for _, format := range opts.Formats {
+		name := slug + "." + string(format)
+		if err := writePage(filepath.Join(result.Dir, name), format, page); err != nil {
+			return result, err
+		}
+		result.Pages = append(result.Pages, name)
+	}
+	return result, nil
+}
+
+// save fetches url into the export directory as name plus the extension of
+// its MIME type. Payloads that are no decodable image, such as an error
+// page, are not saved.
+func save(fetcher Fetcher, result *Result, name, url, path string) (Asset, error) {
+	data, mime, err := fetcher.GetImageDataByURL(url)
+	if err != nil {
+		return Asset{}, err
+	}
+	if !imaging.CanDecode(mime) {
+		return Asset{}, fmt.Errorf("%w: %s from %s", imaging.ErrUnsupportedFormat, mime, url)
+	}
+
+	asset := Asset{Name: name, URL: url, MIME: mime, Path: path + imaging.Extension(mime), data: data}
+	if err := os.WriteFile(filepath.Join(result.Dir, asset.Path), data, 0644); err != nil {
+		return Asset{}, fmt.Errorf("failed to write %s: %w", asset.Path, err)
+	}
+	result.Assets = append(result.Assets, asset)
+	return asset, nil
+}
+
+type pageData struct {
+	viewmodel.DigimonView
+	Image  *Asset
+	Fields []pageField
+}
+
+type pageField struct {
+	viewmodel.FieldView
+	Image *Asset
+}
+
+func writePage(path string, format Format, page pageData) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create page: %w", err)
+	}
+	defer file.Close()
+
+	if err := renderPage(file, format, page); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func renderPage(w io.Writer, format Format, page pageData) error {
+	var err error
+	switch format {
+	case HTML:
+		err = htmlPage.Execute(w, page)
+	case Markdown:
+		err = markdownPage.Execute(w, page)
+	default:
+		return fmt.Errorf("invalid export format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render %s page: %w", format, err)
+	}
+	return nil
+}
+
+// Slug turns a name into a lower case file name of letters, digits and
+// dashes.
+func Slug(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if slug.Len() == 0 {
+		return "digimon"
+	}
+	return slug.String()
+}
+
+// escapeMarkdown backslash-escapes the characters Markdown would treat as
+// inline formatting or table cell breaks.
+func escapeMarkdown(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune("\\`*_[]<>|", r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
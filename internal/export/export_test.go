@@ -0,0 +1,118 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/gif"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+type fakeFetcher map[string][]byte
+
+func (f fakeFetcher) GetImageDataByURL(imageUrl string) ([]byte, string, error) {
+	data, ok := f[imageUrl]
+	if !ok {
+		return nil, "", errors.New("not found")
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+func TestExport(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	var pngData, gifData bytes.Buffer
+	png.Encode(&pngData, img)
+	gif.Encode(&gifData, img, nil)
+
+	var digimon models.DigimonDetail
+	err := json.Unmarshal([]byte(`{
+		"id": 4,
+		"name": "Greymon",
+		"images": [{"href": "https://example.com/greymon"}],
+		"fields": [
+			{"field": "Nature Spirits", "image": "https://example.com/ns"},
+			{"field": "Virus Busters", "image": "https://example.com/missing"},
+			{"field": "Dark Area", "image": "https://example.com/error-page"}
+		],
+		"descriptions": [{"language": "en_us", "description": "A *big* dinosaur."}]
+	}`), &digimon)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := fakeFetcher{
+		"https://example.com/greymon":    gifData.Bytes(),
+		"https://example.com/ns":         pngData.Bytes(),
+		"https://example.com/error-page": []byte("<html><body>Bad Gateway</body></html>"),
+	}
+	dir := t.TempDir()
+	result, err := Export(fetcher, &digimon, Options{Dir: dir, Formats: []Format{HTML, Markdown}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Dir != filepath.Join(dir, "4-greymon") {
+		t.Errorf("Dir = %s", result.Dir)
+	}
+	if len(result.Errors) != 2 || !errors.Is(result.Errors[1], imaging.ErrUnsupportedFormat) {
+		t.Errorf("Errors = %v, want the missing and the HTML field icons", result.Errors)
+	}
+	if _, err := os.Stat(filepath.Join(result.Dir, "fields", "dark-area.bin")); err == nil {
+		t.Error("the HTML error page was saved as a field icon")
+	}
+	for _, path := range []string{"greymon.gif", "fields/nature-spirits.png", "4-greymon.html", "4-greymon.md"} {
+		if _, err := os.Stat(filepath.Join(result.Dir, path)); err != nil {
+			t.Errorf("%s was not written: %v", path, err)
+		}
+	}
+
+	html, _ := os.ReadFile(filepath.Join(result.Dir, "4-greymon.html"))
+	for _, want := range []string{`src="data:image/gif;base64,`, `src="data:image/png;base64,`, "A *big* dinosaur."} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("HTML page is missing %q", want)
+		}
+	}
+
+	if strings.Contains(string(html), "data:text/html") {
+		t.Error("the HTML error page was inlined")
+	}
+
+	markdown, _ := os.ReadFile(filepath.Join(result.Dir, "4-greymon.md"))
+	for _, want := range []string{"# Greymon", "![Greymon](data:image/gif;base64,", "- ![](data:image/png;base64,", "A \\*big\\* dinosaur."} {
+		if !strings.Contains(string(markdown), want) {
+			t.Errorf("Markdown page is missing %q:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats("html, markdown")
+	if err != nil || len(formats) != 2 || formats[0] != HTML || formats[1] != Markdown {
+		t.Errorf("ParseFormats = %v, %v", formats, err)
+	}
+	if _, err := ParseFormats("pdf"); err == nil {
+		t.Error("ParseFormats accepted pdf")
+	}
+}
+
+func TestSlug(t *testing.T) {
+	for name, want := range map[string]string{
+		"Greymon":           "greymon",
+		"MetalGreymon (X)":  "metalgreymon-x",
+		"  War--Greymon!  ": "war-greymon",
+		"???":               "digimon",
+	} {
+		if got := Slug(name); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
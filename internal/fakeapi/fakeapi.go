@@ -0,0 +1,353 @@
+// Package fakeapi serves recorded Digi-API responses with the same routes
+// and pagination semantics as the real API, for offline tests and demos.
+package fakeapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services/cassette"
+)
+
+// defaultPageSize matches the Digi-API default when pageSize is omitted
+const defaultPageSize = 5
+
+//go:embed testdata/*.json
+var defaultCassettes embed.FS
+
+type recordedImage struct {
+	contentType string
+	body        []byte
+}
+
+// Server answers /api/v1/digimon, /api/v1/digimon/{id|name}, /api/v1/field
+// and image requests from the Digimon found in a set of cassettes.
+type Server struct {
+	details []models.DigimonDetail
+	images  map[string]recordedImage
+	origin  string
+	mux     *http.ServeMux
+}
+
+// NewDefault returns a server for the cassettes bundled in testdata/.
+func NewDefault() (*Server, error) {
+	cassettes, err := LoadFS(defaultCassettes, "testdata")
+	if err != nil {
+		return nil, err
+	}
+	return New(cassettes...)
+}
+
+// LoadFS reads every *.json cassette in dir of fsys.
+func LoadFS(fsys fs.FS, dir string) ([]*cassette.Cassette, error) {
+	paths, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cassettes: %v", err)
+	}
+
+	cassettes := make([]*cassette.Cassette, 0, len(paths))
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette %s: %w", p, err)
+		}
+		c, err := cassette.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		cassettes = append(cassettes, c)
+	}
+	return cassettes, nil
+}
+
+// New builds a server from recorded detail, list and image interactions.
+// Digimon that only appear in list responses are served with the summary
+// fields the list provided.
+func New(cassettes ...*cassette.Cassette) (*Server, error) {
+	s := &Server{
+		images: make(map[string]recordedImage),
+		mux:    http.NewServeMux(),
+	}
+
+	details := make(map[int]models.DigimonDetail)
+	summaries := make(map[int]models.Digimon)
+
+	for _, c := range cassettes {
+		for _, interaction := range c.Interactions {
+			if interaction.Status != http.StatusOK {
+				continue
+			}
+			u, err := url.Parse(interaction.URL)
+			if err != nil {
+				continue
+			}
+			if s.origin == "" && u.Scheme != "" {
+				s.origin = u.Scheme + "://" + u.Host
+			}
+
+			body, err := interaction.BodyBytes()
+			if err != nil {
+				return nil, fmt.Errorf("invalid body for %s: %v", interaction.URL, err)
+			}
+
+			switch cassette.Kind(interaction) {
+			case "images":
+				s.images[u.Path] = recordedImage{
+					contentType: interaction.Header.Get("Content-Type"),
+					body:        body,
+				}
+			case "detail":
+				var detail models.DigimonDetail
+				if err := json.Unmarshal(body, &detail); err == nil && detail.ID > 0 {
+					details[detail.ID] = detail
+				}
+			case "list":
+				var list models.DigimonResponse
+				if err := json.Unmarshal(body, &list); err == nil {
+					for _, digimon := range list.Content {
+						summaries[digimon.ID] = digimon
+					}
+				}
+			}
+		}
+	}
+
+	for id, summary := range summaries {
+		if _, ok := details[id]; ok || id <= 0 {
+			continue
+		}
+		detail := models.DigimonDetail{ID: id, Name: summary.Name}
+		if summary.Image != "" {
+			detail.Images = append(detail.Images, struct {
+				Href        string `json:"href"`
+				Transparent bool   `json:"transparent"`
+			}{Href: summary.Image})
+		}
+		details[id] = detail
+	}
+
+	for _, detail := range details {
+		s.details = append(s.details, detail)
+	}
+	sort.Slice(s.details, func(i, j int) bool {
+		return s.details[i].ID < s.details[j].ID
+	})
+
+	s.mux.HandleFunc("GET /api/v1/digimon", s.handleList)
+	s.mux.HandleFunc("GET /api/v1/digimon/{key}", s.handleDetail)
+	s.mux.HandleFunc("GET /api/v1/field", s.handleFields)
+	s.mux.HandleFunc("GET /", s.handleImage)
+
+	return s, nil
+}
+
+// Len returns the number of Digimon the server knows.
+func (s *Server) Len() int {
+	return len(s.details)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := intParam(query, "page", 0)
+	if err != nil || page < 0 {
+		writeError(w, http.StatusBadRequest, "invalid page")
+		return
+	}
+	pageSize, err := intParam(query, "pageSize", defaultPageSize)
+	if err != nil || pageSize < 1 {
+		writeError(w, http.StatusBadRequest, "invalid pageSize")
+		return
+	}
+
+	matches := s.filter(query)
+	totalPages := (len(matches) + pageSize - 1) / pageSize
+	start := min(page*pageSize, len(matches))
+	end := min(start+pageSize, len(matches))
+
+	resp := models.DigimonResponse{Content: make([]models.Digimon, 0, end-start)}
+	for _, detail := range matches[start:end] {
+		resp.Content = append(resp.Content, s.summary(detail))
+	}
+	resp.Pageable.CurrentPage = page
+	resp.Pageable.ElementsOnPage = len(resp.Content)
+	resp.Pageable.TotalElements = len(matches)
+	resp.Pageable.TotalPages = totalPages
+	if page > 0 && totalPages > 0 {
+		resp.Pageable.PreviousPage = s.pageURL(query, min(page-1, totalPages-1), pageSize)
+	}
+	if page < totalPages-1 {
+		resp.Pageable.NextPage = s.pageURL(query, page+1, pageSize)
+	}
+
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+// filter applies the name, exact, level, attribute and xAntibody parameters.
+func (s *Server) filter(query url.Values) []models.DigimonDetail {
+	name := strings.ToLower(query.Get("name"))
+	exact := query.Get("exact") == "true"
+	level := strings.ToLower(query.Get("level"))
+	attribute := strings.ToLower(query.Get("attribute"))
+	xAntibody := query.Get("xAntibody")
+
+	var matches []models.DigimonDetail
+	for _, detail := range s.details {
+		detailName := strings.ToLower(detail.Name)
+		if exact && name != "" && detailName != name {
+			continue
+		}
+		if !exact && !strings.Contains(detailName, name) {
+			continue
+		}
+		if level != "" && !hasLevel(detail, level) {
+			continue
+		}
+		if attribute != "" && !hasAttribute(detail, attribute) {
+			continue
+		}
+		if xAntibody != "" && strconv.FormatBool(detail.XAntibody) != xAntibody {
+			continue
+		}
+		matches = append(matches, detail)
+	}
+	return matches
+}
+
+func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+
+	for _, detail := range s.details {
+		if strconv.Itoa(detail.ID) == key || strings.EqualFold(detail.Name, key) {
+			s.writeJSON(w, r, http.StatusOK, detail)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Digimon %q not found", key))
+}
+
+// handleFields lists the fields of the known Digimon on a single page, the
+// real endpoint has few enough of them.
+func (s *Server) handleFields(w http.ResponseWriter, r *http.Request) {
+	seen := make(map[int]bool)
+	var resp models.FieldResponse
+	resp.Content.Name = "DigimonField"
+	resp.Content.Fields = []models.Field{}
+	for _, detail := range s.details {
+		for _, field := range detail.Fields {
+			if seen[field.ID] {
+				continue
+			}
+			seen[field.ID] = true
+			resp.Content.Fields = append(resp.Content.Fields, models.Field{
+				ID:   field.ID,
+				Name: field.Field,
+				Href: fmt.Sprintf("%s/api/v1/field/%d", s.origin, field.ID),
+			})
+		}
+	}
+	sort.Slice(resp.Content.Fields, func(i, j int) bool {
+		return resp.Content.Fields[i].ID < resp.Content.Fields[j].ID
+	})
+	resp.Pageable.ElementsOnPage = len(resp.Content.Fields)
+	resp.Pageable.TotalElements = len(resp.Content.Fields)
+	resp.Pageable.TotalPages = 1
+
+	s.writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	img, ok := s.images[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", img.contentType)
+	w.Write(img.body)
+}
+
+func (s *Server) summary(detail models.DigimonDetail) models.Digimon {
+	summary := models.Digimon{
+		ID:   detail.ID,
+		Name: detail.Name,
+		Href: fmt.Sprintf("%s/api/v1/digimon/%d", s.origin, detail.ID),
+	}
+	if len(detail.Images) > 0 {
+		summary.Image = detail.Images[0].Href
+	}
+	return summary
+}
+
+// pageURL builds a previous/next link in terms of the recorded origin; the
+// origin is rewritten to this server when the response is written.
+func (s *Server) pageURL(query url.Values, page, pageSize int) string {
+	q := url.Values{}
+	for key, values := range query {
+		q[key] = values
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("pageSize", strconv.Itoa(pageSize))
+	return fmt.Sprintf("%s/api/v1/digimon?%s", s.origin, q.Encode())
+}
+
+// writeJSON encodes v and points every recorded URL at this server, so that
+// links and images keep working offline.
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if s.origin != "" {
+		data = []byte(strings.ReplaceAll(string(data), s.origin, "http://"+r.Host))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+func intParam(query url.Values, key string, fallback int) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func hasLevel(detail models.DigimonDetail, level string) bool {
+	for _, l := range detail.Levels {
+		if strings.ToLower(l.Level) == level || strconv.Itoa(l.ID) == level {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAttribute(detail models.DigimonDetail, attribute string) bool {
+	for _, a := range detail.Attributes {
+		if strings.ToLower(a.Attribute) == attribute || strconv.Itoa(a.ID) == attribute {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package fakeapi
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+func newTestClient(t *testing.T) (*services.Client, *Server) {
+	t.Helper()
+
+	server, err := NewDefault()
+	if err != nil {
+		t.Fatalf("NewDefault() error = %v", err)
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	return services.NewClient(ts.URL+"/api/v1", nil), server
+}
+
+func TestPagination(t *testing.T) {
+	client, server := newTestClient(t)
+
+	seen := make(map[int]bool)
+	for page := 0; ; page++ {
+		resp, err := client.GetDigimonList(models.DigimonSearchQueryParams{Page: page, PageSize: 5})
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		if resp.Pageable.CurrentPage != page {
+			t.Errorf("page %d: currentPage = %d", page, resp.Pageable.CurrentPage)
+		}
+		if (page == 0) != (resp.Pageable.PreviousPage == "") {
+			t.Errorf("page %d: previousPage = %q", page, resp.Pageable.PreviousPage)
+		}
+		if resp.Pageable.TotalElements != server.Len() {
+			t.Errorf("totalElements = %d, want %d", resp.Pageable.TotalElements, server.Len())
+		}
+		for _, digimon := range resp.Content {
+			if seen[digimon.ID] {
+				t.Errorf("digimon %d listed twice", digimon.ID)
+			}
+			seen[digimon.ID] = true
+		}
+
+		if resp.Pageable.NextPage == "" {
+			if page != resp.Pageable.TotalPages-1 {
+				t.Errorf("nextPage empty on page %d of %d", page, resp.Pageable.TotalPages)
+			}
+			break
+		}
+		if !strings.Contains(resp.Pageable.NextPage, "page=") || strings.Contains(resp.Pageable.NextPage, "digi-api.com") {
+			t.Errorf("nextPage = %q, want a link to the fake server", resp.Pageable.NextPage)
+		}
+	}
+
+	if len(seen) != server.Len() {
+		t.Errorf("listed %d digimon, want %d", len(seen), server.Len())
+	}
+}
+
+func TestNameFilter(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	resp, err := client.GetDigimonList(models.DigimonSearchQueryParams{Name: "greymon", PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Pageable.TotalElements != 3 {
+		t.Errorf("totalElements = %d, want 3 (Greymon, MetalGreymon, WarGreymon)", resp.Pageable.TotalElements)
+	}
+}
+
+func TestDetail(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	byName, err := client.GetDigimonByName("Greymon")
+	if err != nil {
+		t.Fatalf("GetDigimonByName() error = %v", err)
+	}
+	byID, err := client.GetDigimonByID(byName.ID)
+	if err != nil {
+		t.Fatalf("GetDigimonByID() error = %v", err)
+	}
+	if byID.Name != "Greymon" {
+		t.Errorf("GetDigimonByID(%d).Name = %q", byName.ID, byID.Name)
+	}
+
+	if _, err := client.GetImageByURL(byID.Images[0].Href); err != nil {
+		t.Errorf("image %s was not served: %v", byID.Images[0].Href, err)
+	}
+
+	if _, err := client.GetDigimonByID(9999); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("GetDigimonByID(9999) error = %v, want ErrNotFound", err)
+	}
+	if _, err := client.GetDigimonByName("Nomon"); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("GetDigimonByName(Nomon) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFields(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	fields, err := client.GetFieldList()
+	if err != nil {
+		t.Fatalf("GetFieldList() error = %v", err)
+	}
+	if len(fields) != 9 {
+		t.Fatalf("GetFieldList() returned %d fields, want 9", len(fields))
+	}
+	if fields[0].ID != 1 || fields[0].Name != "Metal Empire" {
+		t.Errorf("fields[0] = %+v, want Metal Empire first", fields[0])
+	}
+	if !strings.HasSuffix(fields[0].Href, "/api/v1/field/1") || strings.Contains(fields[0].Href, "digi-api.com") {
+		t.Errorf("href %q does not point at the fake server", fields[0].Href)
+	}
+}
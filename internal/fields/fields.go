@@ -0,0 +1,168 @@
+// Package fields groups a set of Digimon details by field (faction), such as
+// Nightmare Soldiers or Virus Busters, so a field can be browsed with its
+// members.
+package fields
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// Field is a field with the icon the details link to and the number of
+// indexed Digimon in it.
+type Field struct {
+	ID    int
+	Name  string
+	Image string
+	Count int
+}
+
+// Member is a Digimon of a field.
+type Member struct {
+	ID         int
+	Name       string
+	Levels     []string
+	Attributes []string
+}
+
+// Filter narrows the members of a field. Empty values match everything,
+// the others are compared case-insensitively.
+type Filter struct {
+	Level     string
+	Attribute string
+}
+
+func (f Filter) matches(member Member) bool {
+	return matchesAny(member.Levels, f.Level) && matchesAny(member.Attributes, f.Attribute)
+}
+
+func matchesAny(values []string, want string) bool {
+	if want == "" {
+		return true
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// Index maps field IDs to the fields and their members.
+type Index struct {
+	fields  map[int]*Field
+	members map[int][]Member
+}
+
+// NewIndex indexes details. reference, the /field endpoint's list, adds the
+// fields no indexed Digimon belongs to; details provide the icons.
+func NewIndex(reference []models.Field, details []models.DigimonDetail) *Index {
+	index := &Index{
+		fields:  make(map[int]*Field),
+		members: make(map[int][]Member),
+	}
+	for _, field := range reference {
+		index.field(field.ID, field.Name)
+	}
+
+	for _, digimon := range details {
+		member := Member{ID: digimon.ID, Name: digimon.Name}
+		for _, level := range digimon.Levels {
+			if name := strings.TrimSpace(level.Level); name != "" {
+				member.Levels = append(member.Levels, name)
+			}
+		}
+		for _, attribute := range digimon.Attributes {
+			if name := strings.TrimSpace(attribute.Attribute); name != "" {
+				member.Attributes = append(member.Attributes, name)
+			}
+		}
+
+		seen := make(map[int]bool)
+		for _, entry := range digimon.Fields {
+			if seen[entry.ID] || strings.TrimSpace(entry.Field) == "" {
+				continue
+			}
+			seen[entry.ID] = true
+			field := index.field(entry.ID, entry.Field)
+			if field.Image == "" {
+				field.Image = entry.Image
+			}
+			field.Count++
+			index.members[entry.ID] = append(index.members[entry.ID], member)
+		}
+	}
+
+	for _, members := range index.members {
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Name != members[j].Name {
+				return members[i].Name < members[j].Name
+			}
+			return members[i].ID < members[j].ID
+		})
+	}
+	return index
+}
+
+func (i *Index) field(id int, name string) *Field {
+	field, ok := i.fields[id]
+	if !ok {
+		field = &Field{ID: id}
+		i.fields[id] = field
+	}
+	if field.Name == "" {
+		field.Name = strings.TrimSpace(name)
+	}
+	return field
+}
+
+// Fields returns every field ordered by ID.
+func (i *Index) Fields() []Field {
+	fields := make([]Field, 0, len(i.fields))
+	for _, field := range i.fields {
+		fields = append(fields, *field)
+	}
+	sort.Slice(fields, func(a, b int) bool {
+		return fields[a].ID < fields[b].ID
+	})
+	return fields
+}
+
+// Members returns the Digimon of the field that match filter, ordered by
+// name.
+func (i *Index) Members(fieldID int, filter Filter) []Member {
+	var members []Member
+	for _, member := range i.members[fieldID] {
+		if filter.matches(member) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// Levels returns the distinct levels of the field's members, sorted.
+func (i *Index) Levels(fieldID int) []string {
+	return i.distinct(fieldID, func(member Member) []string { return member.Levels })
+}
+
+// Attributes returns the distinct attributes of the field's members, sorted.
+func (i *Index) Attributes(fieldID int) []string {
+	return i.distinct(fieldID, func(member Member) []string { return member.Attributes })
+}
+
+func (i *Index) distinct(fieldID int, values func(Member) []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, member := range i.members[fieldID] {
+		for _, value := range values(member) {
+			if !seen[strings.ToLower(value)] {
+				seen[strings.ToLower(value)] = true
+				result = append(result, value)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,43 @@
+package fields
+
+import (
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+func TestIndex(t *testing.T) {
+	details := fixtures.Details(t, "Greymon", "Devimon", "Agumon")
+	reference := []models.Field{{ID: 3, Name: "Virus Busters"}, {ID: 9, Name: "Dark Area"}}
+	index := NewIndex(reference, details)
+
+	fields := index.Fields()
+	if len(fields) != 4 {
+		t.Fatalf("Fields() = %+v, want 4 fields", fields)
+	}
+	if fields[0] != (Field{ID: 3, Name: "Virus Busters", Image: "vb.png", Count: 2}) {
+		t.Errorf("fields[0] = %+v", fields[0])
+	}
+	if fields[3].Name != "Dark Area" || fields[3].Count != 0 {
+		t.Errorf("reference-only field = %+v", fields[3])
+	}
+
+	members := index.Members(3, Filter{})
+	if len(members) != 2 || members[0].Name != "Agumon" || members[1].Name != "Greymon" {
+		t.Errorf("Members(3) = %+v", members)
+	}
+	if members := index.Members(3, Filter{Level: "adult", Attribute: "VACCINE"}); len(members) != 1 || members[0].ID != 4 {
+		t.Errorf("filtered members = %+v", members)
+	}
+	if members := index.Members(3, Filter{Attribute: "Virus"}); len(members) != 0 {
+		t.Errorf("members with no match = %+v", members)
+	}
+
+	if levels := index.Levels(3); len(levels) != 2 || levels[0] != "Adult" || levels[1] != "Child" {
+		t.Errorf("Levels(3) = %v", levels)
+	}
+	if attributes := index.Attributes(3); len(attributes) != 1 || attributes[0] != "Vaccine" {
+		t.Errorf("Attributes(3) = %v", attributes)
+	}
+}
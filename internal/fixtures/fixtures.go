@@ -0,0 +1,44 @@
+// Package fixtures loads the Digimon the package tests share, so every test
+// reads the same consistent details instead of its own inline JSON.
+package fixtures
+
+import (
+	_ "embed"
+	"encoding/json"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+//go:embed testdata/digimon.json
+var digimonJSON []byte
+
+// Details returns the fixture Digimon with the given names in that order,
+// or all of them when no name is given. Each call returns fresh copies.
+func Details(tb testing.TB, names ...string) []models.DigimonDetail {
+	tb.Helper()
+
+	var all []models.DigimonDetail
+	if err := json.Unmarshal(digimonJSON, &all); err != nil {
+		tb.Fatalf("invalid fixture: %v", err)
+	}
+	if len(names) == 0 {
+		return all
+	}
+
+	details := make([]models.DigimonDetail, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, detail := range all {
+			if detail.Name == name {
+				details = append(details, detail)
+				found = true
+				break
+			}
+		}
+		if !found {
+			tb.Fatalf("no fixture Digimon named %q", name)
+		}
+	}
+	return details
+}
@@ -0,0 +1,222 @@
+// Package imaging decodes Digimon artwork through a registry of decoders
+// keyed by MIME type, guards against oversized images and resizes them for
+// the panel they are drawn in.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/webp"
+)
+
+// ErrUnsupportedFormat is returned for data no registered decoder handles.
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// FormatError reports data that looked like MIME but failed to decode.
+type FormatError struct {
+	MIME string
+	Err  error
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("failed to decode %s: %v", e.MIME, e.Err)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// TooLargeError reports an image over the Limits, by file size when Size is
+// set and by its dimensions otherwise.
+type TooLargeError struct {
+	Size          int64
+	Width, Height int
+	Limits        Limits
+}
+
+func (e *TooLargeError) Error() string {
+	if e.Size > 0 {
+		return fmt.Sprintf("image is larger than %d bytes", e.Limits.MaxBytes)
+	}
+	return fmt.Sprintf("image is %dx%d, more than %d pixels", e.Width, e.Height, e.Limits.MaxPixels)
+}
+
+// Limits bound what Decode accepts. Zero values disable a check.
+type Limits struct {
+	MaxBytes  int64
+	MaxPixels int
+}
+
+// DefaultLimits are far above any Digi-API artwork but keep a broken or
+// hostile response from using gigabytes of memory.
+var DefaultLimits = Limits{
+	MaxBytes:  20 << 20,
+	MaxPixels: 4096 * 4096,
+}
+
+// Decoder decodes one image format. DecodeConfig reads only the header, so
+// dimensions can be checked before decoding the pixels.
+type Decoder struct {
+	Decode       func(r io.Reader) (image.Image, error)
+	DecodeConfig func(r io.Reader) (image.Config, error)
+}
+
+var (
+	decodersMutex sync.RWMutex
+	decoders      = map[string]Decoder{
+		"image/jpeg": {jpeg.Decode, jpeg.DecodeConfig},
+		"image/png":  {png.Decode, png.DecodeConfig},
+		"image/gif":  {decodeGIF, gif.DecodeConfig},
+		"image/webp": {webp.Decode, webp.DecodeConfig},
+	}
+)
+
+// RegisterDecoder makes Decode handle data sniffed as mime by
+// http.DetectContentType, replacing any decoder registered for it.
+func RegisterDecoder(mime string, decoder Decoder) {
+	decodersMutex.Lock()
+	defer decodersMutex.Unlock()
+	decoders[mime] = decoder
+}
+
+// Formats returns the MIME types with a registered decoder.
+func Formats() []string {
+	decodersMutex.RLock()
+	defer decodersMutex.RUnlock()
+
+	formats := make([]string, 0, len(decoders))
+	for mime := range decoders {
+		formats = append(formats, mime)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// CanDecode reports whether a decoder is registered for mime.
+func CanDecode(mime string) bool {
+	decodersMutex.RLock()
+	defer decodersMutex.RUnlock()
+	_, ok := decoders[mime]
+	return ok
+}
+
+// Extension returns the file extension for an image MIME type, ".bin" for
+// anything that is not a known image format.
+func Extension(mime string) string {
+	switch mime {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/bmp":
+		return ".bmp"
+	}
+	return ".bin"
+}
+
+// DetectMIME returns the MIME type of image data, without parameters.
+func DetectMIME(data []byte) string {
+	mime := http.DetectContentType(data)
+	if i := strings.IndexByte(mime, ';'); i >= 0 {
+		mime = mime[:i]
+	}
+	return mime
+}
+
+// ReadData reads image data from r, stopping with a *TooLargeError once it
+// goes past limits.MaxBytes.
+func ReadData(r io.Reader, limits Limits) ([]byte, error) {
+	if limits.MaxBytes > 0 {
+		r = io.LimitReader(r, limits.MaxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image data: %w", err)
+	}
+	if limits.MaxBytes > 0 && int64(len(data)) > limits.MaxBytes {
+		return nil, &TooLargeError{Size: int64(len(data)), Limits: limits}
+	}
+	return data, nil
+}
+
+// Read reads at most limits.MaxBytes from r and decodes the image.
+func Read(r io.Reader, limits Limits) (image.Image, string, error) {
+	data, err := ReadData(r, limits)
+	if err != nil {
+		return nil, "", err
+	}
+	return Decode(data, limits)
+}
+
+// Decode sniffs the format of data and decodes it with the registered
+// decoder, returning the image and its MIME type. Errors are
+// ErrUnsupportedFormat, *TooLargeError or *FormatError.
+func Decode(data []byte, limits Limits) (image.Image, string, error) {
+	if limits.MaxBytes > 0 && int64(len(data)) > limits.MaxBytes {
+		return nil, "", &TooLargeError{Size: int64(len(data)), Limits: limits}
+	}
+
+	mime := DetectMIME(data)
+	decodersMutex.RLock()
+	decoder, ok := decoders[mime]
+	decodersMutex.RUnlock()
+	if !ok {
+		return nil, mime, fmt.Errorf("%w: %s", ErrUnsupportedFormat, mime)
+	}
+
+	if decoder.DecodeConfig != nil && limits.MaxPixels > 0 {
+		config, err := decoder.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, mime, &FormatError{MIME: mime, Err: err}
+		}
+		if config.Width*config.Height > limits.MaxPixels {
+			return nil, mime, &TooLargeError{Width: config.Width, Height: config.Height, Limits: limits}
+		}
+	}
+
+	img, err := decoder.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, mime, &FormatError{MIME: mime, Err: err}
+	}
+	return img, mime, nil
+}
+
+// decodeGIF returns the first frame of a GIF drawn on the full logical
+// screen, as animated GIFs may store it as a smaller patch. The frames after
+// the first are never decoded.
+func decodeGIF(r io.Reader) (image.Image, error) {
+	// The header read for the screen size is replayed to the frame decoder
+	var header bytes.Buffer
+	config, err := gif.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	first, err := gif.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, err
+	}
+
+	screen := image.Rect(0, 0, config.Width, config.Height)
+	if screen.Empty() || first.Bounds() == screen {
+		return first, nil
+	}
+	canvas := image.NewNRGBA(screen)
+	draw.Draw(canvas, first.Bounds(), first, first.Bounds().Min, draw.Src)
+	return canvas, nil
+}
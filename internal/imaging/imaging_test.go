@@ -0,0 +1,211 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+)
+
+func encode(t *testing.T, img image.Image, format string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeFormats(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 6, 4))
+	webpData, err := os.ReadFile("testdata/sample.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		data []byte
+		mime string
+	}{
+		{encode(t, img, "png"), "image/png"},
+		{encode(t, img, "jpeg"), "image/jpeg"},
+		{encode(t, img, "gif"), "image/gif"},
+		{webpData, "image/webp"},
+	}
+	for _, tt := range tests {
+		decoded, mime, err := Decode(tt.data, DefaultLimits)
+		if err != nil {
+			t.Errorf("Decode(%s) error = %v", tt.mime, err)
+			continue
+		}
+		if mime != tt.mime || decoded.Bounds().Empty() {
+			t.Errorf("Decode(%s) = %v, %s", tt.mime, decoded.Bounds(), mime)
+		}
+	}
+}
+
+func TestDecodeAnimatedGIFFirstFrame(t *testing.T) {
+	// The first frame only covers the top left corner of the 8x8 screen
+	first := image.NewPaletted(image.Rect(0, 0, 4, 4), palette.Plan9)
+	for i := range first.Pix {
+		first.Pix[i] = uint8(first.Palette.Index(color.RGBA{R: 255, A: 255}))
+	}
+	second := image.NewPaletted(image.Rect(0, 0, 8, 8), palette.Plan9)
+
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:  []*image.Paletted{first, second},
+		Delay:  []int{10, 10},
+		Config: image.Config{Width: 8, Height: 8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, _, err := Decode(buf.Bytes(), DefaultLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 8, 8) {
+		t.Errorf("bounds = %v, want the logical screen", img.Bounds())
+	}
+	if r, _, _, _ := img.At(1, 1).RGBA(); r != 0xffff {
+		t.Errorf("pixel (1,1) = %v, want the red first frame", img.At(1, 1))
+	}
+}
+
+func TestDecodeGIFWithManyFrames(t *testing.T) {
+	g := &gif.GIF{Config: image.Config{Width: 16, Height: 16}}
+	for range 2000 {
+		frame := image.NewPaletted(image.Rect(0, 0, 16, 16), palette.Plan9)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 1)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cutting off the end only breaks frames that are never decoded
+	data := buf.Bytes()[:buf.Len()/2]
+	img, mime, err := Decode(data, DefaultLimits)
+	if err != nil {
+		t.Fatalf("Decode() error = %v, want only the first frame decoded", err)
+	}
+	if mime != "image/gif" || img.Bounds() != image.Rect(0, 0, 16, 16) {
+		t.Errorf("Decode() = %v, %s", img.Bounds(), mime)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	big := encode(t, image.NewNRGBA(image.Rect(0, 0, 100, 100)), "png")
+
+	var tooLarge *TooLargeError
+	if _, _, err := Decode(big, Limits{MaxPixels: 50 * 50}); !errors.As(err, &tooLarge) || tooLarge.Width != 100 {
+		t.Errorf("over MaxPixels error = %v", err)
+	}
+	if _, _, err := Decode(big, Limits{MaxBytes: 10}); !errors.As(err, &tooLarge) || tooLarge.Size != int64(len(big)) {
+		t.Errorf("over MaxBytes error = %v", err)
+	}
+	if _, _, err := Read(bytes.NewReader(big), Limits{MaxBytes: 10}); !errors.As(err, &tooLarge) {
+		t.Errorf("Read over MaxBytes error = %v", err)
+	}
+
+	if _, mime, err := Decode([]byte("<html></html>"), DefaultLimits); !errors.Is(err, ErrUnsupportedFormat) || mime != "text/html" {
+		t.Errorf("HTML error = %v, %s", err, mime)
+	}
+
+	var formatErr *FormatError
+	if _, _, err := Decode(big[:40], DefaultLimits); !errors.As(err, &formatErr) || formatErr.MIME != "image/png" {
+		t.Errorf("truncated PNG error = %v", err)
+	}
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	const mime = "image/bmp"
+	defer func() {
+		decodersMutex.Lock()
+		delete(decoders, mime)
+		decodersMutex.Unlock()
+	}()
+
+	want := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	RegisterDecoder(mime, Decoder{
+		Decode: func(io.Reader) (image.Image, error) { return want, nil },
+	})
+
+	img, got, err := Decode([]byte("BM fake bitmap"), DefaultLimits)
+	if err != nil || got != mime || img != want {
+		t.Errorf("Decode = %v, %s, %v", img, got, err)
+	}
+}
+
+func TestFit(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	fitted := Fit(img, 100, 100)
+	if fitted.Bounds() != image.Rect(0, 0, 100, 25) {
+		t.Errorf("Fit bounds = %v", fitted.Bounds())
+	}
+	if c := color.NRGBAModel.Convert(fitted.At(50, 10)).(color.NRGBA); c != (color.NRGBA{255, 255, 255, 255}) {
+		t.Errorf("resized pixel = %v", c)
+	}
+	if Fit(img, 500, 500) != image.Image(img) {
+		t.Error("Fit enlarged an image that already fits")
+	}
+}
+
+func TestSilhouette(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 255}
+	white := color.NRGBA{255, 255, 255, 255}
+
+	// Transparent artwork: the opaque square is the figure
+	transparent := image.NewNRGBA(image.Rect(10, 10, 14, 14))
+	transparent.Set(11, 11, color.NRGBA{200, 30, 30, 255})
+	transparent.Set(12, 12, color.NRGBA{30, 30, 200, 100})
+	got := Silhouette(transparent, black, white)
+	if got.Bounds() != image.Rect(0, 0, 4, 4) {
+		t.Fatalf("Silhouette bounds = %v", got.Bounds())
+	}
+	if c := got.At(1, 1); c != black {
+		t.Errorf("opaque pixel = %v, want the figure color", c)
+	}
+	if c := got.At(2, 2); c != white {
+		t.Errorf("mostly transparent pixel = %v, want the background color", c)
+	}
+
+	// Opaque artwork: the corner color is the background
+	opaque := image.NewNRGBA(image.Rect(0, 0, 3, 3))
+	for i := 0; i < len(opaque.Pix); i += 4 {
+		copy(opaque.Pix[i:], []byte{250, 250, 245, 255})
+	}
+	opaque.Set(1, 1, color.NRGBA{250, 120, 0, 255})
+	opaque.Set(2, 2, color.NRGBA{240, 255, 250, 255})
+	got = Silhouette(opaque, black, white)
+	if c := got.At(1, 1); c != black {
+		t.Errorf("figure pixel = %v", c)
+	}
+	if c := got.At(2, 2); c != white {
+		t.Errorf("near background pixel = %v", c)
+	}
+}
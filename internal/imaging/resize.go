@@ -0,0 +1,63 @@
+package imaging
+
+import "image"
+
+// FitSize returns the largest size with the aspect ratio of width x height
+// that fits in maxWidth x maxHeight.
+func FitSize(width, height, maxWidth, maxHeight int) (int, int) {
+	if width <= 0 || height <= 0 {
+		return 0, 0
+	}
+	if width*maxHeight > height*maxWidth {
+		return maxWidth, max(1, height*maxWidth/width)
+	}
+	return max(1, width*maxHeight/height), maxHeight
+}
+
+// Fit shrinks img to fit in maxWidth x maxHeight, keeping its aspect ratio.
+// Images that already fit are returned as they are.
+func Fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= maxWidth && bounds.Dy() <= maxHeight {
+		return img
+	}
+	width, height := FitSize(bounds.Dx(), bounds.Dy(), maxWidth, maxHeight)
+	return Resize(img, width, height)
+}
+
+// Resize scales img to width x height, averaging the source pixels under
+// each target pixel.
+func Resize(img image.Image, width, height int) image.Image {
+	src := img.Bounds()
+	if src.Dx() == width && src.Dy() == height {
+		return img
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := max(y0+1, src.Min.Y+(y+1)*src.Dy()/height)
+		for x := range width {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := max(x0+1, src.Min.X+(x+1)*src.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			i := dst.PixOffset(x, y)
+			// Average premultiplied values, then convert back to straight alpha
+			alpha := a / n
+			if alpha > 0 {
+				dst.Pix[i] = uint8(r / n * 0xffff / alpha >> 8)
+				dst.Pix[i+1] = uint8(g / n * 0xffff / alpha >> 8)
+				dst.Pix[i+2] = uint8(b / n * 0xffff / alpha >> 8)
+			}
+			dst.Pix[i+3] = uint8(alpha >> 8)
+		}
+	}
+	return dst
+}
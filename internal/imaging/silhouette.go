@@ -0,0 +1,60 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+)
+
+// backgroundTolerance is how far, per channel, an opaque pixel may be from
+// the corner color and still count as background.
+const backgroundTolerance = 24
+
+// Silhouette paints the figure of img in fg and everything else in bg. With
+// transparency, the figure is every pixel at least half opaque; opaque
+// artwork is taken to have the background color of its top-left corner.
+func Silhouette(img image.Image, fg, bg color.Color) image.Image {
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	if bounds.Empty() {
+		return dst
+	}
+
+	transparent := false
+	for y := bounds.Min.Y; y < bounds.Max.Y && !transparent; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a < 0x8000 {
+				transparent = true
+				break
+			}
+		}
+	}
+	corner := color.NRGBAModel.Convert(img.At(bounds.Min.X, bounds.Min.Y)).(color.NRGBA)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var figure bool
+			if transparent {
+				_, _, _, a := img.At(x, y).RGBA()
+				figure = a >= 0x8000
+			} else {
+				figure = !near(color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA), corner)
+			}
+			if figure {
+				dst.Set(x-bounds.Min.X, y-bounds.Min.Y, fg)
+			} else {
+				dst.Set(x-bounds.Min.X, y-bounds.Min.Y, bg)
+			}
+		}
+	}
+	return dst
+}
+
+func near(a, b color.NRGBA) bool {
+	diff := func(x, y uint8) int {
+		if x > y {
+			return int(x - y)
+		}
+		return int(y - x)
+	}
+	return diff(a.R, b.R) <= backgroundTolerance && diff(a.G, b.G) <= backgroundTolerance && diff(a.B, b.B) <= backgroundTolerance
+}
@@ -0,0 +1,100 @@
+// Package logging sets up the process-wide slog logger: levels, text or
+// JSON output and a rotating log file. The standard log package is routed
+// through the same handler, so older log.Println calls keep working.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxSize    = 10 << 20 // 10 MiB
+	DefaultMaxAge     = 7 * 24 * time.Hour
+	DefaultMaxBackups = 5
+)
+
+type Config struct {
+	// File is the log file, "-" writes to stderr
+	File string
+	// Level is debug, info, warn or error
+	Level string
+	// Format is text or json
+	Format string
+	// MaxSize rotates the file once it grows past this many bytes
+	MaxSize int64
+	// MaxAge rotates the file once it is older than this
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int
+}
+
+// DefaultFile returns the log file in the XDG state directory,
+// $XDG_STATE_HOME/digimontex/digimontex.log or ~/.local/state/... when the
+// variable is unset.
+func DefaultFile() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" || !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join("storage", "logs", "digimontex.log")
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "digimontex", "digimontex.log")
+}
+
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, fmt.Errorf("invalid log level %q, want debug, info, warn or error", level)
+	}
+	return l, nil
+}
+
+// Setup installs the logger described by cfg as the slog and log default.
+// The returned closer flushes and closes the log file.
+func Setup(cfg Config) (io.Closer, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		out    io.Writer
+		closer io.Closer = nopCloser{}
+	)
+	if cfg.File == "-" {
+		out = os.Stderr
+	} else {
+		file, err := NewRotatingFile(cfg.File, cfg.MaxSize, cfg.MaxAge, cfg.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		out, closer = file, file
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		closer.Close()
+		return nil, fmt.Errorf("invalid log format %q, want text or json", cfg.Format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return closer, nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	file, err := NewRotatingFile(path, 10, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current) != "fourth\n" {
+		t.Errorf("current log = %q, want the last line", current)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "test-*.log"))
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want the two newest", backups)
+	}
+	oldest, _ := os.ReadFile(backups[0])
+	if string(oldest) != "second\n" {
+		t.Errorf("oldest kept backup = %q, want the second line", oldest)
+	}
+}
+
+func TestRotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	clock := time.Now()
+	file, err := NewRotatingFile(path, 0, time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.now = func() time.Time { return clock }
+	defer file.Close()
+
+	file.Write([]byte("old\n"))
+	clock = clock.Add(2 * time.Hour)
+	file.Write([]byte("new\n"))
+
+	current, _ := os.ReadFile(path)
+	if string(current) != "new\n" {
+		t.Errorf("current log = %q, want only the new line", current)
+	}
+}
+
+func TestKeepsWritingWhenRotationFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	file, err := NewRotatingFile(path, 10, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.now = func() time.Time { return clock }
+	defer file.Close()
+
+	// A non-empty directory where the backup goes makes the rename fail
+	backup := filepath.Join(dir, "test-"+clock.Format(backupTimeFormat)+".log")
+	if err := os.MkdirAll(filepath.Join(backup, "taken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q) error = %v", line, err)
+		}
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "first\nsecond\nthird\n" {
+		t.Errorf("current log = %q, want every line", current)
+	}
+
+	// Once the way is clear the next write rotates
+	os.RemoveAll(backup)
+	file.Write([]byte("fourth\n"))
+	current, _ = os.ReadFile(path)
+	if string(current) != "fourth\n" {
+		t.Errorf("current log = %q after rotating, want the last line", current)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	closer, err := Setup(Config{File: path, Level: "warn", Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.Info("hidden")
+	slog.Warn("shown", "id", 42)
+	closer.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "hidden") {
+		t.Errorf("info line logged at warn level: %s", data)
+	}
+	if !strings.Contains(string(data), `"msg":"shown","id":42`) {
+		t.Errorf("log = %s, want a JSON warn line", data)
+	}
+
+	if _, err := Setup(Config{File: "-", Level: "loud"}); err == nil {
+		t.Error("Setup accepted an unknown level")
+	}
+	if _, err := Setup(Config{File: "-", Level: "info", Format: "xml"}); err == nil {
+		t.Error("Setup accepted an unknown format")
+	}
+}
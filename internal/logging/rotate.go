@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is appended to the log name of rotated files
+const backupTimeFormat = "20060102-150405.000"
+
+// RotatingFile is an io.Writer over a log file that is moved aside once it
+// grows past maxSize bytes or gets older than maxAge. Only the newest
+// maxBackups rotated files are kept. Zero limits disable that check.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	now        func() time.Time
+
+	mutex sync.Mutex
+	// file is nil after Close, or when a failed rotation could not reopen
+	// the log; Write then tries to open it again unless closed
+	file    *os.File
+	closed  bool
+	size    int64
+	started time.Time
+}
+
+func NewRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.shouldRotate(int64(len(p))) {
+		// A log that cannot be moved aside is still written to, the
+		// rotation is tried again with the next write
+		if err := r.rotate(); err != nil && r.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open appends to an existing log, taking its modification time as a lower
+// bound for its age so restarts do not keep a file alive forever.
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.started = r.now()
+	if info.Size() > 0 && info.ModTime().Before(r.started) {
+		r.started = info.ModTime()
+	}
+	return nil
+}
+
+func (r *RotatingFile) shouldRotate(next int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+next > r.maxSize {
+		return true
+	}
+	return r.maxAge > 0 && r.now().Sub(r.started) > r.maxAge
+}
+
+// rotate assumes the mutex is already locked.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	r.file = nil
+
+	ext := filepath.Ext(r.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.path, ext), r.now().Format(backupTimeFormat), ext)
+	if err := os.Rename(r.path, backup); err != nil {
+		// Keep appending to the log rather than stop logging
+		return errors.Join(fmt.Errorf("failed to rotate log file: %w", err), r.open())
+	}
+
+	r.pruneBackups()
+	return r.open()
+}
+
+func (r *RotatingFile) pruneBackups() {
+	if r.maxBackups <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(r.path)
+	backups, err := filepath.Glob(strings.TrimSuffix(r.path, ext) + "-*" + ext)
+	if err != nil || len(backups) <= r.maxBackups {
+		return
+	}
+	// The timestamp suffix sorts chronologically
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-r.maxBackups] {
+		os.Remove(old)
+	}
+}
@@ -0,0 +1,204 @@
+package matchup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+// Side is a Digimon as far as matchups go: its name and attributes.
+type Side struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Attributes []string `json:"attributes"`
+}
+
+// FromDetail takes the side of d.
+func FromDetail(d models.DigimonDetail) Side {
+	return FromMember(team.NewMember(d))
+}
+
+// FromMember takes the side of a team member.
+func FromMember(m team.Member) Side {
+	name := m.Name
+	if !m.Resolved() {
+		name = m.Label()
+	}
+	return Side{ID: m.ID, Name: name, Attributes: append([]string(nil), m.Attributes...)}
+}
+
+// Label is the side's name with its attributes.
+func (s Side) Label() string {
+	if len(s.Attributes) == 0 {
+		return s.Name + " (no attribute)"
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, strings.Join(s.Attributes, ", "))
+}
+
+// Pair is the outcome of one attribute of A against one of B.
+type Pair struct {
+	A       string  `json:"a"`
+	B       string  `json:"b"`
+	Outcome Outcome `json:"outcome"`
+}
+
+// Result is how A fares against B. Every attribute of A is compared with
+// every attribute of B; Score adds up the pairs and Outcome is its sign.
+type Result struct {
+	A       Side    `json:"a"`
+	B       Side    `json:"b"`
+	Pairs   []Pair  `json:"pairs"`
+	Score   int     `json:"score"`
+	Outcome Outcome `json:"outcome"`
+}
+
+// Compare matches a against b.
+func Compare(rules *Rules, a, b Side) Result {
+	result := Result{A: a, B: b}
+	for _, attrA := range a.Attributes {
+		for _, attrB := range b.Attributes {
+			outcome := rules.Outcome(attrA, attrB)
+			result.Pairs = append(result.Pairs, Pair{A: attrA, B: attrB, Outcome: outcome})
+			result.Score += int(outcome)
+		}
+	}
+	switch {
+	case result.Score > 0:
+		result.Outcome = Advantage
+	case result.Score < 0:
+		result.Outcome = Disadvantage
+	}
+	return result
+}
+
+// Text explains the result, one line per attribute pair.
+func (r Result) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s vs %s: %s for %s\n", r.A.Label(), r.B.Label(), r.Outcome, r.A.Name)
+	if len(r.Pairs) == 0 {
+		b.WriteString("  no attributes to compare\n")
+	}
+	for _, pair := range r.Pairs {
+		switch pair.Outcome {
+		case Advantage:
+			fmt.Fprintf(&b, "  %s beats %s\n", pair.A, pair.B)
+		case Disadvantage:
+			fmt.Fprintf(&b, "  %s loses to %s\n", pair.A, pair.B)
+		default:
+			fmt.Fprintf(&b, "  %s and %s are even\n", pair.A, pair.B)
+		}
+	}
+	return b.String()
+}
+
+// Matrix matches every row side against every column side.
+type Matrix struct {
+	Rows  []Side     `json:"rows"`
+	Cols  []Side     `json:"cols"`
+	Cells [][]Result `json:"cells"`
+}
+
+// NewMatrix compares rows against cols.
+func NewMatrix(rules *Rules, rows, cols []Side) Matrix {
+	m := Matrix{Rows: rows, Cols: cols, Cells: make([][]Result, len(rows))}
+	for i, row := range rows {
+		m.Cells[i] = make([]Result, len(cols))
+		for j, col := range cols {
+			m.Cells[i][j] = Compare(rules, row, col)
+		}
+	}
+	return m
+}
+
+// Tally counts the outcomes of the matrix for the row side.
+type Tally struct {
+	Advantages    int `json:"advantages"`
+	Neutral       int `json:"neutral"`
+	Disadvantages int `json:"disadvantages"`
+}
+
+func (t *Tally) add(outcome Outcome) {
+	switch outcome {
+	case Advantage:
+		t.Advantages++
+	case Disadvantage:
+		t.Disadvantages++
+	default:
+		t.Neutral++
+	}
+}
+
+// Outcome is the overall outcome for the row side.
+func (t Tally) Outcome() Outcome {
+	switch {
+	case t.Advantages > t.Disadvantages:
+		return Advantage
+	case t.Advantages < t.Disadvantages:
+		return Disadvantage
+	}
+	return Neutral
+}
+
+func (t Tally) String() string {
+	return fmt.Sprintf("%s, %d neutral, %s", plural(t.Advantages, "advantage"), t.Neutral, plural(t.Disadvantages, "disadvantage"))
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Tally counts every cell of the matrix.
+func (m Matrix) Tally() Tally {
+	var tally Tally
+	for _, row := range m.Cells {
+		for _, cell := range row {
+			tally.add(cell.Outcome)
+		}
+	}
+	return tally
+}
+
+// RowTally counts the cells of row i.
+func (m Matrix) RowTally(i int) Tally {
+	var tally Tally
+	for _, cell := range m.Cells[i] {
+		tally.add(cell.Outcome)
+	}
+	return tally
+}
+
+// Text renders the matrix as a grid of outcome symbols with a tally per
+// row and overall.
+func (m Matrix) Text() string {
+	nameWidth := 0
+	for _, row := range m.Rows {
+		nameWidth = max(nameWidth, len([]rune(row.Name)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s", nameWidth, "")
+	for j := range m.Cols {
+		fmt.Fprintf(&b, " %2d", j+1)
+	}
+	b.WriteString("\n")
+	for i, row := range m.Rows {
+		fmt.Fprintf(&b, "%-*s", nameWidth, row.Name)
+		for _, cell := range m.Cells[i] {
+			fmt.Fprintf(&b, "  %s", cell.Outcome.Symbol())
+		}
+		tally := m.RowTally(i)
+		fmt.Fprintf(&b, "   +%d =%d -%d\n", tally.Advantages, tally.Neutral, tally.Disadvantages)
+	}
+	b.WriteString("\n")
+	for j, col := range m.Cols {
+		fmt.Fprintf(&b, "%2d %s\n", j+1, col.Label())
+	}
+	tally := m.Tally()
+	fmt.Fprintf(&b, "\nOverall: %s (%s)\n", tally.Outcome(), tally)
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package matchup
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want Outcome
+	}{
+		{"Vaccine", "Virus", Advantage},
+		{"Virus", "Data", Advantage},
+		{"data", "VACCINE", Advantage},
+		{"Virus", "Vaccine", Disadvantage},
+		{"Vaccine", "Vaccine", Neutral},
+		{"Free", "Virus", Neutral},
+	}
+	for _, tt := range tests {
+		if got := DefaultRules.Outcome(tt.a, tt.b); got != tt.want {
+			t.Errorf("Outcome(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader(`
+# The triangle plus Free beating Variable
+Vaccine > Virus
+Virus > Data
+Data > Vaccine
+Free > Variable
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rules.Outcome("Variable", "Free"); got != Disadvantage {
+		t.Errorf("Outcome(Variable, Free) = %v", got)
+	}
+	if got := strings.Join(rules.Attributes(), ","); got != "Data,Free,Vaccine,Variable,Virus" {
+		t.Errorf("Attributes() = %s", got)
+	}
+
+	for _, bad := range []string{"Vaccine Virus", "Vaccine > Vaccine", "Vaccine > Virus\nVirus > Vaccine", "> Data"} {
+		if _, err := ParseRules(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseRules(%q) accepted invalid rules", bad)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	agumon := Side{Name: "Agumon", Attributes: []string{"Vaccine"}}
+	devimon := Side{Name: "Devimon", Attributes: []string{"Virus"}}
+	mixed := Side{Name: "Mixed", Attributes: []string{"Data", "Virus"}}
+	free := Side{Name: "Free"}
+
+	if r := Compare(DefaultRules, agumon, devimon); r.Outcome != Advantage || r.Score != 1 {
+		t.Errorf("Agumon vs Devimon = %+v", r)
+	}
+	if r := Compare(DefaultRules, devimon, agumon); r.Outcome != Disadvantage {
+		t.Errorf("Devimon vs Agumon = %+v", r)
+	}
+	// Vaccine loses to Data and beats Virus
+	r := Compare(DefaultRules, agumon, mixed)
+	if r.Outcome != Neutral || len(r.Pairs) != 2 {
+		t.Errorf("Agumon vs Mixed = %+v", r)
+	}
+	text := r.Text()
+	for _, want := range []string{"Agumon (Vaccine) vs Mixed (Data, Virus): neutral", "Vaccine loses to Data", "Vaccine beats Virus"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() is missing %q:\n%s", want, text)
+		}
+	}
+	if r := Compare(DefaultRules, agumon, free); r.Outcome != Neutral || !strings.Contains(r.Text(), "no attributes") {
+		t.Errorf("Agumon vs Free = %+v", r)
+	}
+
+	data, err := json.Marshal(Compare(DefaultRules, agumon, devimon))
+	if err != nil || !strings.Contains(string(data), `"outcome":"advantage"`) {
+		t.Errorf("json.Marshal() = %s, %v", data, err)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	rows := []Side{
+		{Name: "Agumon", Attributes: []string{"Vaccine"}},
+		{Name: "Gabumon", Attributes: []string{"Data"}},
+	}
+	cols := []Side{
+		{Name: "Devimon", Attributes: []string{"Virus"}},
+		{Name: "Tentomon", Attributes: []string{"Vaccine"}},
+		{Name: "Patamon", Attributes: []string{"Data"}},
+	}
+	m := NewMatrix(DefaultRules, rows, cols)
+
+	if got := m.Cells[1][0].Outcome; got != Disadvantage {
+		t.Errorf("Gabumon vs Devimon = %v", got)
+	}
+	if got := m.RowTally(0); got != (Tally{Advantages: 1, Neutral: 1, Disadvantages: 1}) {
+		t.Errorf("RowTally(0) = %+v", got)
+	}
+	tally := m.Tally()
+	if tally != (Tally{Advantages: 2, Neutral: 2, Disadvantages: 2}) || tally.Outcome() != Neutral {
+		t.Errorf("Tally() = %+v", tally)
+	}
+
+	text := m.Text()
+	for _, want := range []string{"Agumon   +  =  -   +1 =1 -1", " 1 Devimon (Virus)", "Overall: neutral"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	details := fixtures.Details(t, "Agumon", "Devimon")
+	find := func(match func(models.DigimonDetail) bool) (models.DigimonDetail, error) {
+		for _, detail := range details {
+			if match(detail) {
+				return detail, nil
+			}
+		}
+		return models.DigimonDetail{}, errors.New("not found")
+	}
+	current := team.Team{Members: []team.Member{{ID: 13}}}
+	r := Resolver{
+		ByID: func(id int) (models.DigimonDetail, error) {
+			return find(func(d models.DigimonDetail) bool { return d.ID == id })
+		},
+		ByName: func(name string) (models.DigimonDetail, error) {
+			return find(func(d models.DigimonDetail) bool { return strings.EqualFold(d.Name, name) })
+		},
+		Current: &current,
+	}
+
+	if lineup, err := r.Resolve("agumon"); err != nil || lineup.Team || lineup.Sides[0].Attributes[0] != "Vaccine" {
+		t.Errorf("Resolve(agumon) = %+v, %v", lineup, err)
+	}
+	if lineup, err := r.Resolve("13"); err != nil || lineup.Name != "Devimon" {
+		t.Errorf("Resolve(13) = %+v, %v", lineup, err)
+	}
+	code := team.Encode(team.Team{Name: "Pair", Members: []team.Member{{ID: 3}, {ID: 13}}})
+	if lineup, err := r.Resolve(code); err != nil || !lineup.Team || lineup.Name != "Pair" || len(lineup.Sides) != 2 {
+		t.Errorf("Resolve(code) = %+v, %v", lineup, err)
+	}
+	if lineup, err := r.Resolve("Team"); err != nil || lineup.Name != "Current team" || lineup.Sides[0].Name != "Devimon" {
+		t.Errorf("Resolve(team) = %+v, %v", lineup, err)
+	}
+	if current.Members[0].Resolved() {
+		t.Error("Resolve(team) changed the current team")
+	}
+	if _, err := r.Resolve("Omnimon"); err == nil {
+		t.Error("Resolve(Omnimon) found an unknown Digimon")
+	}
+}
@@ -0,0 +1,103 @@
+package matchup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/team"
+)
+
+// CurrentTeam is the input that stands for the team being built.
+const CurrentTeam = "team"
+
+// Lineup is one side of a matchup: a single Digimon or a team.
+type Lineup struct {
+	Name  string
+	Sides []Side
+	// Team is set for teams, also for one-member teams
+	Team bool
+}
+
+// Resolver turns the inputs of the vs view and the matchup command into
+// lineups.
+type Resolver struct {
+	// ByID and ByName look up a Digimon
+	ByID   func(id int) (models.DigimonDetail, error)
+	ByName func(name string) (models.DigimonDetail, error)
+	// Teams are the saved teams, nil for none
+	Teams *team.Book
+	// Current is the team being built, nil for none
+	Current *team.Team
+}
+
+// Resolve reads input as, in this order: "team" for the team being built, a
+// team code, the name of a saved team, a team JSON file, a Digimon ID or a
+// Digimon name.
+func (r Resolver) Resolve(input string) (Lineup, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return Lineup{}, errors.New("enter a Digimon, a team code or a saved team")
+	}
+
+	switch {
+	case strings.EqualFold(input, CurrentTeam) && r.Current != nil:
+		t := *r.Current
+		t.Members = append([]team.Member(nil), t.Members...)
+		if t.Name == "" {
+			t.Name = "Current team"
+		}
+		return r.teamLineup(t)
+	case team.IsCode(input):
+		t, err := team.Decode(input)
+		if err != nil {
+			return Lineup{}, err
+		}
+		return r.teamLineup(t)
+	}
+	if r.Teams != nil {
+		if t, ok := r.Teams.Get(input); ok {
+			return r.teamLineup(t)
+		}
+	}
+	if strings.HasSuffix(strings.ToLower(input), ".json") {
+		t, err := team.Import(input)
+		if err != nil {
+			return Lineup{}, err
+		}
+		return r.teamLineup(t)
+	}
+
+	var detail models.DigimonDetail
+	var err error
+	if id, convErr := strconv.Atoi(input); convErr == nil {
+		detail, err = r.ByID(id)
+	} else {
+		detail, err = r.ByName(input)
+	}
+	if err != nil {
+		return Lineup{}, fmt.Errorf("failed to find %q: %w", input, err)
+	}
+	side := FromDetail(detail)
+	return Lineup{Name: side.Name, Sides: []Side{side}}, nil
+}
+
+// teamLineup looks up the members of t that only have an ID.
+func (r Resolver) teamLineup(t team.Team) (Lineup, error) {
+	if len(t.Members) == 0 {
+		return Lineup{}, fmt.Errorf("team %q has no Digimon", t.Name)
+	}
+	if err := t.Resolve(r.ByID); err != nil {
+		return Lineup{}, err
+	}
+	lineup := Lineup{Name: t.Name, Team: true}
+	if lineup.Name == "" {
+		lineup.Name = "Team"
+	}
+	for _, member := range t.Members {
+		lineup.Sides = append(lineup.Sides, FromMember(member))
+	}
+	return lineup, nil
+}
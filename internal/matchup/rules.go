@@ -0,0 +1,186 @@
+// Package matchup compares Digimon and teams by attribute with a rule
+// table, by default the Vaccine > Virus > Data > Vaccine triangle.
+package matchup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Outcome is the result of one side against the other.
+type Outcome int
+
+const (
+	Disadvantage Outcome = -1
+	Neutral      Outcome = 0
+	Advantage    Outcome = 1
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Advantage:
+		return "advantage"
+	case Disadvantage:
+		return "disadvantage"
+	}
+	return "neutral"
+}
+
+// MarshalText writes outcomes by name in JSON.
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// Symbol is the one character form of o used in matrices.
+func (o Outcome) Symbol() string {
+	switch o {
+	case Advantage:
+		return "+"
+	case Disadvantage:
+		return "-"
+	}
+	return "="
+}
+
+// Rule is one line of the rule table: Winner has the advantage over Loser.
+type Rule struct {
+	Winner string `json:"winner"`
+	Loser  string `json:"loser"`
+}
+
+func (r Rule) String() string {
+	return r.Winner + " > " + r.Loser
+}
+
+// Rules is a rule table. Attributes are compared case-insensitively and
+// pairs without a rule are neutral.
+type Rules struct {
+	rules []Rule
+	beats map[[2]string]bool
+}
+
+// DefaultRules is the attribute triangle.
+var DefaultRules = MustRules(
+	Rule{"Vaccine", "Virus"},
+	Rule{"Virus", "Data"},
+	Rule{"Data", "Vaccine"},
+)
+
+// NewRules builds a rule table. A rule of an attribute against itself and
+// two rules contradicting each other are errors.
+func NewRules(rules ...Rule) (*Rules, error) {
+	r := &Rules{beats: make(map[[2]string]bool)}
+	for _, rule := range rules {
+		winner, loser := normalize(rule.Winner), normalize(rule.Loser)
+		if winner == "" || loser == "" {
+			return nil, fmt.Errorf("rule %q needs two attributes", rule)
+		}
+		if winner == loser {
+			return nil, fmt.Errorf("rule %q compares an attribute with itself", rule)
+		}
+		if r.beats[[2]string{loser, winner}] {
+			return nil, fmt.Errorf("rule %q contradicts %s > %s", rule, rule.Loser, rule.Winner)
+		}
+		if !r.beats[[2]string{winner, loser}] {
+			r.beats[[2]string{winner, loser}] = true
+			r.rules = append(r.rules, Rule{strings.TrimSpace(rule.Winner), strings.TrimSpace(rule.Loser)})
+		}
+	}
+	return r, nil
+}
+
+// MustRules is NewRules for tables known to be valid.
+func MustRules(rules ...Rule) *Rules {
+	r, err := NewRules(rules...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// ParseRules reads a rule table with one "Winner > Loser" rule per line.
+// Blank lines and lines starting with # are skipped.
+func ParseRules(r io.Reader) (*Rules, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		winner, loser, ok := strings.Cut(text, ">")
+		if !ok {
+			return nil, fmt.Errorf("line %d: want \"Winner > Loser\", got %q", line, text)
+		}
+		rules = append(rules, Rule{Winner: winner, Loser: loser})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	return NewRules(rules...)
+}
+
+// LoadRules reads the rule table at path.
+func LoadRules(path string) (*Rules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rules: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := ParseRules(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Rules returns the rules in the order they were given.
+func (r *Rules) Rules() []Rule {
+	return append([]Rule(nil), r.rules...)
+}
+
+// String lists the rules, one per line.
+func (r *Rules) String() string {
+	lines := make([]string, len(r.rules))
+	for i, rule := range r.rules {
+		lines[i] = rule.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Attributes returns the attributes the rules mention, sorted.
+func (r *Rules) Attributes() []string {
+	seen := make(map[string]bool)
+	var attributes []string
+	for _, rule := range r.rules {
+		for _, attribute := range []string{rule.Winner, rule.Loser} {
+			if !seen[normalize(attribute)] {
+				seen[normalize(attribute)] = true
+				attributes = append(attributes, attribute)
+			}
+		}
+	}
+	sort.Strings(attributes)
+	return attributes
+}
+
+// Outcome returns how attribute a fares against attribute b.
+func (r *Rules) Outcome(a, b string) Outcome {
+	a, b = normalize(a), normalize(b)
+	switch {
+	case r.beats[[2]string{a, b}]:
+		return Advantage
+	case r.beats[[2]string{b, a}]:
+		return Disadvantage
+	}
+	return Neutral
+}
+
+func normalize(attribute string) string {
+	return strings.ToLower(strings.TrimSpace(attribute))
+}
@@ -0,0 +1,322 @@
+// Package metrics keeps the process counters shared by the TUI status bar
+// and the /metrics endpoint of the long-running modes. Metrics are written
+// in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default holds the metrics below. Everything in the process reports to it.
+var Default = NewRegistry()
+
+var (
+	UpstreamRequests = Default.NewCounter("digimontex_upstream_requests_total",
+		"Requests made to the Digi-API by endpoint and status code (\"error\" for transport failures).",
+		"endpoint", "status")
+	UpstreamDuration = Default.NewHistogram("digimontex_upstream_request_duration_seconds",
+		"Latency of Digi-API requests by endpoint.",
+		[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		"endpoint")
+	UpstreamRetries = Default.NewCounter("digimontex_upstream_retries_total",
+		"Digi-API requests retried after a transport error or a 429/5xx answer.",
+		"endpoint")
+
+	CacheHits = Default.NewCounter("digimontex_cache_hits_total",
+		"Cache lookups that found an entry.", "cache")
+	CacheMisses = Default.NewCounter("digimontex_cache_misses_total",
+		"Cache lookups that found nothing.", "cache")
+	CacheEvictions = Default.NewCounter("digimontex_cache_evictions_total",
+		"Entries dropped to make room for newer ones.", "cache")
+	CacheSize = Default.NewGauge("digimontex_cache_entries",
+		"Entries currently held.", "cache")
+
+	SyncTotal = Default.NewGauge("digimontex_sync_digimon_total",
+		"Digimon listed by the API in the current sync run.")
+	SyncDone = Default.NewCounter("digimontex_sync_digimon_synced_total",
+		"Digimon stored by the current sync run, including ones already up to date.")
+	SyncErrors = Default.NewCounter("digimontex_sync_errors_total",
+		"Digimon the current sync run failed to fetch.")
+)
+
+type metric interface {
+	write(w io.Writer)
+}
+
+// Registry is a set of metrics written out in registration order.
+type Registry struct {
+	mutex   sync.Mutex
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText writes every metric in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) {
+	r.mutex.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mutex.Unlock()
+
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// Mount registers GET /metrics for the default registry on mux and, when
+// withPprof is set, the /debug/pprof/ handlers.
+func Mount(mux *http.ServeMux, withPprof bool) {
+	mux.Handle("GET /metrics", Default.Handler())
+	if !withPprof {
+		return
+	}
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+}
+
+// vec holds one value per label combination.
+type vec struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	mutex      sync.Mutex
+	values     map[string]float64
+}
+
+func newVec(name, help, kind string, labelNames []string) *vec {
+	return &vec{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		values:     make(map[string]float64),
+	}
+}
+
+func (v *vec) key(labels []string) string {
+	if len(labels) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s takes %d labels, got %d", v.name, len(v.labelNames), len(labels)))
+	}
+	return strings.Join(labels, "\xff")
+}
+
+func (v *vec) add(delta float64, labels []string) {
+	key := v.key(labels)
+	v.mutex.Lock()
+	v.values[key] += delta
+	v.mutex.Unlock()
+}
+
+func (v *vec) set(value float64, labels []string) {
+	key := v.key(labels)
+	v.mutex.Lock()
+	v.values[key] = value
+	v.mutex.Unlock()
+}
+
+func (v *vec) value(labels []string) float64 {
+	key := v.key(labels)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.values[key]
+}
+
+func (v *vec) sum() float64 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	var total float64
+	for _, value := range v.values {
+		total += value
+	}
+	return total
+}
+
+func (v *vec) write(w io.Writer) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, v.kind)
+	if len(v.labelNames) == 0 {
+		fmt.Fprintf(w, "%s %s\n", v.name, formatValue(v.values[""]))
+		return
+	}
+	for _, key := range sortedKeys(v.values) {
+		fmt.Fprintf(w, "%s%s %s\n", v.name, formatLabels(v.labelNames, strings.Split(key, "\xff"), "", ""), formatValue(v.values[key]))
+	}
+}
+
+// Counter only goes up.
+type Counter struct{ v *vec }
+
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	c := &Counter{newVec(name, help, "counter", labelNames)}
+	r.register(c.v)
+	return c
+}
+
+func (c *Counter) Inc(labels ...string) { c.v.add(1, labels) }
+
+func (c *Counter) Add(delta float64, labels ...string) {
+	if delta < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	c.v.add(delta, labels)
+}
+
+func (c *Counter) Value(labels ...string) float64 { return c.v.value(labels) }
+
+// Sum returns the total over every label combination.
+func (c *Counter) Sum() float64 { return c.v.sum() }
+
+// Gauge goes up and down.
+type Gauge struct{ v *vec }
+
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	g := &Gauge{newVec(name, help, "gauge", labelNames)}
+	r.register(g.v)
+	return g
+}
+
+func (g *Gauge) Set(value float64, labels ...string) { g.v.set(value, labels) }
+func (g *Gauge) Add(delta float64, labels ...string) { g.v.add(delta, labels) }
+func (g *Gauge) Value(labels ...string) float64      { return g.v.value(labels) }
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+	mutex      sync.Mutex
+	series     map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	h := &Histogram{
+		name:       name,
+		help:       help,
+		buckets:    buckets,
+		labelNames: labelNames,
+		series:     make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+func (h *Histogram) Observe(value float64, labels ...string) {
+	if len(labels) != len(h.labelNames) {
+		panic(fmt.Sprintf("metrics: %s takes %d labels, got %d", h.name, len(h.labelNames), len(labels)))
+	}
+	key := strings.Join(labels, "\xff")
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// Count returns the number of observations with the given labels.
+func (h *Histogram) Count(labels ...string) uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if s, ok := h.series[strings.Join(labels, "\xff")]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		labels := strings.Split(key, "\xff")
+		if len(h.labelNames) == 0 {
+			labels = nil
+		}
+		for i, bound := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, labels, "le", formatValue(bound)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, labels, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labelNames, labels, "", ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labelNames, labels, "", ""), s.count)
+	}
+}
+
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, strconv.Quote(values[i])))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", extraName, strconv.Quote(extraValue)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
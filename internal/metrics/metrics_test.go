@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounter("test_requests_total", "Requests.", "endpoint", "status")
+	size := r.NewGauge("test_size", "Size.")
+	latency := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1}, "endpoint")
+
+	requests.Inc("detail", "200")
+	requests.Inc("detail", "200")
+	requests.Inc("list", "error")
+	size.Set(3)
+	latency.Observe(0.05, "list")
+	latency.Observe(0.5, "list")
+
+	var b strings.Builder
+	r.WriteText(&b)
+	want := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{endpoint="detail",status="200"} 2
+test_requests_total{endpoint="list",status="error"} 1
+# HELP test_size Size.
+# TYPE test_size gauge
+test_size 3
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{endpoint="list",le="0.1"} 1
+test_latency_seconds_bucket{endpoint="list",le="1"} 2
+test_latency_seconds_bucket{endpoint="list",le="+Inf"} 2
+test_latency_seconds_sum{endpoint="list"} 0.55
+test_latency_seconds_count{endpoint="list"} 2
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := requests.Sum(); got != 3 {
+		t.Errorf("Sum() = %v, want 3", got)
+	}
+	if got := requests.Value("detail", "200"); got != 2 {
+		t.Errorf("Value(detail, 200) = %v, want 2", got)
+	}
+}
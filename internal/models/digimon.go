@@ -61,23 +61,46 @@ type DigimonDetail struct {
 		Description string `json:"description"`
 	} `json:"descriptions"`
 	Skills []struct {
-		ID int `json:"id"`
-		Skill string `json:"skill"`
+		ID          int    `json:"id"`
+		Skill       string `json:"skill"`
 		Translation string `json:"translation"`
 		Description string `json:"description"`
 	} `json:"skills"`
 	PriorEvolutions []struct {
-        ID        int    `json:"id"`
-        Digimon   string `json:"digimon"`
-        Condition string `json:"condition"`
-        Image     string `json:"image"`
-        URL       string `json:"url"`
-    } `json:"priorEvolutions"`
+		ID        int    `json:"id"`
+		Digimon   string `json:"digimon"`
+		Condition string `json:"condition"`
+		Image     string `json:"image"`
+		URL       string `json:"url"`
+	} `json:"priorEvolutions"`
 	NextEvolutions []struct {
-        ID        int    `json:"id"`
-        Digimon   string `json:"digimon"`
-        Condition string `json:"condition"`
-        Image     string `json:"image"`
-        URL       string `json:"url"`
-    } `json:"nextEvolutions"`
+		ID        int    `json:"id"`
+		Digimon   string `json:"digimon"`
+		Condition string `json:"condition"`
+		Image     string `json:"image"`
+		URL       string `json:"url"`
+	} `json:"nextEvolutions"`
+}
+
+// Field is an entry of the /field reference endpoint.
+type Field struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
+
+type FieldResponse struct {
+	Content struct {
+		Name        string  `json:"name"`
+		Description string  `json:"description"`
+		Fields      []Field `json:"fields"`
+	} `json:"content"`
+	Pageable struct {
+		CurrentPage    int    `json:"currentPage"`
+		ElementsOnPage int    `json:"elementsOnPage"`
+		TotalElements  int    `json:"totalElements"`
+		TotalPages     int    `json:"totalPages"`
+		PreviousPage   string `json:"previousPage"`
+		NextPage       string `json:"nextPage"`
+	} `json:"pageable"`
 }
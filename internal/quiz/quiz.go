@@ -0,0 +1,263 @@
+// Package quiz makes multiple choice questions about Digimon from their
+// details. A seed fixes the question sequence, so tests and shared games
+// can replay it.
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// Choices is the most answers a question offers.
+const Choices = 4
+
+// ErrNotEnoughData is returned when the details cannot make any question.
+var ErrNotEnoughData = errors.New("not enough Digimon data for a quiz")
+
+// Kind is the type of a question.
+type Kind int
+
+const (
+	Evolution Kind = iota
+	Level
+	Silhouette
+	Field
+)
+
+// Kinds lists every kind of question.
+var Kinds = []Kind{Evolution, Level, Silhouette, Field}
+
+func (k Kind) String() string {
+	switch k {
+	case Evolution:
+		return "evolution"
+	case Level:
+		return "level"
+	case Silhouette:
+		return "silhouette"
+	case Field:
+		return "field"
+	}
+	return "unknown"
+}
+
+// Question is a multiple choice question about Subject.
+type Question struct {
+	Kind    Kind
+	Prompt  string
+	Subject models.DigimonDetail
+	// ImageURL is the artwork a silhouette question shows
+	ImageURL string
+	Choices  []string
+	// Answer is the index of the right choice
+	Answer int
+}
+
+// Correct reports whether choice is the right answer.
+func (q Question) Correct(choice int) bool {
+	return choice == q.Answer
+}
+
+// AnswerText is the right choice.
+func (q Question) AnswerText() string {
+	return q.Choices[q.Answer]
+}
+
+// Generator makes random questions from a fixed set of details.
+type Generator struct {
+	rng     *rand.Rand
+	details []models.DigimonDetail
+	names   []string
+	levels  []string
+	fields  []string
+	// subjects are the indexes of the details each kind can ask about
+	subjects map[Kind][]int
+	kinds    []Kind
+}
+
+// NewGenerator prepares questions of the given kinds, all kinds when none
+// are given, about details. The same details and seed always give the same
+// questions, whatever order the details come in.
+func NewGenerator(details []models.DigimonDetail, seed int64, kinds ...Kind) (*Generator, error) {
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+	g := &Generator{
+		rng:      rand.New(rand.NewSource(seed)),
+		details:  append([]models.DigimonDetail(nil), details...),
+		subjects: make(map[Kind][]int),
+	}
+	sort.Slice(g.details, func(i, j int) bool { return g.details[i].ID < g.details[j].ID })
+
+	names, levels, fields := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, d := range g.details {
+		if name := strings.TrimSpace(d.Name); name != "" {
+			names[name] = true
+		}
+		for _, level := range levelsOf(d) {
+			levels[level] = true
+		}
+		for _, field := range fieldsOf(d) {
+			fields[field] = true
+		}
+	}
+	g.names, g.levels, g.fields = sortedKeys(names), sortedKeys(levels), sortedKeys(fields)
+
+	for i, d := range g.details {
+		if strings.TrimSpace(d.Name) == "" {
+			continue
+		}
+		for _, kind := range Kinds {
+			answers, excluded, pool := g.options(kind, d)
+			if len(answers) > 0 && hasOther(pool, excluded) {
+				g.subjects[kind] = append(g.subjects[kind], i)
+			}
+		}
+	}
+	for _, kind := range kinds {
+		if len(g.subjects[kind]) > 0 {
+			g.kinds = append(g.kinds, kind)
+		}
+	}
+	if len(g.kinds) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	return g, nil
+}
+
+// Next makes the next question.
+func (g *Generator) Next() Question {
+	kind := g.kinds[g.rng.Intn(len(g.kinds))]
+	subjects := g.subjects[kind]
+	d := g.details[subjects[g.rng.Intn(len(subjects))]]
+
+	q := Question{Kind: kind, Subject: d}
+	switch kind {
+	case Evolution:
+		q.Prompt = fmt.Sprintf("Which Digimon evolves into %s?", d.Name)
+	case Level:
+		q.Prompt = fmt.Sprintf("What level is %s?", d.Name)
+	case Silhouette:
+		q.Prompt = "Which Digimon is this?"
+		q.ImageURL = imageURL(d)
+	case Field:
+		q.Prompt = fmt.Sprintf("Which field does %s belong to?", d.Name)
+	}
+
+	answers, excluded, pool := g.options(kind, d)
+	right := answers[g.rng.Intn(len(answers))]
+	q.Choices = append(q.Choices, right)
+	for _, i := range g.rng.Perm(len(pool)) {
+		if len(q.Choices) == Choices {
+			break
+		}
+		if !containsFold(excluded, pool[i]) {
+			q.Choices = append(q.Choices, pool[i])
+		}
+	}
+	g.rng.Shuffle(len(q.Choices), func(i, j int) {
+		q.Choices[i], q.Choices[j] = q.Choices[j], q.Choices[i]
+	})
+	for i, choice := range q.Choices {
+		if choice == right {
+			q.Answer = i
+		}
+	}
+	return q
+}
+
+// options returns the right answers about d for a kind of question, the
+// values no wrong choice may take and the pool wrong choices come from.
+func (g *Generator) options(kind Kind, d models.DigimonDetail) (answers, excluded, pool []string) {
+	switch kind {
+	case Evolution:
+		answers = priorNames(d)
+		// The subject itself is no wrong choice either
+		return answers, append(append([]string(nil), answers...), d.Name), g.names
+	case Level:
+		answers = levelsOf(d)
+		return answers, answers, g.levels
+	case Silhouette:
+		if imageURL(d) == "" {
+			return nil, nil, nil
+		}
+		return []string{d.Name}, []string{d.Name}, g.names
+	case Field:
+		answers = fieldsOf(d)
+		return answers, answers, g.fields
+	}
+	return nil, nil, nil
+}
+
+// hasOther reports whether pool has a value outside excluded.
+func hasOther(pool, excluded []string) bool {
+	for _, value := range pool {
+		if !containsFold(excluded, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func priorNames(d models.DigimonDetail) []string {
+	var names []string
+	for _, evolution := range d.PriorEvolutions {
+		name := strings.TrimSpace(evolution.Digimon)
+		if name != "" && !containsFold(names, name) && !strings.EqualFold(name, d.Name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func levelsOf(d models.DigimonDetail) []string {
+	var levels []string
+	for _, level := range d.Levels {
+		if name := strings.TrimSpace(level.Level); name != "" && !containsFold(levels, name) {
+			levels = append(levels, name)
+		}
+	}
+	return levels
+}
+
+func fieldsOf(d models.DigimonDetail) []string {
+	var fields []string
+	for _, field := range d.Fields {
+		if name := strings.TrimSpace(field.Field); name != "" && !containsFold(fields, name) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func imageURL(d models.DigimonDetail) string {
+	for _, img := range d.Images {
+		if href := strings.TrimSpace(img.Href); href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+package quiz
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// quizzed are the fixture Digimon the generator draws its questions from.
+var quizzed = []string{"Koromon", "Agumon", "Greymon", "Devimon", "Gabumon"}
+
+func TestQuestionsAreValid(t *testing.T) {
+	details := fixtures.Details(t, quizzed...)
+	g, err := NewGenerator(details, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := make(map[Kind]bool)
+	for range 200 {
+		q := g.Next()
+		kinds[q.Kind] = true
+		if len(q.Choices) < 2 || len(q.Choices) > Choices {
+			t.Fatalf("%q has %d choices", q.Prompt, len(q.Choices))
+		}
+		if len(slices.Compact(slices.Sorted(slices.Values(q.Choices)))) != len(q.Choices) {
+			t.Fatalf("%q repeats a choice: %v", q.Prompt, q.Choices)
+		}
+
+		d := q.Subject
+		right := q.AnswerText()
+		switch q.Kind {
+		case Evolution:
+			if !slices.Contains(priorNames(d), right) || slices.Contains(q.Choices, d.Name) {
+				t.Fatalf("%q: choices %v, answer %q", q.Prompt, q.Choices, right)
+			}
+		case Level:
+			if right != d.Levels[0].Level {
+				t.Fatalf("%q: answer %q", q.Prompt, right)
+			}
+		case Silhouette:
+			if right != d.Name || q.ImageURL == "" {
+				t.Fatalf("silhouette of %s: answer %q, image %q", d.Name, right, q.ImageURL)
+			}
+		case Field:
+			for i, choice := range q.Choices {
+				if i != q.Answer && slices.Contains(fieldsOf(d), choice) {
+					t.Fatalf("%q: wrong choice %q is one of its fields", q.Prompt, choice)
+				}
+			}
+		}
+		if !q.Correct(q.Answer) || q.Correct((q.Answer+1)%len(q.Choices)) {
+			t.Fatalf("Correct() disagrees with Answer for %q", q.Prompt)
+		}
+	}
+	if len(kinds) != len(Kinds) {
+		t.Errorf("200 questions only asked %v", kinds)
+	}
+}
+
+func TestSeedReproducesQuestions(t *testing.T) {
+	details := fixtures.Details(t, quizzed...)
+	sequence := func(seed int64, details []models.DigimonDetail) []Question {
+		g, err := NewGenerator(details, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var questions []Question
+		for range 20 {
+			questions = append(questions, g.Next())
+		}
+		return questions
+	}
+
+	first := sequence(42, details)
+	reversed := slices.Clone(details)
+	slices.Reverse(reversed)
+	if !reflect.DeepEqual(first, sequence(42, reversed)) {
+		t.Error("the same seed gave different questions")
+	}
+	if reflect.DeepEqual(first, sequence(43, details)) {
+		t.Error("another seed gave the same questions")
+	}
+}
+
+func TestGeneratorKinds(t *testing.T) {
+	details := fixtures.Details(t, quizzed...)
+	g, err := NewGenerator(details, 7, Level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 20 {
+		if q := g.Next(); q.Kind != Level || !strings.HasPrefix(q.Prompt, "What level is") {
+			t.Fatalf("Next() = %+v, want a level question", q)
+		}
+	}
+
+	// One Digimon has no wrong answers to offer
+	if _, err := NewGenerator(details[:1], 1); !errors.Is(err, ErrNotEnoughData) {
+		t.Errorf("NewGenerator(one Digimon) = %v, want ErrNotEnoughData", err)
+	}
+}
+
+func TestScore(t *testing.T) {
+	var s Score
+	for i, correct := range []bool{true, true, true, false, true} {
+		points := s.Record(correct)
+		want := []int{10, 15, 20, 0, 10}[i]
+		if points != want {
+			t.Errorf("answer %d scored %d, want %d", i+1, points, want)
+		}
+	}
+	if s != (Score{Points: 55, Correct: 4, Answered: 5, Streak: 1, BestStreak: 3}) {
+		t.Errorf("Score = %+v", s)
+	}
+
+	long := Score{Streak: 10}
+	if points := long.Record(true); points != 35 {
+		t.Errorf("long streak scored %d, want the bonus capped at 35", points)
+	}
+}
+
+func TestHighScores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	h, err := OpenHighScores(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if rank := h.Add(Entry{Score: Score{Answered: 3}, At: at}); rank != 0 {
+		t.Errorf("a quiz without right answers ranked %d", rank)
+	}
+	for i := range MaxHighScores {
+		h.Add(Entry{Score: Score{Points: 10 * (i + 1), Correct: i + 1}, Seed: int64(i), At: at})
+	}
+	if rank := h.Add(Entry{Score: Score{Points: 5, Correct: 1}, At: at}); rank != 0 {
+		t.Errorf("the worst score ranked %d with a full list", rank)
+	}
+	if rank := h.Add(Entry{Score: Score{Points: 95, Correct: 9}, Seed: 99, At: at}); rank != 2 {
+		t.Errorf("95 points ranked %d, want 2", rank)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenHighScores(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reopened.Entries()
+	if len(entries) != MaxHighScores || entries[0].Points != 100 || entries[1].Seed != 99 || entries[9].Points != 20 {
+		t.Errorf("Entries() = %+v", entries)
+	}
+}
@@ -0,0 +1,166 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultScoresPath is where the high scores are kept.
+const DefaultScoresPath = "storage/data/quiz-scores.json"
+
+// MaxHighScores is how many scores the high score file keeps.
+const MaxHighScores = 10
+
+const (
+	pointsPerAnswer = 10
+	// streakBonus is added per right answer already in the streak, up to
+	// maxStreakBonus
+	streakBonus    = 5
+	maxStreakBonus = 25
+)
+
+// Score is the running score of a quiz.
+type Score struct {
+	Points     int `json:"points"`
+	Correct    int `json:"correct"`
+	Answered   int `json:"answered"`
+	Streak     int `json:"streak"`
+	BestStreak int `json:"bestStreak"`
+}
+
+// Record counts an answer and returns the points it scored. A right answer
+// scores 10 points plus 5 for every right answer right before it, a bonus
+// of at most 25.
+func (s *Score) Record(correct bool) int {
+	s.Answered++
+	if !correct {
+		s.Streak = 0
+		return 0
+	}
+	points := pointsPerAnswer + min(s.Streak*streakBonus, maxStreakBonus)
+	s.Correct++
+	s.Streak++
+	s.BestStreak = max(s.BestStreak, s.Streak)
+	s.Points += points
+	return points
+}
+
+func (s Score) String() string {
+	return fmt.Sprintf("Score %d | Streak %d (best %d) | %d/%d right", s.Points, s.Streak, s.BestStreak, s.Correct, s.Answered)
+}
+
+// Entry is a finished quiz in the high score list.
+type Entry struct {
+	Score
+	Seed int64     `json:"seed"`
+	At   time.Time `json:"at"`
+}
+
+type scoresFile struct {
+	Scores []Entry `json:"scores"`
+}
+
+// HighScores are the best finished quizzes, stored as one JSON file.
+type HighScores struct {
+	path    string
+	mutex   sync.RWMutex
+	entries []Entry
+}
+
+// OpenHighScores reads the high scores at path. A missing file has none.
+func OpenHighScores(path string) (*HighScores, error) {
+	h := &HighScores{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read high scores: %w", err)
+	}
+
+	var f scoresFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode high scores %s: %v", path, err)
+	}
+	h.entries = f.Scores
+	h.sortAndTrim()
+	return h, nil
+}
+
+func (h *HighScores) Path() string {
+	return h.path
+}
+
+// Entries returns the high scores, best first.
+func (h *HighScores) Entries() []Entry {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return append([]Entry(nil), h.entries...)
+}
+
+// Add records a finished quiz and returns its rank from 1, or 0 when it
+// did not make the list. Quizzes without a right answer are not recorded.
+func (h *HighScores) Add(entry Entry) int {
+	if entry.Correct == 0 {
+		return 0
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.entries = append(h.entries, entry)
+	h.sortAndTrim()
+	for i, e := range h.entries {
+		if e == entry {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// sortAndTrim orders the entries by points, then streak, earlier quizzes
+// first on ties, and keeps the best MaxHighScores.
+func (h *HighScores) sortAndTrim() {
+	sort.SliceStable(h.entries, func(i, j int) bool {
+		a, b := h.entries[i], h.entries[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.BestStreak != b.BestStreak {
+			return a.BestStreak > b.BestStreak
+		}
+		return a.At.Before(b.At)
+	})
+	if len(h.entries) > MaxHighScores {
+		h.entries = h.entries[:MaxHighScores]
+	}
+}
+
+// Save writes the high scores through a temporary file, like the dataset
+// store.
+func (h *HighScores) Save() error {
+	data, err := json.MarshalIndent(scoresFile{Scores: h.Entries()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode high scores: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create high score directory: %w", err)
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write high scores: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("failed to replace high scores: %w", err)
+	}
+	return nil
+}
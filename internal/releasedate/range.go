@@ -0,0 +1,75 @@
+package releasedate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range is an inclusive range of release dates; a zero bound is open.
+type Range struct {
+	From Date
+	To   Date
+}
+
+// ParseRange reads "1997..1999", "1999-03..", "..2000" or a single date,
+// which stands for its whole year, month or day. "" is the open range.
+func ParseRange(s string) (Range, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Range{}, nil
+	}
+
+	from, to, found := strings.Cut(s, "..")
+	if !found {
+		to = from
+	}
+
+	var r Range
+	var err error
+	if strings.TrimSpace(from) != "" {
+		if r.From, err = Parse(from); err != nil {
+			return Range{}, fmt.Errorf("invalid range start: %w", err)
+		}
+	}
+	if strings.TrimSpace(to) != "" {
+		if r.To, err = Parse(to); err != nil {
+			return Range{}, fmt.Errorf("invalid range end: %w", err)
+		}
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && r.From.first().After(r.To.last()) {
+		return Range{}, fmt.Errorf("range %q ends before it starts", s)
+	}
+	return r, nil
+}
+
+// IsZero reports whether r is open at both ends and so matches every date,
+// including unknown ones.
+func (r Range) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether d may fall in r: a partial date matches when
+// the period it stands for overlaps the range. Unknown dates only match
+// the open range.
+func (r Range) Contains(d Date) bool {
+	if r.IsZero() {
+		return true
+	}
+	if d.IsZero() {
+		return false
+	}
+	if !r.From.IsZero() && d.last().Before(r.From.first()) {
+		return false
+	}
+	if !r.To.IsZero() && d.first().After(r.To.last()) {
+		return false
+	}
+	return true
+}
+
+func (r Range) String() string {
+	if r.From == r.To {
+		return r.From.String()
+	}
+	return r.From.String() + ".." + r.To.String()
+}
@@ -0,0 +1,198 @@
+// Package releasedate parses the free-form release dates of the Digi-API,
+// which are often only a year, into comparable dates and ranges.
+package releasedate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoDate is returned for values without a recognisable date.
+var ErrNoDate = errors.New("no release date")
+
+// Precision tells which parts of a Date are known.
+type Precision int
+
+const (
+	None Precision = iota
+	Year
+	Month
+	Day
+)
+
+// Date is a possibly partial date. Month and Day are 0 when unknown.
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+var (
+	numericPattern = regexp.MustCompile(`^(\d{4})(?:[-/.](\d{1,2})(?:[-/.](\d{1,2}))?)?$`)
+	yearPattern    = regexp.MustCompile(`\b(\d{4})\b`)
+)
+
+// Parse reads "1997", "1997-03", "1997-03-21" (also with / or .),
+// "March 1997", "Mar 21, 1997" and "21 March 1997". Any other text holding
+// a single plausible year yields that year.
+func Parse(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, ErrNoDate
+	}
+
+	if m := numericPattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		return newDate(year, month, day, s)
+	}
+
+	if date, ok := parseWords(s); ok {
+		return newDate(date.Year, date.Month, date.Day, s)
+	}
+
+	if years := yearPattern.FindAllString(s, -1); len(years) == 1 {
+		year, _ := strconv.Atoi(years[0])
+		return newDate(year, 0, 0, s)
+	}
+	return Date{}, fmt.Errorf("%w in %q", ErrNoDate, s)
+}
+
+// parseWords reads dates with an English month name.
+func parseWords(s string) (Date, bool) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	var date Date
+	for _, field := range fields {
+		if month := monthNumber(field); month > 0 && date.Month == 0 {
+			date.Month = month
+			continue
+		}
+		number := strings.TrimRight(field, "stndrh")
+		n, err := strconv.Atoi(number)
+		switch {
+		case err != nil:
+			return Date{}, false
+		case len(number) == 4 && date.Year == 0:
+			date.Year = n
+		case n >= 1 && n <= 31 && date.Day == 0:
+			date.Day = n
+		default:
+			return Date{}, false
+		}
+	}
+	return date, date.Year > 0 && date.Month > 0
+}
+
+func monthNumber(name string) int {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if len(name) < 3 {
+		return 0
+	}
+	for month := time.January; month <= time.December; month++ {
+		if strings.HasPrefix(strings.ToLower(month.String()), name) {
+			return int(month)
+		}
+	}
+	return 0
+}
+
+func newDate(year, month, day int, source string) (Date, error) {
+	if year < 1900 || year > 2999 {
+		return Date{}, fmt.Errorf("%w in %q: implausible year %d", ErrNoDate, source, year)
+	}
+	if month < 0 || month > 12 || (month == 0 && day != 0) {
+		return Date{}, fmt.Errorf("%w in %q: invalid month", ErrNoDate, source)
+	}
+	if day != 0 && time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Day() != day {
+		return Date{}, fmt.Errorf("%w in %q: invalid day", ErrNoDate, source)
+	}
+	return Date{Year: year, Month: month, Day: day}, nil
+}
+
+// IsZero reports whether d is unknown.
+func (d Date) IsZero() bool {
+	return d.Year == 0
+}
+
+func (d Date) Precision() Precision {
+	switch {
+	case d.Year == 0:
+		return None
+	case d.Month == 0:
+		return Year
+	case d.Day == 0:
+		return Month
+	}
+	return Day
+}
+
+// String formats d as "1997", "1997-03" or "1997-03-21", "" when unknown.
+func (d Date) String() string {
+	switch d.Precision() {
+	case Year:
+		return fmt.Sprintf("%04d", d.Year)
+	case Month:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	case Day:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+	return ""
+}
+
+// Text formats d for reading: "1997", "March 1997" or "21 March 1997".
+func (d Date) Text() string {
+	switch d.Precision() {
+	case Year:
+		return strconv.Itoa(d.Year)
+	case Month:
+		return fmt.Sprintf("%s %d", time.Month(d.Month), d.Year)
+	case Day:
+		return fmt.Sprintf("%d %s %d", d.Day, time.Month(d.Month), d.Year)
+	}
+	return ""
+}
+
+// Compare orders dates chronologically, a partial date before the fuller
+// ones within it and unknown dates last. It returns -1, 0 or +1.
+func (d Date) Compare(other Date) int {
+	if d.IsZero() || other.IsZero() {
+		switch {
+		case d.IsZero() && other.IsZero():
+			return 0
+		case d.IsZero():
+			return 1
+		}
+		return -1
+	}
+	for _, pair := range [][2]int{{d.Year, other.Year}, {d.Month, other.Month}, {d.Day, other.Day}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// first and last return the first and last day the date may stand for.
+func (d Date) first() time.Time {
+	return time.Date(d.Year, time.Month(max(d.Month, 1)), max(d.Day, 1), 0, 0, 0, 0, time.UTC)
+}
+
+func (d Date) last() time.Time {
+	switch d.Precision() {
+	case Year:
+		return time.Date(d.Year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	case Month:
+		return time.Date(d.Year, time.Month(d.Month)+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	return d.first()
+}
@@ -0,0 +1,101 @@
+package releasedate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Date
+	}{
+		{"1997", Date{Year: 1997}},
+		{" 1999-03 ", Date{Year: 1999, Month: 3}},
+		{"2005-03-21", Date{Year: 2005, Month: 3, Day: 21}},
+		{"2005/3/1", Date{Year: 2005, Month: 3, Day: 1}},
+		{"March 1997", Date{Year: 1997, Month: 3}},
+		{"Mar 21, 1997", Date{Year: 1997, Month: 3, Day: 21}},
+		{"21st March 1997", Date{Year: 1997, Month: 3, Day: 21}},
+		{"1997 (Japan)", Date{Year: 1997}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "Unknown", "1997-13", "1999-02-30", "0042", "1997 or 1998"} {
+		if got, err := Parse(in); !errors.Is(err, ErrNoDate) {
+			t.Errorf("Parse(%q) = %+v, %v, want ErrNoDate", in, got, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	date := Date{Year: 1997, Month: 3, Day: 21}
+	if date.String() != "1997-03-21" || date.Text() != "21 March 1997" {
+		t.Errorf("day formats = %q, %q", date.String(), date.Text())
+	}
+	date.Day = 0
+	if date.String() != "1997-03" || date.Text() != "March 1997" || date.Precision() != Month {
+		t.Errorf("month formats = %q, %q", date.String(), date.Text())
+	}
+	if (Date{}).String() != "" || (Date{}).Precision() != None {
+		t.Errorf("zero date is not empty")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []Date{{Year: 1997}, {Year: 1997, Month: 1}, {Year: 1997, Month: 1, Day: 5}, {Year: 1997, Month: 2}, {Year: 1999}, {}}
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := ordered[i].Compare(ordered[j]); got != want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		contains []Date
+		excludes []Date
+	}{
+		{"1997..1999", []Date{{Year: 1997}, {Year: 1999, Month: 12, Day: 31}}, []Date{{Year: 1996, Month: 12}, {Year: 2000}, {}}},
+		{"1999-03..", []Date{{Year: 1999}, {Year: 1999, Month: 3, Day: 1}, {Year: 2010}}, []Date{{Year: 1999, Month: 2}, {Year: 1998}}},
+		{"..1998", []Date{{Year: 1990}, {Year: 1998, Month: 12}}, []Date{{Year: 1999}}},
+		{"2005", []Date{{Year: 2005, Month: 6}}, []Date{{Year: 2004}, {Year: 2006}}},
+		{"", []Date{{Year: 2005}, {}}, nil},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.in)
+		if err != nil {
+			t.Fatalf("ParseRange(%q) error = %v", tt.in, err)
+		}
+		for _, date := range tt.contains {
+			if !r.Contains(date) {
+				t.Errorf("%q does not contain %v", tt.in, date)
+			}
+		}
+		for _, date := range tt.excludes {
+			if r.Contains(date) {
+				t.Errorf("%q contains %v", tt.in, date)
+			}
+		}
+	}
+
+	for _, in := range []string{"2000..1999", "soon..1999", "1999..later"} {
+		if _, err := ParseRange(in); err == nil {
+			t.Errorf("ParseRange(%q) accepted an invalid range", in)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+// graphqlWorkers bounds the upstream detail requests one loader batch makes
+const graphqlWorkers = 4
+
+const (
+	// graphqlMaxDepth allows two evolution hops, such as
+	// digimon.nextEvolutions.detail.priorEvolutions.detail.levels.level.
+	// Every hop can fan out into many upstream detail requests.
+	graphqlMaxDepth = 7
+	// graphqlMaxParallelism bounds the fields resolved concurrently
+	graphqlMaxParallelism = 10
+	// graphqlMaxPageSize bounds digimonList pages, whose detail fields
+	// fetch every entry
+	graphqlMaxPageSize = 100
+)
+
+//go:embed schema.graphql
+var graphqlSchema string
+
+type loaderKey struct{}
+
+// handleGraphQL executes a query sent as a JSON POST body or, for quick
+// experiments, as GET ?query=.
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+	if r.Method == http.MethodGet {
+		params.Query = r.URL.Query().Get("query")
+		params.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &params.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid variables: "+err.Error())
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	// Every query gets its own loader so batches never mix queries
+	ctx := context.WithValue(r.Context(), loaderKey{}, newDetailLoader(s.fetchDetails))
+	response := s.schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(response)
+}
+
+// fetchDetails resolves one loader batch through the detail cache and the
+// request collapsing of the REST endpoints.
+func (s *Server) fetchDetails(ids []int) map[int]*loaderResult {
+	results := make(map[int]*loaderResult, len(ids))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, graphqlWorkers)
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			digimon, _, err := s.detail(strconv.Itoa(id))
+			mutex.Lock()
+			results[id] = &loaderResult{digimon: digimon, err: err}
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// loadDigimon returns nil for unknown Digimon, so that a dangling evolution
+// resolves to null rather than failing the whole query.
+func loadDigimon(ctx context.Context, id int) (*digimonResolver, error) {
+	loader := ctx.Value(loaderKey{}).(*detailLoader)
+	digimon, err := loader.Load(id)
+	if errors.Is(err, services.ErrNotFound) || (err == nil && digimon == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &digimonResolver{digimon}, nil
+}
+
+type queryResolver struct {
+	server *Server
+}
+
+func (q *queryResolver) Digimon(ctx context.Context, args struct {
+	ID   *int32
+	Name *string
+}) (*digimonResolver, error) {
+	switch {
+	case args.ID != nil:
+		return loadDigimon(ctx, int(*args.ID))
+	case args.Name != nil:
+		digimon, _, err := q.server.detail(*args.Name)
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &digimonResolver{digimon}, nil
+	}
+	return nil, errors.New("digimon needs an id or a name")
+}
+
+func (q *queryResolver) DigimonList(args struct {
+	Name      *string
+	Exact     *bool
+	Level     *string
+	Attribute *string
+	XAntibody *bool
+	Page      int32
+	PageSize  int32
+}) (*pageResolver, error) {
+	if args.Page < 0 || args.PageSize < 1 || args.PageSize > graphqlMaxPageSize {
+		return nil, fmt.Errorf("page must be at least 0 and pageSize between 1 and %d", graphqlMaxPageSize)
+	}
+
+	params := models.DigimonSearchQueryParams{
+		Page:     int(args.Page),
+		PageSize: int(args.PageSize),
+	}
+	if args.Name != nil {
+		params.Name = *args.Name
+	}
+	if args.Exact != nil {
+		params.Exact = strconv.FormatBool(*args.Exact)
+	}
+	if args.Level != nil {
+		params.Level = *args.Level
+	}
+	if args.Attribute != nil {
+		params.Attribute = *args.Attribute
+	}
+	if args.XAntibody != nil {
+		params.XAntibody = strconv.FormatBool(*args.XAntibody)
+	}
+
+	resp, _, err := q.server.list(params)
+	if err != nil {
+		return nil, err
+	}
+	return &pageResolver{resp}, nil
+}
+
+type pageResolver struct {
+	resp *models.DigimonResponse
+}
+
+func (p *pageResolver) Content() []*summaryResolver {
+	summaries := make([]*summaryResolver, 0, len(p.resp.Content))
+	for _, digimon := range p.resp.Content {
+		summaries = append(summaries, &summaryResolver{digimon})
+	}
+	return summaries
+}
+
+func (p *pageResolver) CurrentPage() int32    { return int32(p.resp.Pageable.CurrentPage) }
+func (p *pageResolver) ElementsOnPage() int32 { return int32(p.resp.Pageable.ElementsOnPage) }
+func (p *pageResolver) TotalElements() int32  { return int32(p.resp.Pageable.TotalElements) }
+func (p *pageResolver) TotalPages() int32     { return int32(p.resp.Pageable.TotalPages) }
+func (p *pageResolver) PreviousPage() string  { return p.resp.Pageable.PreviousPage }
+func (p *pageResolver) NextPage() string      { return p.resp.Pageable.NextPage }
+
+type summaryResolver struct {
+	digimon models.Digimon
+}
+
+func (r *summaryResolver) ID() int32     { return int32(r.digimon.ID) }
+func (r *summaryResolver) Name() string  { return r.digimon.Name }
+func (r *summaryResolver) Href() string  { return r.digimon.Href }
+func (r *summaryResolver) Image() string { return r.digimon.Image }
+
+func (r *summaryResolver) Detail(ctx context.Context) (*digimonResolver, error) {
+	return loadDigimon(ctx, r.digimon.ID)
+}
+
+type digimonResolver struct {
+	d *models.DigimonDetail
+}
+
+func (r *digimonResolver) ID() int32           { return int32(r.d.ID) }
+func (r *digimonResolver) Name() string        { return r.d.Name }
+func (r *digimonResolver) XAntibody() bool     { return r.d.XAntibody }
+func (r *digimonResolver) ReleaseDate() string { return r.d.ReleaseDate }
+
+func (r *digimonResolver) Images() []*imageResolver {
+	images := make([]*imageResolver, 0, len(r.d.Images))
+	for _, img := range r.d.Images {
+		images = append(images, &imageResolver{img.Href, img.Transparent})
+	}
+	return images
+}
+
+func (r *digimonResolver) Levels() []*namedResolver {
+	levels := make([]*namedResolver, 0, len(r.d.Levels))
+	for _, level := range r.d.Levels {
+		levels = append(levels, &namedResolver{level.ID, level.Level})
+	}
+	return levels
+}
+
+func (r *digimonResolver) Types() []*namedResolver {
+	types := make([]*namedResolver, 0, len(r.d.Types))
+	for _, t := range r.d.Types {
+		types = append(types, &namedResolver{t.ID, t.Type})
+	}
+	return types
+}
+
+func (r *digimonResolver) Attributes() []*namedResolver {
+	attributes := make([]*namedResolver, 0, len(r.d.Attributes))
+	for _, attribute := range r.d.Attributes {
+		attributes = append(attributes, &namedResolver{attribute.ID, attribute.Attribute})
+	}
+	return attributes
+}
+
+func (r *digimonResolver) Fields() []*fieldResolver {
+	fields := make([]*fieldResolver, 0, len(r.d.Fields))
+	for _, field := range r.d.Fields {
+		fields = append(fields, &fieldResolver{field.ID, field.Field, field.Image})
+	}
+	return fields
+}
+
+func (r *digimonResolver) Descriptions() []*descriptionResolver {
+	descriptions := make([]*descriptionResolver, 0, len(r.d.Descriptions))
+	for _, description := range r.d.Descriptions {
+		descriptions = append(descriptions, &descriptionResolver{description.Origin, description.Language, description.Description})
+	}
+	return descriptions
+}
+
+func (r *digimonResolver) Skills() []*skillResolver {
+	skills := make([]*skillResolver, 0, len(r.d.Skills))
+	for _, skill := range r.d.Skills {
+		skills = append(skills, &skillResolver{skill.ID, skill.Skill, skill.Translation, skill.Description})
+	}
+	return skills
+}
+
+func (r *digimonResolver) PriorEvolutions() []*evolutionResolver {
+	evolutions := make([]*evolutionResolver, 0, len(r.d.PriorEvolutions))
+	for _, e := range r.d.PriorEvolutions {
+		evolutions = append(evolutions, &evolutionResolver{e.ID, e.Digimon, e.Condition, e.Image, e.URL})
+	}
+	return evolutions
+}
+
+func (r *digimonResolver) NextEvolutions() []*evolutionResolver {
+	evolutions := make([]*evolutionResolver, 0, len(r.d.NextEvolutions))
+	for _, e := range r.d.NextEvolutions {
+		evolutions = append(evolutions, &evolutionResolver{e.ID, e.Digimon, e.Condition, e.Image, e.URL})
+	}
+	return evolutions
+}
+
+type imageResolver struct {
+	href        string
+	transparent bool
+}
+
+func (r *imageResolver) Href() string      { return r.href }
+func (r *imageResolver) Transparent() bool { return r.transparent }
+
+// namedResolver serves the id/name pairs of levels, types and attributes.
+type namedResolver struct {
+	id   int
+	name string
+}
+
+func (r *namedResolver) ID() int32         { return int32(r.id) }
+func (r *namedResolver) Level() string     { return r.name }
+func (r *namedResolver) Type() string      { return r.name }
+func (r *namedResolver) Attribute() string { return r.name }
+
+type fieldResolver struct {
+	id    int
+	field string
+	image string
+}
+
+func (r *fieldResolver) ID() int32     { return int32(r.id) }
+func (r *fieldResolver) Field() string { return r.field }
+func (r *fieldResolver) Image() string { return r.image }
+
+type descriptionResolver struct {
+	origin      string
+	language    string
+	description string
+}
+
+func (r *descriptionResolver) Origin() string      { return r.origin }
+func (r *descriptionResolver) Language() string    { return r.language }
+func (r *descriptionResolver) Description() string { return r.description }
+
+type skillResolver struct {
+	id          int
+	skill       string
+	translation string
+	description string
+}
+
+func (r *skillResolver) ID() int32           { return int32(r.id) }
+func (r *skillResolver) Skill() string       { return r.skill }
+func (r *skillResolver) Translation() string { return r.translation }
+func (r *skillResolver) Description() string { return r.description }
+
+type evolutionResolver struct {
+	id        int
+	digimon   string
+	condition string
+	image     string
+	url       string
+}
+
+func (r *evolutionResolver) ID() int32         { return int32(r.id) }
+func (r *evolutionResolver) Digimon() string   { return r.digimon }
+func (r *evolutionResolver) Condition() string { return r.condition }
+func (r *evolutionResolver) Image() string     { return r.image }
+func (r *evolutionResolver) URL() string       { return r.url }
+
+func (r *evolutionResolver) Detail(ctx context.Context) (*digimonResolver, error) {
+	if r.id <= 0 {
+		return nil, nil
+	}
+	return loadDigimon(ctx, r.id)
+}
+
+func parseGraphQLSchema(s *Server) *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &queryResolver{server: s},
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxParallelism(graphqlMaxParallelism))
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/fakeapi"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+// countingTransport counts the upstream requests per path.
+type countingTransport struct {
+	mutex sync.Mutex
+	paths map[string]int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	t.paths[req.URL.Path]++
+	t.mutex.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newFakeAPIServer(t *testing.T) (*httptest.Server, *countingTransport) {
+	t.Helper()
+
+	api, err := fakeapi.NewDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := httptest.NewServer(api)
+	t.Cleanup(upstream.Close)
+
+	transport := &countingTransport{paths: make(map[string]int)}
+	client := services.NewClient(upstream.URL+"/api/v1", &http.Client{Transport: transport})
+	ts := httptest.NewServer(New(client, Config{}))
+	t.Cleanup(ts.Close)
+	return ts, transport
+}
+
+func query(t *testing.T, url, q string, out any) {
+	t.Helper()
+
+	result := post(t, url, q)
+	if len(result.Errors) > 0 {
+		t.Fatalf("query errors: %+v", result.Errors)
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type graphqlResult struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, url, q string) graphqlResult {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": q})
+	resp, err := http.Post(url+"/graphql", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result graphqlResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestGraphQLEvolutions(t *testing.T) {
+	ts, transport := newFakeAPIServer(t)
+
+	var data struct {
+		Digimon struct {
+			Name           string
+			Levels         []struct{ Level string }
+			NextEvolutions []struct {
+				Digimon string
+				Detail  struct {
+					Name            string
+					Levels          []struct{ Level string }
+					PriorEvolutions []struct {
+						Detail struct{ Name string }
+					}
+				}
+			}
+		}
+	}
+	query(t, ts.URL, `{
+		digimon(name: "Agumon") {
+			name
+			levels { level }
+			nextEvolutions {
+				digimon
+				detail {
+					name
+					levels { level }
+					priorEvolutions { detail { name } }
+				}
+			}
+		}
+	}`, &data)
+
+	if data.Digimon.Name != "Agumon" || len(data.Digimon.Levels) == 0 {
+		t.Fatalf("digimon = %+v", data.Digimon)
+	}
+	if len(data.Digimon.NextEvolutions) == 0 {
+		t.Fatal("Agumon has no next evolutions")
+	}
+	for _, evolution := range data.Digimon.NextEvolutions {
+		if evolution.Detail.Name != evolution.Digimon || len(evolution.Detail.Levels) == 0 {
+			t.Errorf("evolution %s resolved to %+v", evolution.Digimon, evolution.Detail)
+		}
+	}
+
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	for path, count := range transport.paths {
+		if count > 1 {
+			t.Errorf("%s was fetched %d times, want once", path, count)
+		}
+	}
+}
+
+func TestGraphQLList(t *testing.T) {
+	ts, _ := newFakeAPIServer(t)
+
+	var data struct {
+		DigimonList struct {
+			TotalElements int
+			Content       []struct {
+				ID     int
+				Detail struct{ ID int }
+			}
+		}
+		Missing *struct{ Name string }
+	}
+	query(t, ts.URL, `{
+		digimonList(name: "mon", pageSize: 3) { totalElements content { id detail { id } } }
+		missing: digimon(id: 9999) { name }
+	}`, &data)
+
+	if len(data.DigimonList.Content) != 3 || data.DigimonList.TotalElements < 3 {
+		t.Fatalf("digimonList = %+v", data.DigimonList)
+	}
+	for _, summary := range data.DigimonList.Content {
+		if summary.Detail.ID != summary.ID {
+			t.Errorf("summary %d resolved detail %d", summary.ID, summary.Detail.ID)
+		}
+	}
+	if data.Missing != nil {
+		t.Errorf("unknown Digimon = %+v, want null", data.Missing)
+	}
+}
+
+func TestDetailLoaderBatches(t *testing.T) {
+	var mutex sync.Mutex
+	var batches [][]int
+	loader := newDetailLoader(func(ids []int) map[int]*loaderResult {
+		mutex.Lock()
+		batches = append(batches, append([]int(nil), ids...))
+		mutex.Unlock()
+
+		results := make(map[int]*loaderResult)
+		for _, id := range ids {
+			results[id] = &loaderResult{digimon: &models.DigimonDetail{ID: id}}
+		}
+		return results
+	})
+	// Leave the goroutines below plenty of time to join one batch
+	loader.wait = 100 * time.Millisecond
+
+	var wg sync.WaitGroup
+	for _, id := range []int{3, 1, 2, 1, 3} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if digimon, err := loader.Load(id); err != nil || digimon.ID != id {
+				t.Errorf("Load(%d) = %+v, %v", id, digimon, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A later load is answered from the loader without another batch
+	if digimon, _ := loader.Load(2); digimon.ID != 2 {
+		t.Errorf("Load(2) = %+v", digimon)
+	}
+
+	if len(batches) != 1 {
+		t.Fatalf("batches = %v, want one", batches)
+	}
+	sort.Ints(batches[0])
+	if len(batches[0]) != 3 || batches[0][0] != 1 || batches[0][2] != 3 {
+		t.Errorf("batch = %v, want [1 2 3]", batches[0])
+	}
+}
+
+func TestGraphQLRejectsDeepQueries(t *testing.T) {
+	ts, transport := newFakeAPIServer(t)
+
+	result := post(t, ts.URL, `{
+		digimon(name: "Agumon") {
+			nextEvolutions { detail {
+				nextEvolutions { detail {
+					nextEvolutions { detail { name } }
+				} }
+			} }
+		}
+	}`)
+	if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, "depth") {
+		t.Fatalf("errors = %+v, want the query rejected for its depth", result.Errors)
+	}
+
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if len(transport.paths) != 0 {
+		t.Errorf("a rejected query fetched %v", transport.paths)
+	}
+}
+
+func TestGraphQLRejectsLargePages(t *testing.T) {
+	ts, transport := newFakeAPIServer(t)
+
+	result := post(t, ts.URL, `{ digimonList(pageSize: 5000) { content { detail { name } } } }`)
+	if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, "pageSize") {
+		t.Fatalf("errors = %+v, want the page size rejected", result.Errors)
+	}
+
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if len(transport.paths) != 0 {
+		t.Errorf("a rejected query fetched %v", transport.paths)
+	}
+}
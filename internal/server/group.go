@@ -0,0 +1,43 @@
+package server
+
+import "sync"
+
+type call struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// callGroup collapses concurrent calls with the same key into one, so that
+// a burst of identical requests costs a single upstream round trip.
+type callGroup struct {
+	mutex sync.Mutex
+	calls map[string]*call
+}
+
+func newCallGroup() *callGroup {
+	return &callGroup{calls: make(map[string]*call)}
+}
+
+// Do runs fn once per key at a time. Callers that arrive while fn is running
+// wait for it and share its result; shared reports whether that happened.
+func (g *callGroup) Do(key string, fn func() (any, error)) (value any, err error, shared bool) {
+	g.mutex.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mutex.Unlock()
+		<-c.done
+		return c.value, c.err, true
+	}
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mutex.Unlock()
+
+	c.value, c.err = fn()
+
+	g.mutex.Lock()
+	delete(g.calls, key)
+	g.mutex.Unlock()
+	close(c.done)
+
+	return c.value, c.err, false
+}
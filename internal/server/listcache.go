@@ -0,0 +1,84 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+type listEntry struct {
+	resp    *models.DigimonResponse
+	expires time.Time
+}
+
+// listCache keeps list responses for a fixed time. Lists change when the API
+// adds Digimon, so unlike details they are not kept until evicted.
+type listCache struct {
+	data  map[string]listEntry
+	order []string
+	mutex sync.Mutex
+	size  int
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func newListCache(size int, ttl time.Duration) *listCache {
+	return &listCache{
+		data:  make(map[string]listEntry),
+		order: make([]string, 0, size),
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+func (c *listCache) Get(key string) (*models.DigimonResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.data[key]
+	if !exists {
+		return nil, false
+	}
+	if c.now().After(entry.expires) {
+		c.removeUnsafe(key)
+		return nil, false
+	}
+	return entry.resp, true
+}
+
+func (c *listCache) Put(key string, resp *models.DigimonResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.data[key]; exists {
+		c.removeUnsafe(key)
+	}
+
+	// If cache is full, remove oldest (first in order)
+	if len(c.order) >= c.size {
+		c.removeUnsafe(c.order[0])
+	}
+
+	c.data[key] = listEntry{resp: resp, expires: c.now().Add(c.ttl)}
+	c.order = append(c.order, key)
+}
+
+func (c *listCache) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.data)
+}
+
+// removeUnsafe assumes the mutex is already locked
+func (c *listCache) removeUnsafe(key string) {
+	delete(c.data, key)
+	for i, v := range c.order {
+		if v == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+const (
+	// loaderWait is how long a loader collects IDs before fetching them
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch dispatches a batch early once it holds this many IDs
+	loaderMaxBatch = 50
+)
+
+type loaderResult struct {
+	done    chan struct{}
+	digimon *models.DigimonDetail
+	err     error
+}
+
+// detailLoader batches the detail lookups made while resolving one GraphQL
+// query, in the style of a dataloader. IDs requested within loaderWait of
+// each other are fetched together, and every ID is fetched at most once
+// per query no matter how many evolutions point at it.
+type detailLoader struct {
+	fetch func(ids []int) map[int]*loaderResult
+	wait  time.Duration
+
+	mutex   sync.Mutex
+	results map[int]*loaderResult
+	pending []int
+	timer   *time.Timer
+}
+
+// newDetailLoader returns a loader that resolves batches with fetch. fetch
+// fills in the digimon or err of every ID it is given.
+func newDetailLoader(fetch func(ids []int) map[int]*loaderResult) *detailLoader {
+	return &detailLoader{
+		fetch:   fetch,
+		wait:    loaderWait,
+		results: make(map[int]*loaderResult),
+	}
+}
+
+func (l *detailLoader) Load(id int) (*models.DigimonDetail, error) {
+	l.mutex.Lock()
+	result, ok := l.results[id]
+	if !ok {
+		result = &loaderResult{done: make(chan struct{})}
+		l.results[id] = result
+		l.pending = append(l.pending, id)
+
+		if len(l.pending) >= loaderMaxBatch {
+			l.dispatchUnsafe()
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(l.wait, l.dispatch)
+		}
+	}
+	l.mutex.Unlock()
+
+	<-result.done
+	return result.digimon, result.err
+}
+
+func (l *detailLoader) dispatch() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.dispatchUnsafe()
+}
+
+// dispatchUnsafe starts fetching the pending IDs. It assumes the mutex is
+// already locked.
+func (l *detailLoader) dispatchUnsafe() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.pending) == 0 {
+		return
+	}
+
+	ids := l.pending
+	l.pending = nil
+	results := make(map[int]*loaderResult, len(ids))
+	for _, id := range ids {
+		results[id] = l.results[id]
+	}
+
+	go func() {
+		fetched := l.fetch(ids)
+		for _, id := range ids {
+			if r, ok := fetched[id]; ok {
+				results[id].digimon, results[id].err = r.digimon, r.err
+			}
+			close(results[id].done)
+		}
+	}()
+}
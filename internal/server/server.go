@@ -0,0 +1,360 @@
+// Package server exposes the Digimon service as a caching HTTP proxy, so
+// that several scripts can share one set of upstream requests.
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/services/cache"
+)
+
+// Config tunes the proxy caches. Zero values fall back to the defaults.
+type Config struct {
+	// CacheSize is the number of Digimon details kept in memory
+	CacheSize int
+	// ListCacheSize is the number of list pages kept in memory
+	ListCacheSize int
+	// ListTTL is how long a list page is served from the cache
+	ListTTL time.Duration
+	// MaxAge is sent to clients in Cache-Control
+	MaxAge time.Duration
+	// Pprof serves /debug/pprof next to /metrics
+	Pprof bool
+}
+
+const (
+	DefaultCacheSize     = 500
+	DefaultListCacheSize = 200
+	DefaultListTTL       = 5 * time.Minute
+	DefaultMaxAge        = time.Minute
+)
+
+// Server answers /digimon, /digimon/{id}, /digimon/{name}, /graphql,
+// /healthz and /metrics, and serves the web front end with its /api/view endpoints.
+type Server struct {
+	service services.Service
+	details *cache.DigimonCache
+	lists   *listCache
+	group   *callGroup
+	maxAge  time.Duration
+	started time.Time
+	mux     *http.ServeMux
+	schema  *graphql.Schema
+
+	// names maps lower-cased names to IDs so that name lookups can be
+	// answered from the detail cache
+	namesMutex sync.RWMutex
+	names      map[string]int
+}
+
+func New(service services.Service, cfg Config) *Server {
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = DefaultCacheSize
+	}
+	if cfg.ListCacheSize <= 0 {
+		cfg.ListCacheSize = DefaultListCacheSize
+	}
+	if cfg.ListTTL <= 0 {
+		cfg.ListTTL = DefaultListTTL
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
+
+	s := &Server{
+		service: service,
+		details: cache.NewDigimonCache(cfg.CacheSize),
+		lists:   newListCache(cfg.ListCacheSize, cfg.ListTTL),
+		group:   newCallGroup(),
+		maxAge:  cfg.MaxAge,
+		started: time.Now(),
+		mux:     http.NewServeMux(),
+		names:   make(map[string]int),
+	}
+
+	s.mux.HandleFunc("GET /digimon", s.handleList)
+	s.mux.HandleFunc("GET /digimon/{key}", s.handleDetail)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /graphql", s.handleGraphQL)
+	s.mux.HandleFunc("POST /graphql", s.handleGraphQL)
+	s.mux.HandleFunc("GET /api/view/digimon", s.handleListView)
+	s.mux.HandleFunc("GET /api/view/digimon/{key}", s.handleDetailView)
+	s.mux.HandleFunc("GET /no-image.png", handleNoImage)
+	s.mux.Handle("GET /", webHandler())
+	metrics.Mount(s.mux, cfg.Pprof)
+
+	s.schema = parseGraphQLSchema(s)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(rec, r)
+	slog.Info("request", "method", r.Method, "path", r.URL.RequestURI(), "status", rec.status,
+		"cache", rec.Header().Get("X-Cache"), "duration", time.Since(start))
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	resp, status, ok := s.listFor(w, r)
+	if !ok {
+		return
+	}
+
+	// Point the page links at this proxy rather than the upstream API
+	out := *resp
+	out.Pageable.PreviousPage = proxyPageURL(r, resp.Pageable.PreviousPage)
+	out.Pageable.NextPage = proxyPageURL(r, resp.Pageable.NextPage)
+
+	w.Header().Set("X-Cache", status)
+	s.writeJSON(w, r, out)
+}
+
+func (s *Server) handleDetail(w http.ResponseWriter, r *http.Request) {
+	digimon, status, err := s.detail(r.PathValue("key"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("X-Cache", status)
+	s.writeJSON(w, r, digimon)
+}
+
+// listFor answers the list request in r. On failure the error response has
+// been written and ok is false.
+func (s *Server) listFor(w http.ResponseWriter, r *http.Request) (resp *models.DigimonResponse, status string, ok bool) {
+	params, err := listParams(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, "", false
+	}
+
+	resp, status, err = s.list(params)
+	if err != nil {
+		writeServiceError(w, err)
+		return nil, "", false
+	}
+	return resp, status, true
+}
+
+// list returns one list page from the cache or upstream.
+func (s *Server) list(params models.DigimonSearchQueryParams) (*models.DigimonResponse, string, error) {
+	key := listKey(params)
+
+	if resp, ok := s.lists.Get(key); ok {
+		return resp, "HIT", nil
+	}
+
+	value, err, shared := s.group.Do("list:"+key, func() (any, error) {
+		resp, err := s.service.GetDigimonList(params)
+		if err != nil {
+			return nil, err
+		}
+		s.lists.Put(key, resp)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return value.(*models.DigimonResponse), cacheStatus(shared), nil
+}
+
+// detail looks a Digimon up by ID or name, from the cache when possible.
+func (s *Server) detail(key string) (*models.DigimonDetail, string, error) {
+	id, err := strconv.Atoi(key)
+	if err != nil {
+		s.namesMutex.RLock()
+		known, ok := s.names[strings.ToLower(key)]
+		s.namesMutex.RUnlock()
+		if ok {
+			id = known
+		}
+	}
+
+	if id > 0 {
+		if digimon, ok := s.details.Get(id); ok {
+			return digimon, "HIT", nil
+		}
+	}
+
+	var (
+		groupKey string
+		fetch    func() (*models.DigimonDetail, error)
+	)
+	if id > 0 {
+		groupKey = fmt.Sprintf("id:%d", id)
+		fetch = func() (*models.DigimonDetail, error) { return s.service.GetDigimonByID(id) }
+	} else {
+		groupKey = "name:" + strings.ToLower(key)
+		fetch = func() (*models.DigimonDetail, error) { return s.service.GetDigimonByName(key) }
+	}
+
+	value, err, shared := s.group.Do(groupKey, func() (any, error) {
+		digimon, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		s.details.Put(digimon.ID, digimon)
+		s.namesMutex.Lock()
+		s.names[strings.ToLower(digimon.Name)] = digimon.ID
+		s.namesMutex.Unlock()
+		return digimon, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return value.(*models.DigimonDetail), cacheStatus(shared), nil
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	hits, misses := s.details.Stats()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]any{
+		"status":       "ok",
+		"uptime":       time.Since(s.started).Round(time.Second).String(),
+		"detailCache":  s.details.Size(),
+		"detailHits":   hits,
+		"detailMisses": misses,
+		"listCache":    s.lists.Size(),
+	})
+}
+
+// writeJSON encodes v with a content-derived ETag and answers 304 when the
+// client already has that version.
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.maxAge.Seconds())))
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// etagMatches implements the weak comparison If-None-Match asks for.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	slog.Warn("Upstream request failed", "error", err)
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+func cacheStatus(shared bool) string {
+	if shared {
+		return "SHARED"
+	}
+	return "MISS"
+}
+
+func listParams(query url.Values) (models.DigimonSearchQueryParams, error) {
+	params := models.DigimonSearchQueryParams{
+		Name:      query.Get("name"),
+		Exact:     query.Get("exact"),
+		Level:     query.Get("level"),
+		Attribute: query.Get("attribute"),
+		XAntibody: query.Get("xAntibody"),
+	}
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"page", &params.Page}, {"pageSize", &params.PageSize}} {
+		raw := query.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return params, fmt.Errorf("invalid %s %q", p.name, raw)
+		}
+		*p.value = n
+	}
+	return params, nil
+}
+
+// listKey identifies a list request independently of parameter order.
+func listKey(params models.DigimonSearchQueryParams) string {
+	q := url.Values{}
+	q.Set("name", params.Name)
+	q.Set("exact", params.Exact)
+	q.Set("level", params.Level)
+	q.Set("attribute", params.Attribute)
+	q.Set("xAntibody", params.XAntibody)
+	q.Set("page", strconv.Itoa(params.Page))
+	q.Set("pageSize", strconv.Itoa(params.PageSize))
+	return q.Encode()
+}
+
+// proxyPageURL rewrites an upstream previous/next link to the same query on
+// this server.
+func proxyPageURL(r *http.Request, upstream string) string {
+	if upstream == "" {
+		return ""
+	}
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return upstream
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/digimon?%s", scheme, r.Host, u.RawQuery)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
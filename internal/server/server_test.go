@@ -0,0 +1,297 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// countingService serves a few Digimon and counts upstream calls. Calls
+// block on gate while it is set.
+type countingService struct {
+	mutex sync.Mutex
+	calls map[string]int
+	gate  chan struct{}
+}
+
+func newCountingService() *countingService {
+	return &countingService{calls: make(map[string]int)}
+}
+
+func (s *countingService) count(key string) {
+	s.mutex.Lock()
+	s.calls[key]++
+	gate := s.gate
+	s.mutex.Unlock()
+
+	if gate != nil {
+		<-gate
+	}
+}
+
+func (s *countingService) callsTo(key string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls[key]
+}
+
+func (s *countingService) GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+	s.count("list")
+	resp := &models.DigimonResponse{Content: []models.Digimon{{ID: 1, Name: "Agumon"}}}
+	resp.Pageable.TotalPages = 2
+	resp.Pageable.NextPage = "https://digi-api.com/api/v1/digimon?page=1&pageSize=1"
+	return resp, nil
+}
+
+func (s *countingService) GetDigimonByName(name string) (*models.DigimonDetail, error) {
+	s.count("name")
+	if strings.EqualFold(name, "agumon") {
+		return &models.DigimonDetail{ID: 1, Name: "Agumon"}, nil
+	}
+	return nil, fmt.Errorf("%w: no Digimon named %q", services.ErrNotFound, name)
+}
+
+func (s *countingService) GetDigimonByID(id int) (*models.DigimonDetail, error) {
+	s.count("id")
+	if id == 1 {
+		return &models.DigimonDetail{ID: 1, Name: "Agumon"}, nil
+	}
+	return nil, fmt.Errorf("%w: no Digimon with ID %d", services.ErrNotFound, id)
+}
+
+func (s *countingService) GetImageByURL(imageUrl string) (image.Image, error) {
+	return nil, errors.New("no images")
+}
+
+func (s *countingService) GetImageDataByURL(imageUrl string) ([]byte, string, error) {
+	return nil, "", errors.New("no images")
+}
+
+func (s *countingService) GetFieldList() ([]models.Field, error) {
+	return nil, nil
+}
+
+func get(t *testing.T, url string, header http.Header) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestDetailETag(t *testing.T) {
+	service := newCountingService()
+	ts := httptest.NewServer(New(service, Config{}))
+	defer ts.Close()
+
+	first := get(t, ts.URL+"/digimon/1", nil)
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag = %q", first.StatusCode, etag)
+	}
+	if got := first.Header.Get("X-Cache"); got != "MISS" {
+		t.Errorf("first X-Cache = %q, want MISS", got)
+	}
+
+	second := get(t, ts.URL+"/digimon/1", http.Header{"If-None-Match": {etag}})
+	if second.StatusCode != http.StatusNotModified {
+		t.Errorf("conditional status = %d, want 304", second.StatusCode)
+	}
+	if got := second.Header.Get("X-Cache"); got != "HIT" {
+		t.Errorf("second X-Cache = %q, want HIT", got)
+	}
+
+	// The name resolves to the cached ID and shares its ETag
+	byName := get(t, ts.URL+"/digimon/AGUMON", nil)
+	if byName.Header.Get("ETag") != etag || byName.Header.Get("X-Cache") != "HIT" {
+		t.Errorf("name lookup ETag = %q, X-Cache = %q", byName.Header.Get("ETag"), byName.Header.Get("X-Cache"))
+	}
+
+	if calls := service.callsTo("id") + service.callsTo("name"); calls != 1 {
+		t.Errorf("upstream detail calls = %d, want 1", calls)
+	}
+}
+
+func TestCollapsesConcurrentRequests(t *testing.T) {
+	service := newCountingService()
+	service.gate = make(chan struct{})
+	ts := httptest.NewServer(New(service, Config{}))
+	defer ts.Close()
+
+	const clients = 10
+	var wg sync.WaitGroup
+	statuses := make(chan int, clients)
+	for range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(ts.URL + "/digimon?pageSize=1&name=")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	// Give the other clients time to queue behind the first upstream call
+	for service.callsTo("list") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(service.gate)
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		if status != http.StatusOK {
+			t.Errorf("status = %d", status)
+		}
+	}
+	if calls := service.callsTo("list"); calls != 1 {
+		t.Errorf("upstream list calls = %d, want 1", calls)
+	}
+}
+
+func TestListLinksPointAtProxy(t *testing.T) {
+	ts := httptest.NewServer(New(newCountingService(), Config{}))
+	defer ts.Close()
+
+	var body models.DigimonResponse
+	resp := get(t, ts.URL+"/digimon?pageSize=1", nil)
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if want := ts.URL + "/digimon?page=1&pageSize=1"; body.Pageable.NextPage != want {
+		t.Errorf("nextPage = %q, want %q", body.Pageable.NextPage, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ts := httptest.NewServer(New(newCountingService(), Config{}))
+	defer ts.Close()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/digimon/42", http.StatusNotFound},
+		{"/digimon/Nomon", http.StatusNotFound},
+		{"/digimon?page=-1", http.StatusBadRequest},
+		{"/digimon?pageSize=x", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if resp := get(t, ts.URL+tt.path, nil); resp.StatusCode != tt.want {
+			t.Errorf("GET %s status = %d, want %d", tt.path, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	ts := httptest.NewServer(New(newCountingService(), Config{}))
+	defer ts.Close()
+
+	var body map[string]any
+	resp := get(t, ts.URL+"/healthz", nil)
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("status = %d, body = %v", resp.StatusCode, body)
+	}
+}
+
+func TestWebFrontEnd(t *testing.T) {
+	ts := httptest.NewServer(New(newCountingService(), Config{}))
+	defer ts.Close()
+
+	for _, path := range []string{"/", "/app.js", "/style.css", "/no-image.png"} {
+		if resp := get(t, ts.URL+path, nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s status = %d", path, resp.StatusCode)
+		}
+	}
+	if resp := get(t, ts.URL+"/no-image.png", nil); resp.Header.Get("Content-Type") != "image/png" {
+		t.Errorf("no-image.png Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+
+	var list viewmodel.ListView
+	resp := get(t, ts.URL+"/api/view/digimon?pageSize=1", nil)
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "Agumon" || !list.HasNext {
+		t.Errorf("list view = %+v", list)
+	}
+
+	var detail detailView
+	resp = get(t, ts.URL+"/api/view/digimon/agumon", nil)
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		t.Fatal(err)
+	}
+	// The same placeholders as the TUI detail block
+	if detail.Name != "Agumon" || detail.LevelsText != "Unknown" || detail.SkillsText != "No skills available" {
+		t.Errorf("detail view = %+v", detail)
+	}
+}
+
+func TestMetricsAndPprof(t *testing.T) {
+	service := newCountingService()
+	ts := httptest.NewServer(New(service, Config{Pprof: true}))
+	defer ts.Close()
+
+	get(t, ts.URL+"/digimon/1", nil)
+	get(t, ts.URL+"/digimon/1", nil)
+
+	resp := get(t, ts.URL+"/metrics", nil)
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`digimontex_cache_hits_total{cache="detail"}`,
+		`digimontex_cache_entries{cache="detail"}`,
+		"# TYPE digimontex_upstream_request_duration_seconds histogram",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %s:\n%s", want, body)
+		}
+	}
+
+	if resp := get(t, ts.URL+"/debug/pprof/", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("pprof status = %d", resp.StatusCode)
+	}
+
+	// pprof is opt-in
+	plain := httptest.NewServer(New(service, Config{}))
+	defer plain.Close()
+	if resp := get(t, plain.URL+"/debug/pprof/", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("pprof without opt-in status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/assets"
+	"github.com/sangnt1552314/digimontex/internal/viewmodel"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// detailView is the JSON the web front end renders. It carries the same
+// texts and placeholders the TUI detail block shows, so both front ends read
+// one view model instead of formatting the API data twice.
+type detailView struct {
+	viewmodel.DigimonView
+	LevelsText     string `json:"levelsText"`
+	TypesText      string `json:"typesText"`
+	AttributesText string `json:"attributesText"`
+	FieldsText     string `json:"fieldsText"`
+	SkillsText     string `json:"skillsText"`
+}
+
+func webHandler() http.Handler {
+	root, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(root)
+}
+
+// handleNoImage serves the fallback artwork the TUI shows as well.
+func handleNoImage(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, "no-image.png", time.Time{}, bytes.NewReader(assets.NoImage))
+}
+
+func (s *Server) handleListView(w http.ResponseWriter, r *http.Request) {
+	resp, status, ok := s.listFor(w, r)
+	if !ok {
+		return
+	}
+
+	view := viewmodel.NewListView(resp)
+	if view.Items == nil {
+		view.Items = []viewmodel.ListItem{}
+	}
+
+	w.Header().Set("X-Cache", status)
+	s.writeJSON(w, r, view)
+}
+
+func (s *Server) handleDetailView(w http.ResponseWriter, r *http.Request) {
+	digimon, status, err := s.detail(r.PathValue("key"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	view := viewmodel.NewDigimonView(digimon)
+	w.Header().Set("X-Cache", status)
+	s.writeJSON(w, r, detailView{
+		DigimonView:    view,
+		LevelsText:     view.LevelsText(),
+		TypesText:      view.TypesText(),
+		AttributesText: view.AttributesText(),
+		FieldsText:     view.FieldsText(),
+		SkillsText:     view.SkillsText(),
+	})
+}
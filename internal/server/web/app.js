@@ -0,0 +1,237 @@
+// Read-only front end for the digimontex serve API. The location hash holds
+// the whole state, e.g. #/digimon/4/evolutions?name=grey&page=1, so views can
+// be bookmarked and the back button works.
+"use strict";
+
+const pageSize = 10;
+const fallbackImage = "no-image.png";
+
+const $ = (id) => document.getElementById(id);
+
+let listView = null;
+
+function parseHash() {
+  const [path, query] = location.hash.replace(/^#/, "").split("?");
+  const parts = (path || "/").split("/").filter(Boolean);
+  const params = new URLSearchParams(query || "");
+  return {
+    key: parts[0] === "digimon" ? decodeURIComponent(parts[1] || "") : "",
+    tab: parts[2] === "evolutions" ? "evolutions" : "detail",
+    name: params.get("name") || "",
+    page: Math.max(parseInt(params.get("page") || "0", 10) || 0, 0),
+  };
+}
+
+function hashFor(state) {
+  const params = new URLSearchParams();
+  if (state.name) params.set("name", state.name);
+  if (state.page) params.set("page", state.page);
+  let path = "/";
+  if (state.key) {
+    path = "/digimon/" + encodeURIComponent(state.key);
+    if (state.tab === "evolutions") path += "/evolutions";
+  }
+  const query = params.toString();
+  return "#" + path + (query ? "?" + query : "");
+}
+
+function navigate(changes) {
+  location.hash = hashFor({ ...parseHash(), ...changes });
+}
+
+async function fetchJSON(url) {
+  const resp = await fetch(url);
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    const err = new Error(body.error || resp.statusText);
+    err.status = resp.status;
+    throw err;
+  }
+  return body;
+}
+
+function el(tag, attrs = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [name, value] of Object.entries(attrs)) {
+    if (name === "text") node.textContent = value;
+    else node.setAttribute(name, value);
+  }
+  node.append(...children.filter(Boolean));
+  return node;
+}
+
+function image(url, className, alt) {
+  const img = el("img", { src: url || fallbackImage, alt: alt || "", class: className || "" });
+  img.onerror = () => {
+    img.onerror = null;
+    img.src = fallbackImage;
+  };
+  return img;
+}
+
+// List panel
+
+let listRequest = "";
+
+async function renderList(state) {
+  const request = new URLSearchParams({ page: state.page, pageSize, name: state.name }).toString();
+  if (request === listRequest && listView) {
+    markSelected(state);
+    return;
+  }
+  listRequest = request;
+
+  const list = $("list");
+  list.replaceChildren(el("li", { class: "muted", text: "Loading..." }));
+  try {
+    const view = await fetchJSON("api/view/digimon?" + request);
+    if (request !== listRequest) return;
+    listView = view;
+  } catch (err) {
+    listView = null;
+    listRequest = "";
+    list.replaceChildren(el("li", { class: "error", text: "Failed to fetch digimon list: " + err.message }));
+    updatePager(state);
+    return;
+  }
+
+  if (listView.items.length === 0) {
+    list.replaceChildren(el("li", { class: "muted", text: "No results" }));
+  } else {
+    list.replaceChildren(...listView.items.map((item) =>
+      el("li", {}, el("a", {
+        href: hashFor({ ...state, key: String(item.id), tab: "detail" }),
+        "data-id": item.id,
+        text: item.name,
+      }))));
+  }
+  markSelected(state);
+  updatePager(state);
+}
+
+function markSelected(state) {
+  for (const link of $("list").querySelectorAll("a")) {
+    link.classList.toggle("selected", link.dataset.id === state.key);
+    link.href = hashFor({ ...state, key: link.dataset.id, tab: state.tab });
+  }
+}
+
+function updatePager(state) {
+  const view = listView;
+  const total = view ? view.totalPages : 0;
+  $("page-label").textContent = view && view.totalItems > 0
+    ? `Page ${view.currentPage + 1} / ${total} (${view.totalItems} results)`
+    : "No results";
+  $("first").disabled = !view || state.page === 0;
+  $("previous").disabled = !view || !view.hasPrevious;
+  $("next").disabled = !view || !view.hasNext;
+  $("last").disabled = !view || state.page >= total - 1;
+}
+
+// Detail panel
+
+let detailRequest = "";
+
+async function renderDetail(state) {
+  const detail = $("detail");
+  const tabs = $("tabs");
+
+  if (!state.key) {
+    tabs.hidden = true;
+    detail.replaceChildren(el("p", { class: "muted", text: "Select a Digimon from the list." }));
+    return;
+  }
+
+  tabs.hidden = false;
+  $("tab-detail").href = hashFor({ ...state, tab: "detail" });
+  $("tab-evolutions").href = hashFor({ ...state, tab: "evolutions" });
+  $("tab-detail").classList.toggle("active", state.tab === "detail");
+  $("tab-evolutions").classList.toggle("active", state.tab === "evolutions");
+
+  const request = state.key + "/" + state.tab;
+  detailRequest = request;
+  detail.replaceChildren(el("p", { class: "muted", text: "Loading Digimon details..." }));
+
+  let view;
+  try {
+    view = await fetchJSON("api/view/digimon/" + encodeURIComponent(state.key));
+  } catch (err) {
+    if (request !== detailRequest) return;
+    const message = err.status === 404
+      ? `404: ${err.message}`
+      : "Failed to load Digimon details: " + err.message;
+    detail.replaceChildren(el("p", { class: "error", text: message }));
+    return;
+  }
+  if (request !== detailRequest) return;
+
+  detail.replaceChildren(state.tab === "evolutions" ? evolutionsView(view, state) : detailView(view));
+}
+
+// detailView mirrors the TUI detail block: image and field icons, then name,
+// release date, levels, types, attributes, fields, description and skills.
+function detailView(view) {
+  const fields = el("div", { class: "fields" },
+    ...(view.fields || []).map((field) => image(field.imageUrl, "", field.name)));
+  const images = el("div", { class: "images" }, image(view.imageUrl, "main", view.name), fields);
+
+  const rows = [
+    ["Release Date", view.releaseDate],
+    ["Levels", view.levelsText],
+    ["Types", view.typesText],
+    ["Attributes", view.attributesText],
+    ["Fields", view.fieldsText],
+    ["Description", view.description],
+  ];
+  const info = el("div", {},
+    el("h2", { class: "name", text: view.name + (view.xAntibody ? " (X-Antibody)" : "") }),
+    el("dl", {}, ...rows.flatMap(([label, value]) => [el("dt", { text: label }), el("dd", { text: value })])),
+    el("h3", { text: "Skills" }),
+    el("p", { class: "skills", text: view.skillsText }));
+
+  return el("div", { class: "detail" }, images, info);
+}
+
+function evolutionsView(view, state) {
+  const column = (title, evolutions) => el("div", {},
+    el("h3", { text: title }),
+    evolutions.length === 0
+      ? el("p", { class: "muted", text: "None" })
+      : el("div", { class: "evolution-list" }, ...evolutions.map((evolution) => {
+        const target = evolution.id > 0 ? String(evolution.id) : evolution.name;
+        return el("a", { class: "evolution", href: hashFor({ ...state, key: target, tab: "evolutions" }) },
+          image(evolution.imageUrl, "", evolution.name),
+          el("span", {}, evolution.name, evolution.condition && el("small", { text: evolution.condition })));
+      })));
+
+  const current = el("div", { class: "current" },
+    image(view.imageUrl, "", view.name),
+    el("h2", { class: "name", text: view.name }),
+    el("p", { class: "muted", text: view.levelsText }));
+
+  return el("div", { class: "evolutions" },
+    column("Prior evolutions", view.priorEvolutions || []),
+    current,
+    column("Next evolutions", view.nextEvolutions || []));
+}
+
+// Wiring
+
+function render() {
+  const state = parseHash();
+  $("search-input").value = state.name;
+  renderList(state);
+  renderDetail(state);
+}
+
+$("search").addEventListener("submit", (event) => {
+  event.preventDefault();
+  navigate({ name: $("search-input").value.trim(), page: 0 });
+});
+$("first").addEventListener("click", () => navigate({ page: 0 }));
+$("previous").addEventListener("click", () => navigate({ page: Math.max(parseHash().page - 1, 0) }));
+$("next").addEventListener("click", () => navigate({ page: parseHash().page + 1 }));
+$("last").addEventListener("click", () => navigate({ page: Math.max((listView ? listView.totalPages : 1) - 1, 0) }));
+
+window.addEventListener("hashchange", render);
+render();
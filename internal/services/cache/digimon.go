@@ -3,14 +3,21 @@ package cache
 import (
 	"sync"
 
+	"github.com/sangnt1552314/digimontex/internal/metrics"
 	"github.com/sangnt1552314/digimontex/internal/models"
 )
 
+// detailCacheName labels this cache in the shared metrics
+const detailCacheName = "detail"
+
 type DigimonCache struct {
-	data  map[int]models.DigimonDetail
-	order []int
-	mutex sync.RWMutex
-	size  int
+	data      map[int]models.DigimonDetail
+	order     []int
+	mutex     sync.RWMutex
+	size      int
+	hits      int
+	misses    int
+	evictions int
 }
 
 func NewDigimonCache(size int) *DigimonCache {
@@ -22,17 +29,32 @@ func NewDigimonCache(size int) *DigimonCache {
 }
 
 func (c *DigimonCache) Get(id int) (*models.DigimonDetail, bool) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	digimon, exists := c.data[id]
 	if exists {
+		c.hits++
+		metrics.CacheHits.Inc(detailCacheName)
 		// Move to front when accessed (LRU behavior)
 		c.moveToFrontUnsafe(id)
+	} else {
+		c.misses++
+		metrics.CacheMisses.Inc(detailCacheName)
 	}
 	return &digimon, exists
 }
 
+// Contains reports whether id is cached without touching the LRU order or
+// the hit/miss counters
+func (c *DigimonCache) Contains(id int) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	_, exists := c.data[id]
+	return exists
+}
+
 func (c *DigimonCache) Put(id int, digimon *models.DigimonDetail) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -49,17 +71,22 @@ func (c *DigimonCache) Put(id int, digimon *models.DigimonDetail) {
 		oldest := c.order[0]
 		delete(c.data, oldest)
 		c.order = c.order[1:]
+		c.evictions++
+		metrics.CacheEvictions.Inc(detailCacheName)
+		metrics.CacheSize.Add(-1, detailCacheName)
 	}
 
 	// Add new entry to the end (most recent)
 	c.data[id] = *digimon
 	c.order = append(c.order, id)
+	metrics.CacheSize.Add(1, detailCacheName)
 }
 
 func (c *DigimonCache) Clear() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	metrics.CacheSize.Add(-float64(len(c.data)), detailCacheName)
 	c.data = make(map[int]models.DigimonDetail)
 	c.order = c.order[:0]
 }
@@ -71,6 +98,35 @@ func (c *DigimonCache) Size() int {
 	return len(c.data)
 }
 
+// Stats returns the number of cache hits and misses recorded by Get
+func (c *DigimonCache) Stats() (hits, misses int) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.hits, c.misses
+}
+
+// Evictions returns the number of entries dropped to make room for newer ones
+func (c *DigimonCache) Evictions() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.evictions
+}
+
+// Values returns a copy of every cached detail, least recently used first,
+// without touching the LRU order or the hit/miss counters
+func (c *DigimonCache) Values() []models.DigimonDetail {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	values := make([]models.DigimonDetail, 0, len(c.order))
+	for _, id := range c.order {
+		values = append(values, c.data[id])
+	}
+	return values
+}
+
 func (c *DigimonCache) GetRecentIDs() []int {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
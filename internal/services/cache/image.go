@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"image"
+	"sync"
+
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+)
+
+// imageCacheName labels this cache in the shared metrics
+const imageCacheName = "image"
+
+type ImageCache struct {
+	data  map[string]image.Image
+	order []string
+	mutex sync.Mutex
+	size  int
+}
+
+func NewImageCache(size int) *ImageCache {
+	return &ImageCache{
+		data:  make(map[string]image.Image),
+		order: make([]string, 0, size),
+		size:  size,
+	}
+}
+
+func (c *ImageCache) Get(url string) (image.Image, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	img, exists := c.data[url]
+	if exists {
+		metrics.CacheHits.Inc(imageCacheName)
+		c.moveToFrontUnsafe(url)
+	} else {
+		metrics.CacheMisses.Inc(imageCacheName)
+	}
+	return img, exists
+}
+
+func (c *ImageCache) Contains(url string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, exists := c.data[url]
+	return exists
+}
+
+func (c *ImageCache) Put(url string, img image.Image) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.data[url]; exists {
+		c.moveToFrontUnsafe(url)
+		c.data[url] = img
+		return
+	}
+
+	// If cache is full, remove oldest (first in order)
+	if len(c.order) >= c.size {
+		oldest := c.order[0]
+		delete(c.data, oldest)
+		c.order = c.order[1:]
+		metrics.CacheEvictions.Inc(imageCacheName)
+		metrics.CacheSize.Add(-1, imageCacheName)
+	}
+
+	c.data[url] = img
+	c.order = append(c.order, url)
+	metrics.CacheSize.Add(1, imageCacheName)
+}
+
+func (c *ImageCache) Size() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.data)
+}
+
+// moveToFrontUnsafe moves an existing URL to the end of order slice (most recent)
+// This method assumes the mutex is already locked
+func (c *ImageCache) moveToFrontUnsafe(url string) {
+	for i, v := range c.order {
+		if v == url {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			c.order = append(c.order, url)
+			break
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Interaction is one recorded HTTP exchange. Text bodies are stored as is so
+// cassettes stay readable, binary bodies (images) are base64 encoded.
+type Interaction struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"bodyBase64,omitempty"`
+}
+
+// Cassette is an ordered set of interactions saved as a JSON file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+func (i Interaction) BodyBytes() ([]byte, error) {
+	if i.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(i.BodyBase64)
+	}
+	return []byte(i.Body), nil
+}
+
+func (i *Interaction) setBody(body []byte) {
+	if utf8.Valid(body) && !strings.HasPrefix(i.Header.Get("Content-Type"), "image/") {
+		i.Body = string(body)
+		i.BodyBase64 = ""
+		return
+	}
+	i.Body = ""
+	i.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+}
+
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Cassette, error) {
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %v", err)
+	}
+	return &c, nil
+}
+
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Kind names the cassette file an interaction is stored in: "list" for
+// /digimon, "detail" for /digimon/{id|name}, "images" for image responses
+// and "other" for anything else.
+func Kind(i Interaction) string {
+	if strings.HasPrefix(i.Header.Get("Content-Type"), "image/") {
+		return "images"
+	}
+	path := i.URL
+	if idx := strings.IndexAny(path, "?#"); idx >= 0 {
+		path = path[:idx]
+	}
+	path = strings.TrimRight(path, "/")
+	switch {
+	case strings.HasSuffix(path, "/digimon"):
+		return "list"
+	case strings.Contains(path, "/digimon/"):
+		return "detail"
+	}
+	return "other"
+}
+
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassettes and never touches the
+	// network
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests and records the responses
+	ModeRecord
+)
+
+// Recorder is an http.RoundTripper that records responses into, or replays
+// them from, the cassettes in a directory.
+type Recorder struct {
+	dir       string
+	mode      Mode
+	transport http.RoundTripper
+	mutex     sync.Mutex
+	cassettes map[string]*Cassette
+}
+
+// NewRecorder loads the cassettes in dir. In record mode transport is used
+// for the real requests, nil means http.DefaultTransport.
+func NewRecorder(dir string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		dir:       dir,
+		mode:      mode,
+		transport: transport,
+		cassettes: make(map[string]*Cassette),
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cassettes: %v", err)
+	}
+	for _, path := range paths {
+		c, err := Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		kind := strings.TrimSuffix(filepath.Base(path), ".json")
+		r.cassettes[kind] = c
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+	return r.record(req)
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	interaction, ok := r.Find(req.Method, req.URL.String())
+	if !ok {
+		return nil, fmt.Errorf("cassette: no recorded response for %s %s", req.Method, req.URL)
+	}
+
+	body, err := interaction.BodyBytes()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: invalid body for %s %s: %v", req.Method, req.URL, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Header: http.Header{},
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		interaction.Header.Set("Content-Type", contentType)
+	}
+	interaction.setBody(body)
+	r.add(interaction)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// add stores interaction, replacing an earlier recording of the same request.
+func (r *Recorder) add(interaction Interaction) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kind := Kind(interaction)
+	c, ok := r.cassettes[kind]
+	if !ok {
+		c = &Cassette{}
+		r.cassettes[kind] = c
+	}
+	for idx, existing := range c.Interactions {
+		if existing.Method == interaction.Method && existing.URL == interaction.URL {
+			c.Interactions[idx] = interaction
+			return
+		}
+	}
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+// Find returns the recorded interaction for method and url.
+func (r *Recorder) Find(method, url string) (Interaction, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, c := range r.cassettes {
+		for _, interaction := range c.Interactions {
+			if interaction.Method == method && interaction.URL == url {
+				return interaction, true
+			}
+		}
+	}
+	return Interaction{}, false
+}
+
+// Save writes every cassette back to the directory, sorted by URL so that
+// re-recording produces small diffs.
+func (r *Recorder) Save() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	for kind, c := range r.cassettes {
+		sort.Slice(c.Interactions, func(i, j int) bool {
+			return c.Interactions[i].URL < c.Interactions[j].URL
+		})
+		if err := c.Save(filepath.Join(r.dir, kind+".json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/digimon":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"content":[{"id":1,"name":"Agumon"}],"pageable":{"totalPages":1,"totalElements":1}}`)
+		case "/api/v1/digimon/1":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, `{"id":1,"name":"Agumon"}`)
+		case "/agumon.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G', 0xff, 0x00})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := services.NewClient(upstream.URL+"/api/v1", &http.Client{Transport: recorder})
+
+	if _, err := client.GetDigimonList(models.DigimonSearchQueryParams{PageSize: 5}); err != nil {
+		t.Fatalf("GetDigimonList() error = %v", err)
+	}
+	if _, err := client.GetDigimonByID(1); err != nil {
+		t.Fatalf("GetDigimonByID() error = %v", err)
+	}
+	if _, err := client.GetDigimonByID(2); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("GetDigimonByID(2) error = %v, want ErrNotFound", err)
+	}
+	imageResp, err := (&http.Client{Transport: recorder}).Get(upstream.URL + "/agumon.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	imageResp.Body.Close()
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	upstream.Close()
+
+	replayer, err := NewRecorder(dir, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range []string{"list", "detail", "images"} {
+		if _, ok := replayer.cassettes[kind]; !ok {
+			t.Errorf("no %s cassette was written", kind)
+		}
+	}
+
+	client = services.NewClient(upstream.URL+"/api/v1", &http.Client{Transport: replayer})
+	list, err := client.GetDigimonList(models.DigimonSearchQueryParams{PageSize: 5})
+	if err != nil || len(list.Content) != 1 || list.Content[0].Name != "Agumon" {
+		t.Fatalf("replayed list = %+v, %v", list, err)
+	}
+	detail, err := client.GetDigimonByID(1)
+	if err != nil || detail.Name != "Agumon" {
+		t.Fatalf("replayed detail = %+v, %v", detail, err)
+	}
+	if _, err := client.GetDigimonByID(2); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("replayed GetDigimonByID(2) error = %v, want ErrNotFound", err)
+	}
+
+	imageResp, err = (&http.Client{Transport: replayer}).Get(upstream.URL + "/agumon.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(imageResp.Body)
+	imageResp.Body.Close()
+	if string(body) != "\x89PNG\xff\x00" {
+		t.Errorf("replayed image body = %q", body)
+	}
+
+	if _, err := client.GetDigimonByID(3); err == nil {
+		t.Error("expected an error for a request that was never recorded")
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		url         string
+		contentType string
+		want        string
+	}{
+		{"https://digi-api.com/api/v1/digimon?page=1&pageSize=5", "application/json", "list"},
+		{"https://digi-api.com/api/v1/digimon/", "application/json", "list"},
+		{"https://digi-api.com/api/v1/digimon/42", "application/json", "detail"},
+		{"https://digi-api.com/api/v1/digimon/Greymon", "application/json", "detail"},
+		{"https://digi-api.com/images/digimon/w/Agumon.png", "image/png", "images"},
+		{"https://digi-api.com/api/v1/field", "application/json", "other"},
+	}
+
+	for _, tt := range tests {
+		interaction := Interaction{URL: tt.url, Header: http.Header{"Content-Type": {tt.contentType}}}
+		if got := Kind(interaction); got != tt.want {
+			t.Errorf("Kind(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -1,67 +1,94 @@
 package services
 
 import (
-	"bytes"
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
-	"io"
-	"log"
 	"net/http"
+	"net/url"
+
+	"github.com/sangnt1552314/digimontex/internal/imaging"
 )
 
+// IsNetworkError reports whether err was caused by the transport (DNS, dial,
+// timeout) rather than by the API answering with an error status.
+func IsNetworkError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
 func GetBase64ImageByUrl(imageUrl string) (string, error) {
-	resp, err := http.Get(imageUrl)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch image: %v", err)
-	}
-	defer resp.Body.Close()
+	return defaultClient.GetBase64ImageByUrl(imageUrl)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch image, status code: %d", resp.StatusCode)
-	}
+func GetImageDataByURL(imageUrl string) ([]byte, string, error) {
+	return defaultClient.GetImageDataByURL(imageUrl)
+}
+
+func GetImageByURL(imageUrl string) (image.Image, error) {
+	return defaultClient.GetImageByURL(imageUrl)
+}
 
-	imageData, err := io.ReadAll(resp.Body)
+// GetBase64ImageByUrl returns the image at imageUrl as a data URI labelled
+// with its detected MIME type.
+func (c *Client) GetBase64ImageByUrl(imageUrl string) (string, error) {
+	data, _, err := c.GetImageDataByURL(imageUrl)
 	if err != nil {
-		return "", fmt.Errorf("failed to read image data: %v", err)
+		return "", err
 	}
+	return DataURI(data), nil
+}
 
-	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
-	return fmt.Sprintf("data:image/png;base64,%s", imageBase64), nil
+// GetImageDataByURL returns the raw bytes of the image at imageUrl and the
+// MIME type sniffed from them.
+func (c *Client) GetImageDataByURL(imageUrl string) ([]byte, string, error) {
+	return c.getImageData(context.Background(), imageUrl)
 }
 
-func GetImageByURL(imageUrl string) image.Image {
-	resp, err := http.Get(imageUrl)
+func (c *Client) getImageData(ctx context.Context, imageUrl string) ([]byte, string, error) {
+	resp, err := c.getContext(ctx, "image", imageUrl)
 	if err != nil {
-		log.Println("Error fetching cover image:", err)
-		return nil
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
 	}
 	defer resp.Body.Close()
 
-	imgData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Println("Error reading cover image data:", err)
-		return nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch image, status code: %d", resp.StatusCode)
 	}
-	contentType := http.DetectContentType(imgData)
 
-	var img image.Image
-	switch contentType {
-	case "image/jpeg":
-		img, err = jpeg.Decode(bytes.NewReader(imgData))
-	case "image/png":
-		img, err = png.Decode(bytes.NewReader(imgData))
-	default:
-		log.Printf("Unsupported image type: %s", contentType)
-		return nil
+	data, err := imaging.ReadData(resp.Body, c.imageLimits)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load image %s: %w", imageUrl, err)
 	}
+	return data, imaging.DetectMIME(data), nil
+}
 
+// DataURI encodes data as a base64 data URI with its sniffed MIME type.
+func DataURI(data []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", imaging.DetectMIME(data), base64.StdEncoding.EncodeToString(data))
+}
+
+// GetImageByURL fetches and decodes the image at imageUrl with the decoders
+// registered in the imaging package. Decode failures wrap
+// imaging.ErrUnsupportedFormat, *imaging.TooLargeError or
+// *imaging.FormatError.
+func (c *Client) GetImageByURL(imageUrl string) (image.Image, error) {
+	return c.GetImageByURLContext(context.Background(), imageUrl)
+}
+
+// GetImageByURLContext is GetImageByURL with a context that cancels the
+// request.
+func (c *Client) GetImageByURLContext(ctx context.Context, imageUrl string) (image.Image, error) {
+	data, _, err := c.getImageData(ctx, imageUrl)
 	if err != nil {
-		log.Printf("Error decoding image: %v", err)
-		return nil
+		return nil, err
 	}
 
-	return img
+	img, _, err := imaging.Decode(data, c.imageLimits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image %s: %w", imageUrl, err)
+	}
+	return img, nil
 }
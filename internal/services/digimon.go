@@ -1,21 +1,163 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/metrics"
 	"github.com/sangnt1552314/digimontex/internal/models"
 )
 
 const (
-	baseURL = "https://digi-api.com/api/v1/digimon"
+	DefaultBaseURL = "https://digi-api.com/api/v1"
+
+	// DefaultRetries is how many times a failed request is retried
+	DefaultRetries = 2
+	// DefaultRetryBackoff is the wait before the first retry, doubled for
+	// every further one
+	DefaultRetryBackoff = 200 * time.Millisecond
 )
 
+// ErrNotFound is returned when the API has no Digimon for the requested ID or name.
+var ErrNotFound = errors.New("digimon not found")
+
+// Service is the Digimon data source used by the front ends. Client talks
+// to the Digi-API, tests can substitute an in-memory implementation.
+type Service interface {
+	GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error)
+	GetDigimonByName(name string) (*models.DigimonDetail, error)
+	GetDigimonByID(id int) (*models.DigimonDetail, error)
+	GetImageByURL(imageUrl string) (image.Image, error)
+	GetImageDataByURL(imageUrl string) ([]byte, string, error)
+	GetFieldList() ([]models.Field, error)
+}
+
+// ContextService is implemented by services whose detail and image requests
+// can be cancelled, such as Client. Background work like prefetching uses it
+// when available so cancelling stops requests that are already running.
+type ContextService interface {
+	GetDigimonByIDContext(ctx context.Context, id int) (*models.DigimonDetail, error)
+	GetImageByURLContext(ctx context.Context, imageUrl string) (image.Image, error)
+}
+
+// Client is the Digi-API implementation of Service.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	retries     int
+	backoff     time.Duration
+	imageLimits imaging.Limits
+}
+
+// NewClient returns a client for the API at baseURL. A nil httpClient uses
+// http.DefaultClient.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		httpClient:  httpClient,
+		retries:     DefaultRetries,
+		backoff:     DefaultRetryBackoff,
+		imageLimits: imaging.DefaultLimits,
+	}
+}
+
+// SetRetries changes how often transport errors and 429/5xx answers are
+// retried, and the backoff before the first retry.
+func (c *Client) SetRetries(retries int, backoff time.Duration) *Client {
+	c.retries = max(retries, 0)
+	c.backoff = backoff
+	return c
+}
+
+// SetImageLimits changes the largest image GetImageByURL decodes.
+func (c *Client) SetImageLimits(limits imaging.Limits) *Client {
+	c.imageLimits = limits
+	return c
+}
+
+// get fetches url, retrying transient failures. Every attempt is recorded
+// in the upstream metrics under endpoint and logged with attrs, the fields
+// that identify the request (id, name, page).
+func (c *Client) get(endpoint, url string, attrs ...any) (*http.Response, error) {
+	return c.getContext(context.Background(), endpoint, url, attrs...)
+}
+
+// getContext is get with a context that cancels the request and the waits
+// between retries.
+func (c *Client) getContext(ctx context.Context, endpoint, url string, attrs ...any) (*http.Response, error) {
+	logger := slog.With(append([]any{"endpoint", endpoint, "url", url}, attrs...)...)
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		duration := time.Since(start)
+
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		metrics.UpstreamRequests.Inc(endpoint, status)
+		metrics.UpstreamDuration.Observe(duration.Seconds(), endpoint)
+
+		retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if retryable {
+			logger.Warn("Upstream request failed", "status", status, "duration", duration, "attempt", attempt+1, "error", err)
+		} else {
+			logger.Debug("Upstream request", "status", status, "duration", duration, "attempt", attempt+1)
+		}
+		if !retryable || attempt >= c.retries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		metrics.UpstreamRetries.Inc(endpoint)
+		select {
+		case <-time.After(c.backoff << attempt):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+var defaultClient = NewClient(DefaultBaseURL, nil)
+
 func GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
-	u, err := url.Parse(baseURL)
+	return defaultClient.GetDigimonList(params)
+}
+
+func GetDigimonByName(name string) (*models.DigimonDetail, error) {
+	return defaultClient.GetDigimonByName(name)
+}
+
+func GetDigimonByID(id int) (*models.DigimonDetail, error) {
+	return defaultClient.GetDigimonByID(id)
+}
+
+func (c *Client) digimonURL() string {
+	return c.baseURL + "/digimon"
+}
+
+func (c *Client) GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResponse, error) {
+	u, err := url.Parse(c.digimonURL())
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %v", err)
 	}
@@ -24,9 +166,18 @@ func GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResp
 	if params.Name != "" {
 		q.Add("name", params.Name)
 	}
+	if params.Exact != "" {
+		q.Add("exact", params.Exact)
+	}
 	if params.Level != "" {
 		q.Add("level", params.Level)
 	}
+	if params.Attribute != "" {
+		q.Add("attribute", params.Attribute)
+	}
+	if params.XAntibody != "" {
+		q.Add("xAntibody", params.XAntibody)
+	}
 	if params.Page > 0 {
 		q.Add("page", strconv.Itoa(params.Page))
 	}
@@ -35,9 +186,9 @@ func GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResp
 	}
 	u.RawQuery = q.Encode()
 
-	resp, err := http.Get(u.String())
+	resp, err := c.get("list", u.String(), "page", params.Page, "name", params.Name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch digimon data: %v", err)
+		return nil, fmt.Errorf("failed to fetch digimon data: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -53,15 +204,19 @@ func GetDigimonList(params models.DigimonSearchQueryParams) (*models.DigimonResp
 	return &apiResp, nil
 }
 
-func GetDigimonByName(name string) (*models.DigimonDetail, error) {
-	url := fmt.Sprintf("%s/%s", baseURL, url.QueryEscape(name))
+func (c *Client) GetDigimonByName(name string) (*models.DigimonDetail, error) {
+	url := fmt.Sprintf("%s/%s", c.digimonURL(), url.PathEscape(name))
 
-	resp, err := http.Get(url)
+	resp, err := c.get("detail", url, "name", name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch digimon by name: %v", err)
+		return nil, fmt.Errorf("failed to fetch digimon by name: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: no Digimon named %q", ErrNotFound, name)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
 	}
@@ -74,15 +229,25 @@ func GetDigimonByName(name string) (*models.DigimonDetail, error) {
 	return &digimon, nil
 }
 
-func GetDigimonByID(id int) (*models.DigimonDetail, error) {
-	url := fmt.Sprintf("%s/%d", baseURL, id)
+func (c *Client) GetDigimonByID(id int) (*models.DigimonDetail, error) {
+	return c.GetDigimonByIDContext(context.Background(), id)
+}
+
+// GetDigimonByIDContext is GetDigimonByID with a context that cancels the
+// request.
+func (c *Client) GetDigimonByIDContext(ctx context.Context, id int) (*models.DigimonDetail, error) {
+	url := fmt.Sprintf("%s/%d", c.digimonURL(), id)
 
-	resp, err := http.Get(url)
+	resp, err := c.getContext(ctx, "detail", url, "id", id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch digimon by ID: %v", err)
+		return nil, fmt.Errorf("failed to fetch digimon by ID: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: no Digimon with ID %d", ErrNotFound, id)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
 	}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, `{"id":4,"name":"Greymon"}`)
+	}))
+	defer ts.Close()
+
+	retries := metrics.UpstreamRetries.Value("detail")
+	failures := metrics.UpstreamRequests.Value("detail", "503")
+
+	client := NewClient(ts.URL, nil).SetRetries(2, 0)
+	digimon, err := client.GetDigimonByID(4)
+	if err != nil || digimon.Name != "Greymon" {
+		t.Fatalf("GetDigimonByID() = %+v, %v", digimon, err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("server called %d times, want 2", calls.Load())
+	}
+	if got := metrics.UpstreamRetries.Value("detail") - retries; got != 1 {
+		t.Errorf("retries recorded = %v, want 1", got)
+	}
+	if got := metrics.UpstreamRequests.Value("detail", "503") - failures; got != 1 {
+		t.Errorf("503 answers recorded = %v, want 1", got)
+	}
+}
+
+func TestCancelStopsRunningRequests(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	client := NewClient(ts.URL, nil).SetRetries(2, 0)
+	start := time.Now()
+	if _, err := client.GetDigimonByIDContext(ctx, 4); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetDigimonByIDContext() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("the cancelled request took %v", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times, want no retries after cancelling", calls.Load())
+	}
+}
+
+func TestDoesNotRetryNotFound(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer ts.Close()
+
+	_, err := NewClient(ts.URL, nil).SetRetries(2, 0).GetDigimonByName("Nomon")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("server called %d times, want 1", calls.Load())
+	}
+}
+
+func TestImageErrorsAreTyped(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html>not an image</html>")
+	}))
+	defer ts.Close()
+
+	img, err := NewClient(ts.URL, nil).GetImageByURL(ts.URL + "/image.png")
+	if img != nil || !errors.Is(err, imaging.ErrUnsupportedFormat) {
+		t.Errorf("GetImageByURL() = %v, %v, want ErrUnsupportedFormat", img, err)
+	}
+}
+
+func TestBase64ImageDetectsMIME(t *testing.T) {
+	gif := "GIF89a\x01\x00\x01\x00\x00\x00\x00;"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, gif)
+	}))
+	defer ts.Close()
+
+	uri, err := NewClient(ts.URL, nil).GetBase64ImageByUrl(ts.URL + "/image.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "data:image/gif;base64,"; !strings.HasPrefix(uri, want) {
+		t.Errorf("data URI = %q, want prefix %q", uri, want)
+	}
+}
+
+func TestGetFieldList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/field" || r.URL.Query().Get("pageSize") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `{"content":{"name":"DigimonField","fields":[
+			{"id":1,"name":"Dragon's Roar","href":"https://digi-api.com/api/v1/field/1"},
+			{"id":2,"name":"Virus Busters","href":"https://digi-api.com/api/v1/field/2"}
+		]},"pageable":{"currentPage":0,"totalPages":1}}`)
+	}))
+	defer ts.Close()
+
+	fields, err := NewClient(ts.URL, nil).GetFieldList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 2 || fields[1].ID != 2 || fields[1].Name != "Virus Busters" {
+		t.Errorf("GetFieldList() = %+v", fields)
+	}
+}
@@ -0,0 +1,152 @@
+package prefetch
+
+import (
+	"context"
+	"image"
+	"log/slog"
+	"sync"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+	"github.com/sangnt1552314/digimontex/internal/services/cache"
+)
+
+// Prefetcher warms the detail and image caches for a batch of Digimon IDs
+// using a fixed number of workers. Starting a new batch cancels the previous
+// one.
+type Prefetcher struct {
+	workers int
+	service services.Service
+	details *cache.DigimonCache
+	images  *cache.ImageCache
+	mutex   sync.Mutex
+	cancel  context.CancelFunc
+}
+
+func NewPrefetcher(workers int, service services.Service, details *cache.DigimonCache, images *cache.ImageCache) *Prefetcher {
+	return &Prefetcher{
+		workers: workers,
+		service: service,
+		details: details,
+		images:  images,
+	}
+}
+
+// Prefetch cancels any running batch and starts warming the caches for ids.
+// IDs are handed to the workers in the order given.
+func (p *Prefetcher) Prefetch(ids []int) {
+	if p.workers <= 0 || len(ids) == 0 {
+		return
+	}
+
+	p.mutex.Lock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.mutex.Unlock()
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for _, id := range ids {
+			select {
+			case jobs <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for range min(p.workers, len(ids)) {
+		go func() {
+			for id := range jobs {
+				p.warm(ctx, id)
+			}
+		}()
+	}
+}
+
+// Cancel stops handing out the remaining IDs of the current batch and, for
+// a services.ContextService, cancels the requests already running.
+func (p *Prefetcher) Cancel() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+func (p *Prefetcher) warm(ctx context.Context, id int) {
+	if ctx.Err() != nil || p.details.Contains(id) {
+		return
+	}
+
+	digimon, err := p.getDetail(ctx, id)
+	if err != nil {
+		slog.Debug("Failed to prefetch digimon detail", "id", id, "error", err)
+		return
+	}
+	p.details.Put(id, digimon)
+
+	urls := make([]string, 0, 1+len(digimon.Fields))
+	if len(digimon.Images) > 0 {
+		urls = append(urls, digimon.Images[0].Href)
+	}
+	for _, field := range digimon.Fields {
+		urls = append(urls, field.Image)
+	}
+
+	for _, url := range urls {
+		if ctx.Err() != nil {
+			return
+		}
+		if url == "" || p.images.Contains(url) {
+			continue
+		}
+		img, err := p.getImage(ctx, url)
+		if err != nil {
+			slog.Debug("Failed to prefetch image", "url", url, "error", err)
+			continue
+		}
+		p.images.Put(url, img)
+	}
+}
+
+func (p *Prefetcher) getDetail(ctx context.Context, id int) (*models.DigimonDetail, error) {
+	if service, ok := p.service.(services.ContextService); ok {
+		return service.GetDigimonByIDContext(ctx, id)
+	}
+	return p.service.GetDigimonByID(id)
+}
+
+func (p *Prefetcher) getImage(ctx context.Context, url string) (image.Image, error) {
+	if service, ok := p.service.(services.ContextService); ok {
+		return service.GetImageByURLContext(ctx, url)
+	}
+	return p.service.GetImageByURL(url)
+}
+
+// PriorityOrder returns ids ordered by distance from the highlighted index,
+// starting with the highlighted item and preferring the items below it.
+func PriorityOrder(ids []int, highlighted int) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	highlighted = max(0, min(highlighted, len(ids)-1))
+
+	ordered := make([]int, 0, len(ids))
+	ordered = append(ordered, ids[highlighted])
+	for distance := 1; len(ordered) < len(ids); distance++ {
+		if below := highlighted + distance; below < len(ids) {
+			ordered = append(ordered, ids[below])
+		}
+		if above := highlighted - distance; above >= 0 {
+			ordered = append(ordered, ids[above])
+		}
+	}
+	return ordered
+}
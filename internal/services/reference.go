@@ -0,0 +1,40 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// referencePageSize asks for every entry of a reference endpoint at once,
+// they hold a few dozen entries at most
+const referencePageSize = 100
+
+func GetFieldList() ([]models.Field, error) {
+	return defaultClient.GetFieldList()
+}
+
+// GetFieldList returns the fields (factions) of the /field reference
+// endpoint.
+func (c *Client) GetFieldList() ([]models.Field, error) {
+	url := fmt.Sprintf("%s/field?pageSize=%d", c.baseURL, referencePageSize)
+
+	resp, err := c.get("field", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fields: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
+	}
+
+	var apiResp models.FieldResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return apiResp.Content.Fields, nil
+}
@@ -0,0 +1,86 @@
+// Package skills indexes the skills of a set of Digimon details, so a move
+// can be traced to every Digimon that knows it.
+package skills
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// Usage is one Digimon's entry for a skill.
+type Usage struct {
+	DigimonID   int
+	Digimon     string
+	Skill       string
+	Translation string
+	Description string
+}
+
+// Index maps skill names, compared case-insensitively, to the Digimon that
+// have them.
+type Index struct {
+	bySkill map[string][]Usage
+}
+
+// NewIndex indexes details. A Digimon listing a skill twice counts once.
+func NewIndex(details []models.DigimonDetail) *Index {
+	index := &Index{
+		bySkill: make(map[string][]Usage),
+	}
+	for _, digimon := range details {
+		seen := make(map[string]bool)
+		for _, skill := range digimon.Skills {
+			name := strings.TrimSpace(skill.Skill)
+			key := Key(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			index.bySkill[key] = append(index.bySkill[key], Usage{
+				DigimonID:   digimon.ID,
+				Digimon:     digimon.Name,
+				Skill:       name,
+				Translation: strings.TrimSpace(skill.Translation),
+				Description: strings.TrimSpace(skill.Description),
+			})
+		}
+	}
+	for _, usages := range index.bySkill {
+		sort.Slice(usages, func(i, j int) bool {
+			if usages[i].Digimon != usages[j].Digimon {
+				return usages[i].Digimon < usages[j].Digimon
+			}
+			return usages[i].DigimonID < usages[j].DigimonID
+		})
+	}
+	return index
+}
+
+// Key normalises a skill name for comparison: case and runs of spaces do
+// not matter.
+func Key(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Find returns every Digimon with the skill name, ordered by name.
+func (i *Index) Find(name string) []Usage {
+	return append([]Usage(nil), i.bySkill[Key(name)]...)
+}
+
+// Shared returns the Digimon other than digimonID with the skill name.
+func (i *Index) Shared(name string, digimonID int) []Usage {
+	var others []Usage
+	for _, usage := range i.bySkill[Key(name)] {
+		if usage.DigimonID != digimonID {
+			others = append(others, usage)
+		}
+	}
+	return others
+}
+
+// Len returns the number of distinct skills.
+func (i *Index) Len() int {
+	return len(i.bySkill)
+}
@@ -0,0 +1,32 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+)
+
+func TestIndex(t *testing.T) {
+	details := fixtures.Details(t, "Agumon", "Greymon", "Agumon (2006)", "Nomon")
+	index := NewIndex(details)
+
+	if index.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", index.Len())
+	}
+
+	found := index.Find("Baby Flame")
+	if len(found) != 2 || found[0].Digimon != "Agumon" || found[1].Digimon != "Agumon (2006)" {
+		t.Fatalf("Find = %+v", found)
+	}
+	if found[0].Translation != "Pepper Breath" || found[1].Description != "Fire" {
+		t.Errorf("usages lost their details: %+v", found)
+	}
+
+	shared := index.Shared("baby flame", 3)
+	if len(shared) != 1 || shared[0].DigimonID != 5 {
+		t.Errorf("Shared = %+v", shared)
+	}
+	if got := index.Find("Unknown"); len(got) != 0 {
+		t.Errorf("Find(Unknown) = %+v", got)
+	}
+}
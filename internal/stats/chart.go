@@ -0,0 +1,108 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// barEighths are the partial blocks for the last cell of a bar
+var barEighths = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+// Bar draws value out of total as a bar of up to width cells, using eighth
+// blocks for the remainder. A non-zero value always shows something.
+func Bar(value, total, width int) string {
+	if value <= 0 || total <= 0 || width <= 0 {
+		return ""
+	}
+	eighths := max(min(value, total)*width*8/total, 1)
+	bar := strings.Repeat("█", eighths/8)
+	if eighths%8 > 0 {
+		bar += string(barEighths[eighths%8])
+	}
+	return bar
+}
+
+// Chart lays counts out as labelled bars, one per line, fitting width
+// cells. Labels longer than a third of the width are cut.
+func Chart(counts []Count, width int) string {
+	if len(counts) == 0 {
+		return "No data\n"
+	}
+
+	maxCount, labelWidth := 0, 0
+	for _, count := range counts {
+		maxCount = max(maxCount, count.Count)
+		labelWidth = max(labelWidth, utf8.RuneCountInString(count.Name))
+	}
+	labelWidth = min(labelWidth, max(width/3, 1))
+	countWidth := len(strconv.Itoa(maxCount))
+	barWidth := max(width-labelWidth-countWidth-2, 1)
+
+	var b strings.Builder
+	for _, count := range counts {
+		fmt.Fprintf(&b, "%s %*d %s\n", pad(count.Name, labelWidth), countWidth, count.Count, Bar(count.Count, maxCount, barWidth))
+	}
+	return b.String()
+}
+
+// pad cuts or pads s to width runes.
+func pad(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// RankedText lists ranked Digimon as "1. Name (ID 3): 5 units" lines.
+func RankedText(ranked []Ranked, unit string) string {
+	if len(ranked) == 0 {
+		return "No data\n"
+	}
+	var b strings.Builder
+	for i, entry := range ranked {
+		units := unit
+		if entry.Count != 1 {
+			units += "s"
+		}
+		fmt.Fprintf(&b, "%2d. %s (ID %d): %d %s\n", i+1, entry.Name, entry.ID, entry.Count, units)
+	}
+	return b.String()
+}
+
+// WriteText writes s as a plain text report with charts width cells wide.
+func (s Stats) WriteText(w io.Writer, width int) error {
+	sections := []struct {
+		title string
+		body  string
+	}{
+		{"Levels", Chart(s.Levels, width)},
+		{"Attributes", Chart(s.Attributes, width)},
+		{"Types", Chart(s.Types, width)},
+		{"Fields", Chart(s.Fields, width)},
+		{"Release years", Chart(s.ReleaseYears, width)},
+		{"Most evolutions", RankedText(s.MostEvolutions, "evolution")},
+		{"Most skills", RankedText(s.MostSkills, "skill")},
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", s.Summary()); err != nil {
+		return err
+	}
+	for _, section := range sections {
+		if _, err := fmt.Fprintf(w, "\n%s\n%s", section.title, section.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Summary is the one-line total and X-Antibody share.
+func (s Stats) Summary() string {
+	return fmt.Sprintf("%d Digimon, %d with X-Antibody (%.1f%%)", s.Total, s.XAntibody, s.XAntibodyShare*100)
+}
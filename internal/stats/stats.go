@@ -0,0 +1,198 @@
+// Package stats aggregates a set of Digimon details: how many there are per
+// level, attribute, type and field, the X-Antibody share, release years and
+// the Digimon with the most evolutions or skills.
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/releasedate"
+)
+
+// DefaultTop is how many Digimon the ranked lists keep by default
+const DefaultTop = 10
+
+// Unknown labels Digimon without a value for a breakdown
+const Unknown = "Unknown"
+
+// Count is the number of Digimon with a value.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Ranked is a Digimon in a top list.
+type Ranked struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Stats is the summary of a dataset. The breakdowns are ordered by count,
+// most common first, except ReleaseYears which is chronological.
+type Stats struct {
+	Total          int      `json:"total"`
+	XAntibody      int      `json:"xAntibody"`
+	XAntibodyShare float64  `json:"xAntibodyShare"`
+	Levels         []Count  `json:"levels"`
+	Attributes     []Count  `json:"attributes"`
+	Types          []Count  `json:"types"`
+	Fields         []Count  `json:"fields"`
+	ReleaseYears   []Count  `json:"releaseYears"`
+	MostEvolutions []Ranked `json:"mostEvolutions"`
+	MostSkills     []Ranked `json:"mostSkills"`
+}
+
+// ReleaseYear returns the year of a release date such as "1997" or
+// "2005-03-01", or "" when it has none.
+func ReleaseYear(releaseDate string) string {
+	date, err := releasedate.Parse(releaseDate)
+	if err != nil {
+		return ""
+	}
+	return strconv.Itoa(date.Year)
+}
+
+// Compute summarises details, keeping the top Digimon in the ranked lists.
+// A Digimon counts once per distinct value of a breakdown.
+func Compute(details []models.DigimonDetail, top int) Stats {
+	stats := Stats{Total: len(details)}
+	levels := newCounter()
+	attributes := newCounter()
+	types := newCounter()
+	fields := newCounter()
+	years := newCounter()
+	var evolutions, skills []Ranked
+
+	for _, digimon := range details {
+		if digimon.XAntibody {
+			stats.XAntibody++
+		}
+
+		var values []string
+		for _, level := range digimon.Levels {
+			values = append(values, level.Level)
+		}
+		levels.add(values)
+
+		values = values[:0]
+		for _, attribute := range digimon.Attributes {
+			values = append(values, attribute.Attribute)
+		}
+		attributes.add(values)
+
+		values = values[:0]
+		for _, t := range digimon.Types {
+			values = append(values, t.Type)
+		}
+		types.add(values)
+
+		values = values[:0]
+		for _, field := range digimon.Fields {
+			values = append(values, field.Field)
+		}
+		fields.add(values)
+
+		years.add([]string{ReleaseYear(digimon.ReleaseDate)})
+
+		evolutions = append(evolutions, Ranked{ID: digimon.ID, Name: digimon.Name, Count: len(digimon.NextEvolutions)})
+		skills = append(skills, Ranked{ID: digimon.ID, Name: digimon.Name, Count: len(digimon.Skills)})
+	}
+
+	if stats.Total > 0 {
+		stats.XAntibodyShare = float64(stats.XAntibody) / float64(stats.Total)
+	}
+	stats.Levels = levels.byCount()
+	stats.Attributes = attributes.byCount()
+	stats.Types = types.byCount()
+	stats.Fields = fields.byCount()
+	stats.ReleaseYears = years.byName()
+	stats.MostEvolutions = topRanked(evolutions, top)
+	stats.MostSkills = topRanked(skills, top)
+	return stats
+}
+
+// counter counts values case-insensitively, keeping the first spelling.
+type counter struct {
+	names  map[string]string
+	counts map[string]int
+}
+
+func newCounter() *counter {
+	return &counter{names: make(map[string]string), counts: make(map[string]int)}
+}
+
+// add counts one Digimon with values, Unknown when none is set.
+func (c *counter) add(values []string) {
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := c.names[key]; !ok {
+			c.names[key] = value
+		}
+		c.counts[key]++
+	}
+	if len(seen) == 0 {
+		c.names[""] = Unknown
+		c.counts[""]++
+	}
+}
+
+func (c *counter) list() []Count {
+	counts := make([]Count, 0, len(c.counts))
+	for key, count := range c.counts {
+		counts = append(counts, Count{Name: c.names[key], Count: count})
+	}
+	return counts
+}
+
+// byCount orders the counts most common first, then by name.
+func (c *counter) byCount() []Count {
+	counts := c.list()
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// byName orders the counts by name with Unknown last.
+func (c *counter) byName() []Count {
+	counts := c.list()
+	sort.Slice(counts, func(i, j int) bool {
+		if (counts[i].Name == Unknown) != (counts[j].Name == Unknown) {
+			return counts[j].Name == Unknown
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// topRanked returns up to top entries with the highest counts, leaving out
+// those with none.
+func topRanked(ranked []Ranked, top int) []Ranked {
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	result := []Ranked{}
+	for _, entry := range ranked {
+		if len(result) == top || entry.Count == 0 {
+			break
+		}
+		result = append(result, entry)
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+)
+
+func TestCompute(t *testing.T) {
+	details := fixtures.Details(t, "Agumon", "Agumon (X-Antibody)", "Greymon", "Koromon")
+
+	stats := Compute(details, 2)
+
+	if stats.Total != 4 || stats.XAntibody != 1 || stats.XAntibodyShare != 1.0/4 {
+		t.Errorf("totals = %d, %d, %v", stats.Total, stats.XAntibody, stats.XAntibodyShare)
+	}
+	checks := []struct {
+		name string
+		got  []Count
+		want []Count
+	}{
+		{"levels", stats.Levels, []Count{{"Child", 2}, {"Adult", 1}, {"Baby II", 1}}},
+		{"attributes", stats.Attributes, []Count{{"Vaccine", 3}, {"Unknown", 1}}},
+		{"types", stats.Types, []Count{{"Reptile", 2}, {"Dinosaur", 1}, {"Unknown", 1}}},
+		{"fields", stats.Fields, []Count{{"Virus Busters", 3}, {"Nature Spirits", 1}, {"Unknown", 1}}},
+		{"years", stats.ReleaseYears, []Count{{"1997", 2}, {"2005", 1}, {"Unknown", 1}}},
+	}
+	for _, check := range checks {
+		if !reflect.DeepEqual(check.got, check.want) {
+			t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+		}
+	}
+
+	wantEvolutions := []Ranked{{3, "Agumon", 3}, {1, "Koromon", 1}}
+	if !reflect.DeepEqual(stats.MostEvolutions, wantEvolutions) {
+		t.Errorf("MostEvolutions = %v", stats.MostEvolutions)
+	}
+	if len(stats.MostSkills) != 2 || stats.MostSkills[0].ID != 3 {
+		t.Errorf("MostSkills = %v", stats.MostSkills)
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		value, total, width int
+		want                string
+	}{
+		{10, 10, 4, "████"},
+		{5, 10, 4, "██"},
+		{1, 10, 4, "▍"},
+		{1, 1000, 4, "▏"},
+		{0, 10, 4, ""},
+	}
+	for _, tt := range tests {
+		if got := Bar(tt.value, tt.total, tt.width); got != tt.want {
+			t.Errorf("Bar(%d, %d, %d) = %q, want %q", tt.value, tt.total, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestChart(t *testing.T) {
+	chart := Chart([]Count{{"Vaccine", 12}, {"A very long attribute name", 3}}, 30)
+	lines := strings.Split(strings.TrimSuffix(chart, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("chart has %d lines:\n%s", len(lines), chart)
+	}
+	if !strings.HasPrefix(lines[0], "Vaccine    12 ████") || !strings.HasPrefix(lines[1], "A very lo…  3 ") {
+		t.Errorf("unexpected chart:\n%s", chart)
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > 30 {
+			t.Errorf("line is %d cells wide: %q", n, line)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Compute(nil, DefaultTop).WriteText(&buf, 40); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "0 Digimon, 0 with X-Antibody (0.0%)") || !strings.Contains(buf.String(), "Most skills\nNo data") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+}
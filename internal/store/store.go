@@ -0,0 +1,143 @@
+// Package store keeps a local copy of Digimon details on disk, filled by the
+// sync command, so features that need the whole dataset do not have to hit
+// the API for every record.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// DefaultPath is where sync writes the dataset unless told otherwise
+const DefaultPath = "storage/data/digimon.json"
+
+type file struct {
+	SyncedAt time.Time              `json:"syncedAt"`
+	Digimon  []models.DigimonDetail `json:"digimon"`
+}
+
+// Store is a set of DigimonDetail records saved as one JSON file.
+type Store struct {
+	path     string
+	mutex    sync.RWMutex
+	records  map[int]models.DigimonDetail
+	syncedAt time.Time
+}
+
+// Open loads the store at path. A missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		records: make(map[int]models.DigimonDetail),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode store %s: %v", path, err)
+	}
+	for _, digimon := range f.Digimon {
+		s.records[digimon.ID] = digimon
+	}
+	s.syncedAt = f.SyncedAt
+
+	return s, nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+func (s *Store) Get(id int) (models.DigimonDetail, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	digimon, exists := s.records[id]
+	return digimon, exists
+}
+
+func (s *Store) Put(digimon models.DigimonDetail) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.records[digimon.ID] = digimon
+}
+
+func (s *Store) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.records)
+}
+
+// All returns every record ordered by ID.
+func (s *Store) All() []models.DigimonDetail {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	all := make([]models.DigimonDetail, 0, len(s.records))
+	for _, digimon := range s.records {
+		all = append(all, digimon)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+	return all
+}
+
+// SyncedAt returns when a sync last completed, zero if never.
+func (s *Store) SyncedAt() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.syncedAt
+}
+
+func (s *Store) MarkSynced(at time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.syncedAt = at
+}
+
+// Save writes the store through a temporary file, so an interrupted save
+// never leaves a truncated dataset behind.
+func (s *Store) Save() error {
+	all := s.All()
+
+	s.mutex.RLock()
+	f := file{SyncedAt: s.syncedAt, Digimon: all}
+	s.mutex.RUnlock()
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+const (
+	syncPageSize = 100
+	// syncSaveEvery saves the store after this many new records, so an
+	// interrupted sync keeps most of its work
+	syncSaveEvery = 100
+)
+
+type SyncOptions struct {
+	// Workers is the number of concurrent detail requests
+	Workers int
+	// Refresh fetches records that are already in the store again
+	Refresh bool
+	// Progress is called after every record with the running totals
+	Progress func(done, total, failed int)
+}
+
+type SyncResult struct {
+	Total   int
+	Fetched int
+	Skipped int
+	Failed  int
+}
+
+// Sync lists every Digimon and stores the details that are missing, or all
+// of them with Refresh. Progress is reported to the sync metrics.
+func Sync(ctx context.Context, service services.Service, st *Store, opts SyncOptions) (SyncResult, error) {
+	var result SyncResult
+
+	ids, err := listIDs(ctx, service)
+	if err != nil {
+		return result, err
+	}
+	result.Total = len(ids)
+	metrics.SyncTotal.Set(float64(len(ids)))
+
+	var pending []int
+	for _, id := range ids {
+		if _, ok := st.Get(id); ok && !opts.Refresh {
+			result.Skipped++
+			metrics.SyncDone.Inc()
+			continue
+		}
+		pending = append(pending, id)
+	}
+
+	jobs := make(chan int)
+	var (
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+		saveErr error
+	)
+	for range max(opts.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				digimon, err := service.GetDigimonByID(id)
+
+				mutex.Lock()
+				if err != nil {
+					result.Failed++
+					metrics.SyncErrors.Inc()
+					slog.Warn("Sync failed", "id", id, "error", err)
+				} else {
+					st.Put(*digimon)
+					result.Fetched++
+					metrics.SyncDone.Inc()
+					if result.Fetched%syncSaveEvery == 0 && saveErr == nil {
+						saveErr = st.Save()
+					}
+				}
+				if opts.Progress != nil {
+					opts.Progress(result.Fetched+result.Skipped, result.Total, result.Failed)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, id := range pending {
+		select {
+		case jobs <- id:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if saveErr != nil {
+		return result, saveErr
+	}
+	if ctx.Err() == nil && result.Failed == 0 {
+		st.MarkSynced(time.Now())
+	}
+	if err := st.Save(); err != nil {
+		return result, err
+	}
+	return result, ctx.Err()
+}
+
+func listIDs(ctx context.Context, service services.Service) ([]int, error) {
+	var ids []int
+	for page := 0; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := service.GetDigimonList(models.DigimonSearchQueryParams{Page: page, PageSize: syncPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list page %d: %w", page, err)
+		}
+		for _, digimon := range resp.Content {
+			if digimon.ID > 0 {
+				ids = append(ids, digimon.ID)
+			}
+		}
+		if resp.Pageable.NextPage == "" || len(resp.Content) == 0 {
+			return ids, nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fakeapi"
+	"github.com/sangnt1552314/digimontex/internal/metrics"
+	"github.com/sangnt1552314/digimontex/internal/services"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestSync(t *testing.T) {
+	api, err := fakeapi.NewDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(api)
+	defer ts.Close()
+	client := services.NewClient(ts.URL+"/api/v1", nil)
+
+	path := filepath.Join(t.TempDir(), "digimon.json")
+	st, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	synced := metrics.SyncDone.Value()
+	result, err := Sync(context.Background(), client, st, SyncOptions{Workers: 3})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Total != api.Len() || result.Fetched != api.Len() || result.Failed != 0 {
+		t.Errorf("first sync = %+v, want %d fetched", result, api.Len())
+	}
+	if got := metrics.SyncDone.Value() - synced; got != float64(api.Len()) {
+		t.Errorf("synced metric grew by %v, want %d", got, api.Len())
+	}
+	if metrics.SyncTotal.Value() != float64(api.Len()) {
+		t.Errorf("total metric = %v", metrics.SyncTotal.Value())
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Len() != api.Len() || reopened.SyncedAt().IsZero() {
+		t.Fatalf("reopened store has %d records, synced at %v", reopened.Len(), reopened.SyncedAt())
+	}
+	if greymon, ok := reopened.Get(4); !ok || greymon.Name != "Greymon" {
+		t.Errorf("Get(4) = %+v, %v", greymon, ok)
+	}
+
+	// A second run only lists, everything is already stored
+	result, err = Sync(context.Background(), client, reopened, SyncOptions{Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Fetched != 0 || result.Skipped != api.Len() {
+		t.Errorf("second sync = %+v, want everything skipped", result)
+	}
+}
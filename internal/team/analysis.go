@@ -0,0 +1,166 @@
+package team
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Roles are the attributes of the Vaccine, Data and Virus triangle; a team
+// without one of them has no answer to the attribute it beats.
+var Roles = []string{"Vaccine", "Data", "Virus"}
+
+// levelOrder is the order levels are listed in, unknown levels come after
+// them by name.
+var levelOrder = []string{"Baby I", "Baby II", "Child", "Adult", "Perfect", "Ultimate", "Armor", "Hybrid"}
+
+// Count is the number of members with a value.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Duplicate is a Digimon that is in the team more than once.
+type Duplicate struct {
+	Member Member `json:"member"`
+	Count  int    `json:"count"`
+}
+
+// Analysis is the composition of a team.
+type Analysis struct {
+	Size int `json:"size"`
+	// Attributes always lists the Roles first, also when no member has them
+	Attributes []Count     `json:"attributes"`
+	Fields     []Count     `json:"fields"`
+	Levels     []Count     `json:"levels"`
+	Duplicates []Duplicate `json:"duplicates,omitempty"`
+	Missing    []string    `json:"missingRoles,omitempty"`
+	Warnings   []string    `json:"warnings,omitempty"`
+}
+
+// Analyze counts the attributes, fields and levels of t and lists what is
+// missing or duplicated. Unresolved members only count towards the size.
+func Analyze(t Team) Analysis {
+	analysis := Analysis{Size: len(t.Members)}
+
+	attributes := make(map[string]int)
+	fields := make(map[string]int)
+	levels := make(map[string]int)
+	seen := make(map[int]int)
+	var order []Member
+	for _, member := range t.Members {
+		if seen[member.ID] == 0 {
+			order = append(order, member)
+		}
+		seen[member.ID]++
+		for _, attribute := range member.Attributes {
+			attributes[attribute]++
+		}
+		for _, field := range member.Fields {
+			fields[field]++
+		}
+		for _, level := range member.Levels {
+			levels[level]++
+		}
+	}
+
+	for _, role := range Roles {
+		analysis.Attributes = append(analysis.Attributes, Count{Name: role, Count: attributes[role]})
+		if attributes[role] == 0 {
+			analysis.Missing = append(analysis.Missing, role)
+		}
+		delete(attributes, role)
+	}
+	analysis.Attributes = append(analysis.Attributes, byCount(attributes)...)
+	analysis.Fields = byCount(fields)
+	analysis.Levels = byLevel(levels)
+
+	for _, member := range order {
+		if seen[member.ID] > 1 {
+			analysis.Duplicates = append(analysis.Duplicates, Duplicate{Member: member, Count: seen[member.ID]})
+		}
+	}
+
+	if empty := Size - len(t.Members); empty > 0 {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%d of %d slots empty", empty, Size))
+	}
+	for _, duplicate := range analysis.Duplicates {
+		analysis.Warnings = append(analysis.Warnings,
+			fmt.Sprintf("%s is in the team %d times", duplicate.Member.Label(), duplicate.Count))
+	}
+	for _, role := range analysis.Missing {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("No %s Digimon", role))
+	}
+	for _, member := range t.Members {
+		if !member.Resolved() {
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%s could not be looked up", member.Label()))
+		}
+	}
+	return analysis
+}
+
+// Text renders the analysis as lines of text.
+func (a Analysis) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Team: %d / %d Digimon\n", a.Size, Size)
+	fmt.Fprintf(&b, "Attributes: %s\n", countsText(a.Attributes))
+	fmt.Fprintf(&b, "Levels: %s\n", countsText(a.Levels))
+	fmt.Fprintf(&b, "Fields: %s\n", countsText(a.Fields))
+	if len(a.Warnings) == 0 {
+		b.WriteString("No problems found\n")
+		return b.String()
+	}
+	for _, warning := range a.Warnings {
+		fmt.Fprintf(&b, "! %s\n", warning)
+	}
+	return b.String()
+}
+
+func countsText(counts []Count) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = fmt.Sprintf("%s %d", count.Name, count.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// byCount orders counts by count, then name.
+func byCount(counts map[string]int) []Count {
+	result := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, Count{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// byLevel orders counts by levelOrder.
+func byLevel(counts map[string]int) []Count {
+	rank := func(name string) int {
+		for i, level := range levelOrder {
+			if strings.EqualFold(level, name) {
+				return i
+			}
+		}
+		return len(levelOrder)
+	}
+	result := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, Count{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if ri, rj := rank(result[i].Name), rank(result[j].Name); ri != rj {
+			return ri < rj
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,68 @@
+package team
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CodePrefix starts every team code and versions its format.
+const CodePrefix = "DTX1."
+
+// ErrInvalidCode is returned for strings that are not team codes.
+var ErrInvalidCode = errors.New("invalid team code")
+
+// Encode returns the shareable code of t. It holds the member IDs and the
+// team name: the IDs as uvarints ended by a zero, then the name, in
+// unpadded URL-safe base64.
+func Encode(t Team) string {
+	var data []byte
+	for _, member := range t.Members {
+		data = binary.AppendUvarint(data, uint64(member.ID))
+	}
+	data = append(data, 0)
+	data = append(data, t.Name...)
+	return CodePrefix + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// IsCode reports whether s looks like a team code rather than a file name.
+func IsCode(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), CodePrefix)
+}
+
+// Decode reads a code made by Encode. The members only have IDs, Resolve
+// fills in the rest.
+func Decode(code string) (Team, error) {
+	payload, ok := strings.CutPrefix(strings.TrimSpace(code), CodePrefix)
+	if !ok {
+		return Team{}, fmt.Errorf("%w: missing %q prefix", ErrInvalidCode, CodePrefix)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Team{}, fmt.Errorf("%w: %v", ErrInvalidCode, err)
+	}
+
+	var t Team
+	for {
+		id, n := binary.Uvarint(data)
+		if n <= 0 {
+			return Team{}, fmt.Errorf("%w: truncated member list", ErrInvalidCode)
+		}
+		data = data[n:]
+		if id == 0 {
+			break
+		}
+		if len(t.Members) == Size || id > 1<<31 {
+			return Team{}, fmt.Errorf("%w: more than %d Digimon or an invalid ID", ErrInvalidCode, Size)
+		}
+		t.Members = append(t.Members, Member{ID: int(id)})
+	}
+	if !utf8.Valid(data) {
+		return Team{}, fmt.Errorf("%w: team name is not UTF-8", ErrInvalidCode)
+	}
+	t.Name = string(data)
+	return t, nil
+}
@@ -0,0 +1,181 @@
+package team
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultPath is where the saved teams are kept, next to the synced dataset.
+const DefaultPath = "storage/data/teams.json"
+
+// ReadJSON reads one team exported with WriteJSON.
+func ReadJSON(r io.Reader) (Team, error) {
+	var t Team
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return Team{}, fmt.Errorf("failed to decode team: %v", err)
+	}
+	if err := t.Validate(); err != nil {
+		return Team{}, err
+	}
+	return t, nil
+}
+
+// WriteJSON writes t as indented JSON.
+func WriteJSON(w io.Writer, t Team) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t)
+}
+
+// Import reads a team from a code or, for anything else, a JSON file.
+func Import(s string) (Team, error) {
+	if IsCode(s) {
+		return Decode(s)
+	}
+	f, err := os.Open(strings.TrimSpace(s))
+	if err != nil {
+		return Team{}, fmt.Errorf("failed to open team: %w", err)
+	}
+	defer f.Close()
+	return ReadJSON(f)
+}
+
+// Export writes t as JSON to path, creating its directory.
+func Export(path string, t Team) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create team directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create team file: %w", err)
+	}
+	if err := WriteJSON(f, t); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write team: %w", err)
+	}
+	return f.Close()
+}
+
+type bookFile struct {
+	Teams []Team `json:"teams"`
+}
+
+// Book is the set of saved teams, stored as one JSON file and keyed by
+// name case-insensitively.
+type Book struct {
+	path  string
+	mutex sync.RWMutex
+	teams map[string]Team
+}
+
+// OpenBook reads the saved teams at path. A missing file is an empty book.
+func OpenBook(path string) (*Book, error) {
+	b := &Book{
+		path:  path,
+		teams: make(map[string]Team),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read teams: %w", err)
+	}
+
+	var f bookFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode teams %s: %v", path, err)
+	}
+	for _, t := range f.Teams {
+		b.teams[key(t.Name)] = t
+	}
+	return b, nil
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func (b *Book) Path() string {
+	return b.path
+}
+
+// Get returns a copy of the team saved under name, so resolving or editing
+// it leaves the book alone.
+func (b *Book) Get(name string) (Team, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	t, ok := b.teams[key(name)]
+	t.Members = append([]Member(nil), t.Members...)
+	return t, ok
+}
+
+// Put saves t under its name, replacing a team of the same name.
+func (b *Book) Put(t Team) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("a saved team needs a name")
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t.Members = append([]Member(nil), t.Members...)
+	b.teams[key(t.Name)] = t
+	return nil
+}
+
+// Delete removes the team saved under name.
+func (b *Book) Delete(name string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.teams, key(name))
+}
+
+// All returns copies of the saved teams ordered by name.
+func (b *Book) All() []Team {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	teams := make([]Team, 0, len(b.teams))
+	for _, t := range b.teams {
+		t.Members = append([]Member(nil), t.Members...)
+		teams = append(teams, t)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return key(teams[i].Name) < key(teams[j].Name)
+	})
+	return teams
+}
+
+// Save writes the book through a temporary file, like the dataset store.
+func (b *Book) Save() error {
+	data, err := json.MarshalIndent(bookFile{Teams: b.All()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode teams: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("failed to create teams directory: %w", err)
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write teams: %w", err)
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		return fmt.Errorf("failed to replace teams: %w", err)
+	}
+	return nil
+}
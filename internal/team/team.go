@@ -0,0 +1,119 @@
+// Package team builds teams of Digimon for the card game, analyses their
+// attribute, field and level coverage and shares them as JSON or compact
+// codes.
+package team
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// Size is the number of Digimon in a full team.
+const Size = 6
+
+// ErrFull is returned when a Digimon is added to a full team.
+var ErrFull = fmt.Errorf("a team holds at most %d Digimon", Size)
+
+// Member is a Digimon of a team with the data the analysis needs. Members
+// decoded from a code only have an ID until they are resolved.
+type Member struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name,omitempty"`
+	Levels     []string `json:"levels,omitempty"`
+	Attributes []string `json:"attributes,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+}
+
+// NewMember takes the member data from d.
+func NewMember(d models.DigimonDetail) Member {
+	member := Member{ID: d.ID, Name: strings.TrimSpace(d.Name)}
+	for _, level := range d.Levels {
+		member.Levels = appendNonEmpty(member.Levels, level.Level)
+	}
+	for _, attribute := range d.Attributes {
+		member.Attributes = appendNonEmpty(member.Attributes, attribute.Attribute)
+	}
+	for _, field := range d.Fields {
+		member.Fields = appendNonEmpty(member.Fields, field.Field)
+	}
+	return member
+}
+
+// Resolved reports whether the member data has been filled in.
+func (m Member) Resolved() bool {
+	return m.Name != ""
+}
+
+// Label is the member's name with its ID, or only the ID while unresolved.
+func (m Member) Label() string {
+	if !m.Resolved() {
+		return fmt.Sprintf("ID %d", m.ID)
+	}
+	return fmt.Sprintf("%s (ID %d)", m.Name, m.ID)
+}
+
+// Team is a named list of up to Size Digimon. The same Digimon may be added
+// more than once, the analysis flags it.
+type Team struct {
+	Name    string   `json:"name"`
+	Members []Member `json:"members"`
+}
+
+// Add appends m to the team.
+func (t *Team) Add(m Member) error {
+	if len(t.Members) >= Size {
+		return ErrFull
+	}
+	t.Members = append(t.Members, m)
+	return nil
+}
+
+// Remove drops the member at index i, out of range indexes are ignored.
+func (t *Team) Remove(i int) {
+	if i < 0 || i >= len(t.Members) {
+		return
+	}
+	// Copy rather than shift in place, t may share its members with a copy
+	t.Members = append(t.Members[:i:i], t.Members[i+1:]...)
+}
+
+// Validate checks the team size and member IDs.
+func (t Team) Validate() error {
+	if len(t.Members) > Size {
+		return fmt.Errorf("team %q has %d Digimon: %w", t.Name, len(t.Members), ErrFull)
+	}
+	for _, member := range t.Members {
+		if member.ID <= 0 {
+			return fmt.Errorf("team %q has an invalid Digimon ID %d", t.Name, member.ID)
+		}
+	}
+	return nil
+}
+
+// Resolve fills in the members that only have an ID with lookup. Lookup
+// errors are joined, members that fail stay unresolved.
+func (t *Team) Resolve(lookup func(id int) (models.DigimonDetail, error)) error {
+	var errs []error
+	for i, member := range t.Members {
+		if member.Resolved() {
+			continue
+		}
+		detail, err := lookup(member.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve Digimon %d: %w", member.ID, err))
+			continue
+		}
+		t.Members[i] = NewMember(detail)
+	}
+	return errors.Join(errs...)
+}
+
+func appendNonEmpty(values []string, value string) []string {
+	if value = strings.TrimSpace(value); value != "" {
+		return append(values, value)
+	}
+	return values
+}
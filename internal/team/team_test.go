@@ -0,0 +1,222 @@
+package team
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+func testDetails(t *testing.T) map[int]models.DigimonDetail {
+	t.Helper()
+
+	byID := make(map[int]models.DigimonDetail)
+	for _, detail := range fixtures.Details(t, "Agumon", "Gabumon", "Devimon", "Patamon") {
+		byID[detail.ID] = detail
+	}
+	return byID
+}
+
+func TestAnalyze(t *testing.T) {
+	details := testDetails(t)
+	var team Team
+	for _, id := range []int{3, 20, 3, 50} {
+		if err := team.Add(NewMember(details[id])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysis := Analyze(team)
+	wantAttributes := []Count{{"Vaccine", 2}, {"Data", 1}, {"Virus", 0}, {"Free", 1}}
+	if !reflect.DeepEqual(analysis.Attributes, wantAttributes) {
+		t.Errorf("Attributes = %+v, want %+v", analysis.Attributes, wantAttributes)
+	}
+	if want := []Count{{"Nature Spirits", 3}, {"Virus Busters", 2}, {"Metal Empire", 1}}; !reflect.DeepEqual(analysis.Fields, want) {
+		t.Errorf("Fields = %+v, want %+v", analysis.Fields, want)
+	}
+	if want := []Count{{"Child", 4}}; !reflect.DeepEqual(analysis.Levels, want) {
+		t.Errorf("Levels = %+v, want %+v", analysis.Levels, want)
+	}
+	if len(analysis.Duplicates) != 1 || analysis.Duplicates[0].Member.ID != 3 || analysis.Duplicates[0].Count != 2 {
+		t.Errorf("Duplicates = %+v", analysis.Duplicates)
+	}
+	if !reflect.DeepEqual(analysis.Missing, []string{"Virus"}) {
+		t.Errorf("Missing = %v", analysis.Missing)
+	}
+
+	text := analysis.Text()
+	for _, want := range []string{
+		"Team: 4 / 6 Digimon",
+		"Attributes: Vaccine 2, Data 1, Virus 0, Free 1",
+		"! 2 of 6 slots empty",
+		"! Agumon (ID 3) is in the team 2 times",
+		"! No Virus Digimon",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestAddFullTeam(t *testing.T) {
+	var team Team
+	for id := 1; id <= Size; id++ {
+		if err := team.Add(Member{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := team.Add(Member{ID: 7}); !errors.Is(err, ErrFull) {
+		t.Errorf("Add() to a full team = %v, want ErrFull", err)
+	}
+
+	team.Remove(0)
+	team.Remove(10)
+	if len(team.Members) != Size-1 || team.Members[0].ID != 2 {
+		t.Errorf("Members after Remove(0) = %+v", team.Members)
+	}
+}
+
+func TestCodeRoundTrip(t *testing.T) {
+	team := Team{Name: "Chosen Children", Members: []Member{{ID: 3}, {ID: 8}, {ID: 1300}}}
+	code := Encode(team)
+	if !IsCode(code) {
+		t.Fatalf("IsCode(%q) = false", code)
+	}
+
+	got, err := Decode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, team) {
+		t.Errorf("Decode(Encode()) = %+v, want %+v", got, team)
+	}
+
+	tooMany := Team{}
+	for id := 1; id <= Size+1; id++ {
+		tooMany.Members = append(tooMany.Members, Member{ID: id})
+	}
+	for _, bad := range []string{"", "team.json", CodePrefix + "!!", CodePrefix + "Aw", Encode(tooMany)} {
+		if _, err := Decode(bad); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Decode(%q) = %v, want ErrInvalidCode", bad, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	details := testDetails(t)
+	team, err := Decode(Encode(Team{Name: "Mixed", Members: []Member{{ID: 3}, {ID: 99}}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = team.Resolve(func(id int) (models.DigimonDetail, error) {
+		if detail, ok := details[id]; ok {
+			return detail, nil
+		}
+		return models.DigimonDetail{}, errors.New("not found")
+	})
+	if err == nil || !strings.Contains(err.Error(), "Digimon 99") {
+		t.Errorf("Resolve() error = %v, want one for ID 99", err)
+	}
+	if team.Members[0].Name != "Agumon" || team.Members[1].Resolved() {
+		t.Errorf("Members = %+v", team.Members)
+	}
+	if text := Analyze(team).Text(); !strings.Contains(text, "! ID 99 could not be looked up") {
+		t.Errorf("Text() does not flag the unresolved member:\n%s", text)
+	}
+}
+
+func TestJSONAndBook(t *testing.T) {
+	details := testDetails(t)
+	team := Team{Name: "Rookies", Members: []Member{NewMember(details[3]), NewMember(details[13])}}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, team); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, team) {
+		t.Errorf("ReadJSON(WriteJSON()) = %+v, want %+v", got, team)
+	}
+
+	dir := t.TempDir()
+	exported := filepath.Join(dir, "exports", "rookies.json")
+	if err := Export(exported, team); err != nil {
+		t.Fatal(err)
+	}
+	if imported, err := Import(exported); err != nil || imported.Name != "Rookies" {
+		t.Errorf("Import(file) = %+v, %v", imported, err)
+	}
+	if imported, err := Import(Encode(team)); err != nil || len(imported.Members) != 2 {
+		t.Errorf("Import(code) = %+v, %v", imported, err)
+	}
+
+	path := filepath.Join(dir, "teams.json")
+	book, err := OpenBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Put(Team{}); err == nil {
+		t.Error("Put() accepted a team without a name")
+	}
+	if err := book.Put(team); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Put(Team{Name: "Adults", Members: []Member{NewMember(details[13])}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := reopened.All()
+	if len(all) != 2 || all[0].Name != "Adults" || all[1].Name != "Rookies" {
+		t.Errorf("All() = %+v", all)
+	}
+	if saved, ok := reopened.Get("rookies"); !ok || !reflect.DeepEqual(saved, team) {
+		t.Errorf("Get(rookies) = %+v, %v", saved, ok)
+	}
+	reopened.Delete("ROOKIES")
+	if _, ok := reopened.Get("Rookies"); ok {
+		t.Error("Delete() kept the team")
+	}
+}
+
+func TestBookReturnsCopies(t *testing.T) {
+	details := testDetails(t)
+	book, err := OpenBook(filepath.Join(t.TempDir(), "teams.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Put(Team{Name: "Unresolved", Members: []Member{{ID: 3}, {ID: 13}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := book.Get("Unresolved")
+	err = got.Resolve(func(id int) (models.DigimonDetail, error) {
+		return details[id], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Remove(0)
+	all := book.All()
+	all[0].Members[0].Name = "Changed"
+
+	saved, _ := book.Get("Unresolved")
+	if want := []Member{{ID: 3}, {ID: 13}}; !reflect.DeepEqual(saved.Members, want) {
+		t.Errorf("the book changed with its copies: %+v", saved.Members)
+	}
+}
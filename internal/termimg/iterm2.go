@@ -0,0 +1,25 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// EncodeITerm2 draws img as an iTerm2 inline image fitted to cols x rows
+// cells. iTerm2 moves the cursor below the image, so the sequence saves
+// and restores it.
+func EncodeITerm2(img image.Image, cols, rows int) ([]byte, error) {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "\x1b7\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:", data.Len(), cols, rows)
+	out.WriteString(base64.StdEncoding.EncodeToString(data.Bytes()))
+	out.WriteString("\a\x1b8")
+	return out.Bytes(), nil
+}
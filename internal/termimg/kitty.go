@@ -0,0 +1,46 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// kittyChunkSize is the largest base64 payload per escape sequence the
+// protocol allows
+const kittyChunkSize = 4096
+
+// EncodeKitty transmits img as PNG with image number id and displays it
+// over cols x rows cells. The terminal scales the image to the cells, the
+// cursor does not move and responses are suppressed.
+func EncodeKitty(img image.Image, id uint32, cols, rows int) ([]byte, error) {
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	payload := base64.StdEncoding.EncodeToString(data.Bytes())
+
+	var out bytes.Buffer
+	for first := true; first || len(payload) > 0; first = false {
+		chunk := payload[:min(kittyChunkSize, len(payload))]
+		payload = payload[len(chunk):]
+
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&out, "\x1b_Ga=T,f=100,t=d,i=%d,p=1,c=%d,r=%d,C=1,z=-1,q=2,m=%d;%s\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&out, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// KittyDelete removes image id and frees its data.
+func KittyDelete(id uint32) []byte {
+	return fmt.Appendf(nil, "\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", id)
+}
@@ -0,0 +1,107 @@
+package termimg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+)
+
+// sixelAlphaThreshold is the alpha below which a pixel is left transparent
+const sixelAlphaThreshold = 0x8000
+
+// EncodeSixel draws img at its pixel size as a Sixel image. Colours are
+// dithered to the 256 colour Plan 9 palette and transparent pixels keep the
+// terminal background.
+func EncodeSixel(img image.Image) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	paletted := image.NewPaletted(image.Rect(0, 0, width, height), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, bounds.Min)
+
+	// Mark transparent pixels with -1 and find the colours in use
+	pixels := make([]int, width*height)
+	used := make([]bool, len(palette.Plan9))
+	for y := range height {
+		for x := range width {
+			index := int(paletted.ColorIndexAt(x, y))
+			if _, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA(); a < sixelAlphaThreshold {
+				index = -1
+			} else {
+				used[index] = true
+			}
+			pixels[y*width+x] = index
+		}
+	}
+
+	var out bytes.Buffer
+	// P2=1 leaves pixels that are not drawn transparent
+	fmt.Fprintf(&out, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for index, inUse := range used {
+		if inUse {
+			r, g, b, _ := palette.Plan9[index].RGBA()
+			fmt.Fprintf(&out, "#%d;2;%d;%d;%d", index, percent(r), percent(g), percent(b))
+		}
+	}
+
+	band := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		if top > 0 {
+			out.WriteByte('-')
+		}
+		first := true
+		for index, inUse := range used {
+			if !inUse || !sixelBand(pixels, width, height, top, index, band) {
+				continue
+			}
+			if !first {
+				out.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&out, "#%d", index)
+			writeSixelRuns(&out, bytes.TrimRight(band, "?"))
+		}
+	}
+	out.WriteString("\x1b\\")
+	return out.Bytes()
+}
+
+// sixelBand fills band with the sixel characters of colour index for the six
+// rows starting at top, reporting whether the colour appears at all.
+func sixelBand(pixels []int, width, height, top, index int, band []byte) bool {
+	found := false
+	for x := range width {
+		var bits byte
+		for row := range min(6, height-top) {
+			if pixels[(top+row)*width+x] == index {
+				bits |= 1 << row
+			}
+		}
+		band[x] = '?' + bits
+		found = found || bits != 0
+	}
+	return found
+}
+
+// writeSixelRuns writes band with runs of more than three equal characters
+// compressed to !<count><char>.
+func writeSixelRuns(out *bytes.Buffer, band []byte) {
+	for i := 0; i < len(band); {
+		j := i + 1
+		for j < len(band) && band[j] == band[i] {
+			j++
+		}
+		if run := j - i; run > 3 {
+			fmt.Fprintf(out, "!%d%c", run, band[i])
+		} else {
+			out.Write(band[i:j])
+		}
+		i = j
+	}
+}
+
+func percent(channel uint32) int {
+	return int((channel*100 + 0x7fff) / 0xffff)
+}
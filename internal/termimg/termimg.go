@@ -0,0 +1,107 @@
+// Package termimg draws images with the terminal graphics protocols: the
+// Kitty graphics protocol, iTerm2 inline images and Sixel. Terminals that
+// support none of them get the half-block rendering of tview.Image.
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/imaging"
+)
+
+type Protocol int
+
+const (
+	// Blocks draws with half-block characters through tview.Image
+	Blocks Protocol = iota
+	Kitty
+	ITerm2
+	Sixel
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case Kitty:
+		return "kitty"
+	case ITerm2:
+		return "iterm2"
+	case Sixel:
+		return "sixel"
+	default:
+		return "blocks"
+	}
+}
+
+// ParseProtocol parses a protocol name. "auto" detects it from getenv.
+func ParseProtocol(name string, getenv func(string) string) (Protocol, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return Detect(getenv), nil
+	case "kitty":
+		return Kitty, nil
+	case "iterm2":
+		return ITerm2, nil
+	case "sixel":
+		return Sixel, nil
+	case "blocks":
+		return Blocks, nil
+	}
+	return Blocks, fmt.Errorf("invalid image protocol %q, want auto, kitty, iterm2, sixel or blocks", name)
+}
+
+// Detect guesses the best protocol from the environment the terminal
+// exports. Multiplexers do not pass the graphics sequences through, so
+// anything inside tmux or screen falls back to Blocks.
+func Detect(getenv func(string) string) Protocol {
+	term := getenv("TERM")
+	program := getenv("TERM_PROGRAM")
+
+	switch {
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux"):
+		return Blocks
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || getenv("LC_TERMINAL") == "iTerm2" || program == "WezTerm":
+		return ITerm2
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.Contains(term, "sixel"):
+		return Sixel
+	}
+	return Blocks
+}
+
+// Encode returns the escape sequence that draws img at the cursor, scaled
+// to fit cols x rows cells of cellWidth x cellHeight pixels with its aspect
+// ratio kept. The cursor is left where it was. id names the image for the
+// Kitty protocol, so drawing it again replaces the previous one. Blocks
+// has no escape sequence and yields nil.
+func Encode(p Protocol, img image.Image, id uint32, cols, rows, cellWidth, cellHeight int) ([]byte, error) {
+	if cols <= 0 || rows <= 0 || img.Bounds().Empty() {
+		return nil, nil
+	}
+	if cellWidth <= 0 || cellHeight <= 0 {
+		cellWidth, cellHeight = DefaultCellWidth, DefaultCellHeight
+	}
+	width, height := imaging.FitSize(img.Bounds().Dx(), img.Bounds().Dy(), cols*cellWidth, rows*cellHeight)
+	cellCols := min(cols, (width+cellWidth-1)/cellWidth)
+	cellRows := min(rows, (height+cellHeight-1)/cellHeight)
+
+	// Kitty and iTerm2 scale the image to the cells themselves, so only
+	// shrink it to save bandwidth. Sixel draws pixels as they are.
+	switch p {
+	case Kitty:
+		return EncodeKitty(imaging.Fit(img, width, height), id, cellCols, cellRows)
+	case ITerm2:
+		return EncodeITerm2(imaging.Fit(img, width, height), cellCols, cellRows)
+	case Sixel:
+		return EncodeSixel(imaging.Resize(img, width, height)), nil
+	}
+	return nil, nil
+}
+
+// Cell size assumed when the terminal does not report its pixel size
+const (
+	DefaultCellWidth  = 8
+	DefaultCellHeight = 16
+)
@@ -0,0 +1,200 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func solid(width, height int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func decodePNG(t *testing.T, payload string) image.Image {
+	t.Helper()
+
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatalf("payload is not base64: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("payload is not a PNG: %v", err)
+	}
+	return img
+}
+
+func TestKitty(t *testing.T) {
+	// Noise does not compress, so the payload needs several chunks
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	rand.New(rand.NewSource(1)).Read(img.Pix)
+
+	out, err := EncodeKitty(img, 7, 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := regexp.MustCompile(`\x1b_G([^;]*);([^\x1b]*)\x1b\\`).FindAllStringSubmatch(string(out), -1)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want several: %q", len(chunks), out[:min(len(out), 80)])
+	}
+	if got := chunks[0][1]; got != "a=T,f=100,t=d,i=7,p=1,c=10,r=5,C=1,z=-1,q=2,m=1" {
+		t.Errorf("first chunk keys = %q", got)
+	}
+
+	var payload strings.Builder
+	for i, chunk := range chunks {
+		if len(chunk[2]) > kittyChunkSize {
+			t.Errorf("chunk %d carries %d bytes", i, len(chunk[2]))
+		}
+		last := i == len(chunks)-1
+		if i > 0 && chunk[1] != map[bool]string{true: "m=0", false: "m=1"}[last] {
+			t.Errorf("chunk %d keys = %q", i, chunk[1])
+		}
+		payload.WriteString(chunk[2])
+	}
+	if got := decodePNG(t, payload.String()).Bounds(); got != img.Bounds() {
+		t.Errorf("decoded bounds = %v", got)
+	}
+
+	if got := string(KittyDelete(7)); got != "\x1b_Ga=d,d=I,i=7,q=2\x1b\\" {
+		t.Errorf("delete = %q", got)
+	}
+}
+
+func TestITerm2(t *testing.T) {
+	img := solid(4, 2, color.NRGBA{R: 255, A: 255})
+
+	out, err := EncodeITerm2(img, 12, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match := regexp.MustCompile(`^\x1b7\x1b]1337;File=inline=1;size=(\d+);width=12;height=6;preserveAspectRatio=1:([A-Za-z0-9+/=]+)\a\x1b8$`).FindStringSubmatch(string(out))
+	if match == nil {
+		t.Fatalf("unexpected sequence %q", out)
+	}
+	decoded := decodePNG(t, match[2])
+	if decoded.Bounds() != img.Bounds() {
+		t.Errorf("decoded bounds = %v", decoded.Bounds())
+	}
+	raw, _ := base64.StdEncoding.DecodeString(match[2])
+	if match[1] != strconv.Itoa(len(raw)) {
+		t.Errorf("size = %s, want %d", match[1], len(raw))
+	}
+}
+
+func TestSixel(t *testing.T) {
+	// Two red columns, one transparent and one blue, eight rows high
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 8))
+	for y := range 8 {
+		img.Set(0, y, color.NRGBA{R: 255, A: 255})
+		img.Set(1, y, color.NRGBA{R: 255, A: 255})
+		img.Set(3, y, color.NRGBA{B: 255, A: 255})
+	}
+
+	out := string(EncodeSixel(img))
+
+	header := regexp.MustCompile(`^\x1bP0;1;0q"1;1;4;8((?:#\d+;2;\d+;\d+;\d+)+)`).FindStringSubmatch(out)
+	if header == nil || !strings.HasSuffix(out, "\x1b\\") {
+		t.Fatalf("unexpected framing %q", out)
+	}
+	colors := map[string]string{}
+	for _, c := range regexp.MustCompile(`#(\d+);2;(\d+;\d+;\d+)`).FindAllStringSubmatch(header[1], -1) {
+		colors[c[2]] = c[1]
+	}
+	red, blue := colors["100;0;0"], colors["0;0;100"]
+	if len(colors) != 2 || red == "" || blue == "" {
+		t.Fatalf("palette = %v, want pure red and blue", colors)
+	}
+
+	// The first band is six rows (~ is all six bits), the second two (B)
+	bands := strings.Split(out[len(header[0]):len(out)-2], "-")
+	want := []string{
+		"#" + red + "~~$#" + blue + "???~",
+		"#" + red + "BB$#" + blue + "???B",
+	}
+	// Colours are written in palette order
+	redIndex, _ := strconv.Atoi(red)
+	blueIndex, _ := strconv.Atoi(blue)
+	if redIndex > blueIndex {
+		want = []string{
+			"#" + blue + "???~$#" + red + "~~",
+			"#" + blue + "???B$#" + red + "BB",
+		}
+	}
+	if len(bands) != 2 || bands[0] != want[0] || bands[1] != want[1] {
+		t.Errorf("bands = %q, want %q", bands, want)
+	}
+}
+
+func TestSixelRuns(t *testing.T) {
+	var out bytes.Buffer
+	writeSixelRuns(&out, []byte("~~~~~~AAA@"))
+	if got := out.String(); got != "!6~AAA@" {
+		t.Errorf("runs = %q", got)
+	}
+}
+
+func TestEncodeFitsCells(t *testing.T) {
+	// A 2:1 image in a 10x10 cell area of 8x16 pixel cells fits 80x40
+	// pixels, which is 10x3 cells
+	img := solid(200, 100, color.NRGBA{G: 255, A: 255})
+
+	out, err := Encode(Kitty, img, 1, 10, 10, 8, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), ",c=10,r=3,") {
+		t.Errorf("kitty placement = %q", out[:60])
+	}
+
+	out, _ = Encode(Sixel, img, 1, 10, 10, 0, 0)
+	if !strings.HasPrefix(string(out), "\x1bP0;1;0q\"1;1;80;40") {
+		t.Errorf("sixel size = %q", out[:20])
+	}
+
+	if out, _ := Encode(Blocks, img, 1, 10, 10, 8, 16); out != nil {
+		t.Errorf("blocks produced %q", out)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, Kitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, ITerm2},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, ITerm2},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, Blocks},
+		{map[string]string{"TERM": "xterm-256color"}, Blocks},
+	}
+	for _, tt := range tests {
+		if got := Detect(func(key string) string { return tt.env[key] }); got != tt.want {
+			t.Errorf("Detect(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+
+	if p, err := ParseProtocol("SIXEL", nil); err != nil || p != Sixel {
+		t.Errorf("ParseProtocol(SIXEL) = %v, %v", p, err)
+	}
+	if _, err := ParseProtocol("ascii", nil); err == nil {
+		t.Error("ParseProtocol accepted an unknown protocol")
+	}
+}
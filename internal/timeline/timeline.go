@@ -0,0 +1,162 @@
+// Package timeline searches and orders Digimon details by release date and
+// groups them by release year.
+package timeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/releasedate"
+)
+
+// Order is the sort order of a search.
+type Order int
+
+const (
+	ByID Order = iota
+	ByName
+	ByRelease
+)
+
+func (o Order) String() string {
+	switch o {
+	case ByName:
+		return "name"
+	case ByRelease:
+		return "release"
+	}
+	return "id"
+}
+
+// ParseOrder reads "id", "name" or "release".
+func ParseOrder(name string) (Order, error) {
+	for _, order := range []Order{ByID, ByName, ByRelease} {
+		if strings.EqualFold(name, order.String()) {
+			return order, nil
+		}
+	}
+	return ByID, fmt.Errorf("unknown sort order %q, want id, name or release", name)
+}
+
+// Entry is a Digimon with its parsed release date, zero when unknown.
+type Entry struct {
+	Digimon  models.DigimonDetail
+	Released releasedate.Date
+}
+
+// Query selects and orders Digimon. Name matches case-insensitively as a
+// substring, an open Range matches every Digimon.
+type Query struct {
+	Name       string
+	Range      releasedate.Range
+	Order      Order
+	Descending bool
+}
+
+// IsZero reports whether q keeps every Digimon in ID order.
+func (q Query) IsZero() bool {
+	return q == Query{}
+}
+
+// Search returns the details matching q in its order. Ties, and Digimon
+// without a release date, which come last either way, are ordered by ID.
+func Search(details []models.DigimonDetail, q Query) []Entry {
+	name := strings.ToLower(strings.TrimSpace(q.Name))
+
+	var entries []Entry
+	for _, digimon := range details {
+		if name != "" && !strings.Contains(strings.ToLower(digimon.Name), name) {
+			continue
+		}
+		released, _ := releasedate.Parse(digimon.ReleaseDate)
+		if !q.Range.Contains(released) {
+			continue
+		}
+		entries = append(entries, Entry{Digimon: digimon, Released: released})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		var c int
+		switch q.Order {
+		case ByName:
+			c = strings.Compare(strings.ToLower(a.Digimon.Name), strings.ToLower(b.Digimon.Name))
+		case ByRelease:
+			if a.Released.IsZero() != b.Released.IsZero() {
+				return b.Released.IsZero()
+			}
+			c = a.Released.Compare(b.Released)
+		}
+		if q.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return a.Digimon.ID < b.Digimon.ID
+	})
+	return entries
+}
+
+// Year is the Digimon released in a year, 0 for an unknown year.
+type Year struct {
+	Year    int
+	Entries []Entry
+}
+
+// Label is the year, or "Unknown".
+func (y Year) Label() string {
+	if y.Year == 0 {
+		return "Unknown"
+	}
+	return fmt.Sprint(y.Year)
+}
+
+// Group collects entries by release year, keeping the order of the years
+// and of the Digimon within them as they come in entries.
+func Group(entries []Entry) []Year {
+	var years []Year
+	index := make(map[int]int)
+	for _, entry := range entries {
+		i, ok := index[entry.Released.Year]
+		if !ok {
+			i = len(years)
+			index[entry.Released.Year] = i
+			years = append(years, Year{Year: entry.Released.Year})
+		}
+		years[i].Entries = append(years[i].Entries, entry)
+	}
+	return years
+}
+
+// Page returns the zero-based page of entries as a list response shaped
+// like the API's, so local results can stand in for /digimon. The previous
+// and next links are only markers that another page exists.
+func Page(entries []Entry, page, pageSize int) *models.DigimonResponse {
+	pageSize = max(pageSize, 1)
+	start := min(max(page, 0)*pageSize, len(entries))
+	end := min(start+pageSize, len(entries))
+
+	resp := &models.DigimonResponse{Content: make([]models.Digimon, 0, end-start)}
+	for _, entry := range entries[start:end] {
+		summary := models.Digimon{ID: entry.Digimon.ID, Name: entry.Digimon.Name}
+		if len(entry.Digimon.Images) > 0 {
+			summary.Image = entry.Digimon.Images[0].Href
+		}
+		resp.Content = append(resp.Content, summary)
+	}
+
+	resp.Pageable.CurrentPage = page
+	resp.Pageable.ElementsOnPage = len(resp.Content)
+	resp.Pageable.TotalElements = len(entries)
+	resp.Pageable.TotalPages = (len(entries) + pageSize - 1) / pageSize
+	if page > 0 {
+		resp.Pageable.PreviousPage = fmt.Sprintf("local?page=%d", page-1)
+	}
+	if end < len(entries) {
+		resp.Pageable.NextPage = fmt.Sprintf("local?page=%d", page+1)
+	}
+	return resp
+}
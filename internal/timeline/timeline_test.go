@@ -0,0 +1,92 @@
+package timeline
+
+import (
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/fixtures"
+	"github.com/sangnt1552314/digimontex/internal/releasedate"
+)
+
+// released are the fixture Digimon with full, partial and missing release
+// dates.
+var released = []string{"Koromon", "Agumon", "Agumon (X-Antibody)", "Garurumon", "Nomon"}
+
+func ids(entries []Entry) []int {
+	var result []int
+	for _, entry := range entries {
+		result = append(result, entry.Digimon.ID)
+	}
+	return result
+}
+
+func TestSearch(t *testing.T) {
+	details := fixtures.Details(t, released...)
+	late, _ := releasedate.ParseRange("1998..")
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []int
+	}{
+		{"id order", Query{}, []int{1, 3, 9, 16, 90}},
+		{"name", Query{Name: "agu", Order: ByName}, []int{3, 16}},
+		{"release", Query{Order: ByRelease}, []int{1, 3, 9, 16, 90}},
+		{"release descending", Query{Order: ByRelease, Descending: true}, []int{16, 9, 3, 1, 90}},
+		{"range", Query{Range: late, Order: ByRelease}, []int{9, 16}},
+	}
+	for _, tt := range tests {
+		got := ids(Search(details, tt.query))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	years := Group(Search(fixtures.Details(t, released...), Query{Order: ByRelease}))
+
+	labels := []string{"1997", "1999", "2005", "Unknown"}
+	if len(years) != len(labels) {
+		t.Fatalf("got %d years, want %d", len(years), len(labels))
+	}
+	for i, year := range years {
+		if year.Label() != labels[i] {
+			t.Errorf("year %d = %s, want %s", i, year.Label(), labels[i])
+		}
+	}
+	if len(years[0].Entries) != 2 {
+		t.Errorf("1997 has %d Digimon, want 2", len(years[0].Entries))
+	}
+}
+
+func TestPage(t *testing.T) {
+	entries := Search(fixtures.Details(t, released...), Query{})
+
+	first := Page(entries, 0, 2)
+	if len(first.Content) != 2 || first.Pageable.TotalPages != 3 || first.Pageable.PreviousPage != "" || first.Pageable.NextPage == "" {
+		t.Errorf("first page = %+v", first)
+	}
+	last := Page(entries, 2, 2)
+	if len(last.Content) != 1 || last.Content[0].ID != 90 || last.Pageable.NextPage != "" || last.Pageable.PreviousPage == "" {
+		t.Errorf("last page = %+v", last)
+	}
+	if beyond := Page(entries, 5, 2); len(beyond.Content) != 0 {
+		t.Errorf("page past the end = %+v", beyond)
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	if order, err := ParseOrder("Release"); err != nil || order != ByRelease {
+		t.Errorf("ParseOrder(Release) = %v, %v", order, err)
+	}
+	if _, err := ParseOrder("size"); err == nil {
+		t.Error("ParseOrder(size) accepted an unknown order")
+	}
+}
@@ -0,0 +1,211 @@
+package viewmodel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+	"github.com/sangnt1552314/digimontex/internal/releasedate"
+)
+
+const (
+	unknownText       = "Unknown"
+	noDescriptionText = "No description available"
+	noEnglishText     = "No description available in English"
+	noSkillsText      = "No skills available"
+)
+
+// DigimonView is a DigimonDetail normalised for display. Missing or empty
+// values from the API are dropped or replaced, so renderers never need to
+// guard against nil slices or blank entries.
+type DigimonView struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	XAntibody   bool   `json:"xAntibody"`
+	ImageURL    string `json:"imageUrl"`
+	ReleaseDate string `json:"releaseDate"`
+	// Released is the parsed release date, zero when it is unknown
+	Released        releasedate.Date `json:"-"`
+	Levels          []string         `json:"levels"`
+	Types           []string         `json:"types"`
+	Attributes      []string         `json:"attributes"`
+	Fields          []FieldView      `json:"fields"`
+	Description     string           `json:"description"`
+	Skills          []SkillView      `json:"skills"`
+	PriorEvolutions []EvolutionView  `json:"priorEvolutions"`
+	NextEvolutions  []EvolutionView  `json:"nextEvolutions"`
+}
+
+type FieldView struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ImageURL string `json:"imageUrl"`
+}
+
+type SkillView struct {
+	Name        string `json:"name"`
+	Translation string `json:"translation"`
+	Description string `json:"description"`
+}
+
+type EvolutionView struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Condition string `json:"condition"`
+	ImageURL  string `json:"imageUrl"`
+}
+
+// NewDigimonView builds the view of d. A nil detail yields an empty view with
+// the same placeholders as a detail without any data.
+func NewDigimonView(d *models.DigimonDetail) DigimonView {
+	if d == nil {
+		d = &models.DigimonDetail{}
+	}
+
+	view := DigimonView{
+		ID:          d.ID,
+		Name:        orUnknown(d.Name),
+		XAntibody:   d.XAntibody,
+		ReleaseDate: orUnknown(d.ReleaseDate),
+		Description: description(d),
+	}
+	if released, err := releasedate.Parse(d.ReleaseDate); err == nil {
+		view.Released = released
+		view.ReleaseDate = released.Text()
+	}
+
+	for _, img := range d.Images {
+		if href := strings.TrimSpace(img.Href); href != "" {
+			view.ImageURL = href
+			break
+		}
+	}
+
+	for _, level := range d.Levels {
+		view.Levels = appendNonEmpty(view.Levels, level.Level)
+	}
+	for _, t := range d.Types {
+		view.Types = appendNonEmpty(view.Types, t.Type)
+	}
+	for _, attribute := range d.Attributes {
+		view.Attributes = appendNonEmpty(view.Attributes, attribute.Attribute)
+	}
+
+	for _, field := range d.Fields {
+		name := strings.TrimSpace(field.Field)
+		image := strings.TrimSpace(field.Image)
+		if name == "" && image == "" {
+			continue
+		}
+		view.Fields = append(view.Fields, FieldView{ID: field.ID, Name: orUnknown(name), ImageURL: image})
+	}
+
+	for _, skill := range d.Skills {
+		name := strings.TrimSpace(skill.Skill)
+		if name == "" {
+			continue
+		}
+		view.Skills = append(view.Skills, SkillView{
+			Name:        name,
+			Translation: strings.TrimSpace(skill.Translation),
+			Description: strings.TrimSpace(skill.Description),
+		})
+	}
+
+	for _, evolution := range d.PriorEvolutions {
+		view.PriorEvolutions = appendEvolution(view.PriorEvolutions, evolution.ID, evolution.Digimon, evolution.Condition, evolution.Image)
+	}
+	for _, evolution := range d.NextEvolutions {
+		view.NextEvolutions = appendEvolution(view.NextEvolutions, evolution.ID, evolution.Digimon, evolution.Condition, evolution.Image)
+	}
+
+	return view
+}
+
+func (v DigimonView) LevelsText() string {
+	return joinOrUnknown(v.Levels)
+}
+
+func (v DigimonView) TypesText() string {
+	return joinOrUnknown(v.Types)
+}
+
+func (v DigimonView) AttributesText() string {
+	return joinOrUnknown(v.Attributes)
+}
+
+func (v DigimonView) FieldsText() string {
+	names := make([]string, 0, len(v.Fields))
+	for _, field := range v.Fields {
+		names = append(names, field.Name)
+	}
+	return joinOrUnknown(names)
+}
+
+// SkillsText renders the skills as a bullet list, one skill per line.
+func (v DigimonView) SkillsText() string {
+	if len(v.Skills) == 0 {
+		return noSkillsText
+	}
+
+	var b strings.Builder
+	for _, skill := range v.Skills {
+		if skill.Description == "" {
+			fmt.Fprintf(&b, "- %s\n", skill.Name)
+		} else {
+			fmt.Fprintf(&b, "- %s: %s\n", skill.Name, skill.Description)
+		}
+	}
+	return b.String()
+}
+
+// description prefers the English description and explains why there is
+// none otherwise.
+func description(d *models.DigimonDetail) string {
+	if len(d.Descriptions) == 0 {
+		return noDescriptionText
+	}
+	for _, item := range d.Descriptions {
+		if item.Language == "en_us" {
+			if text := strings.TrimSpace(item.Description); text != "" {
+				return text
+			}
+			return noDescriptionText
+		}
+	}
+	return noEnglishText
+}
+
+func appendEvolution(evolutions []EvolutionView, id int, name, condition, image string) []EvolutionView {
+	name = strings.TrimSpace(name)
+	if name == "" && id == 0 {
+		return evolutions
+	}
+	return append(evolutions, EvolutionView{
+		ID:        id,
+		Name:      orUnknown(name),
+		Condition: strings.TrimSpace(condition),
+		ImageURL:  strings.TrimSpace(image),
+	})
+}
+
+func appendNonEmpty(values []string, value string) []string {
+	if value = strings.TrimSpace(value); value != "" {
+		return append(values, value)
+	}
+	return values
+}
+
+func joinOrUnknown(values []string) string {
+	if len(values) == 0 {
+		return unknownText
+	}
+	return strings.Join(values, ", ")
+}
+
+func orUnknown(value string) string {
+	if value = strings.TrimSpace(value); value != "" {
+		return value
+	}
+	return unknownText
+}
@@ -0,0 +1,183 @@
+package viewmodel
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+func decodeDetail(t *testing.T, payload string) *models.DigimonDetail {
+	t.Helper()
+
+	var detail models.DigimonDetail
+	if err := json.Unmarshal([]byte(payload), &detail); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	return &detail
+}
+
+func TestNewDigimonView(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		check   func(t *testing.T, view DigimonView)
+	}{
+		{
+			name:    "empty object",
+			payload: `{}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.Name != "Unknown" || view.ReleaseDate != "Unknown" {
+					t.Errorf("name/release date = %q/%q, want placeholders", view.Name, view.ReleaseDate)
+				}
+				if view.ImageURL != "" {
+					t.Errorf("ImageURL = %q, want empty", view.ImageURL)
+				}
+				if view.LevelsText() != "Unknown" || view.TypesText() != "Unknown" || view.AttributesText() != "Unknown" {
+					t.Errorf("unexpected list texts: %q %q %q", view.LevelsText(), view.TypesText(), view.AttributesText())
+				}
+				if view.Description != "No description available" {
+					t.Errorf("Description = %q", view.Description)
+				}
+				if view.SkillsText() != "No skills available" {
+					t.Errorf("SkillsText = %q", view.SkillsText())
+				}
+			},
+		},
+		{
+			name:    "null collections",
+			payload: `{"id": 1, "name": "Agumon", "images": null, "levels": null, "fields": null, "skills": null, "descriptions": null}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.ImageURL != "" || len(view.Levels) != 0 || len(view.Fields) != 0 || len(view.Skills) != 0 {
+					t.Errorf("expected empty collections, got %+v", view)
+				}
+			},
+		},
+		{
+			name:    "blank image entries are skipped",
+			payload: `{"images": [{"href": ""}, {"href": "  "}, {"href": "https://example.com/agumon.png"}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.ImageURL != "https://example.com/agumon.png" {
+					t.Errorf("ImageURL = %q", view.ImageURL)
+				}
+			},
+		},
+		{
+			name:    "blank levels types and attributes are dropped",
+			payload: `{"levels": [{"level": ""}, {"level": "Child"}], "types": [{"type": " "}], "attributes": [{"attribute": "Vaccine"}, {"attribute": ""}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if got := view.LevelsText(); got != "Child" {
+					t.Errorf("LevelsText = %q", got)
+				}
+				if got := view.TypesText(); got != "Unknown" {
+					t.Errorf("TypesText = %q", got)
+				}
+				if got := view.AttributesText(); got != "Vaccine" {
+					t.Errorf("AttributesText = %q", got)
+				}
+			},
+		},
+		{
+			name:    "partial release date",
+			payload: `{"releaseDate": "1999-03"}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.ReleaseDate != "March 1999" || view.Released.String() != "1999-03" {
+					t.Errorf("release date = %q (%v), want March 1999", view.ReleaseDate, view.Released)
+				}
+			},
+		},
+		{
+			name:    "unparsable release date",
+			payload: `{"releaseDate": "Unreleased"}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.ReleaseDate != "Unreleased" || !view.Released.IsZero() {
+					t.Errorf("release date = %q (%v), want it verbatim", view.ReleaseDate, view.Released)
+				}
+			},
+		},
+		{
+			name:    "fields without name or image",
+			payload: `{"fields": [{"field": "", "image": ""}, {"field": "", "image": "https://example.com/vb.png"}, {"field": "Nature Spirits"}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				want := []FieldView{
+					{Name: "Unknown", ImageURL: "https://example.com/vb.png"},
+					{Name: "Nature Spirits"},
+				}
+				if !reflect.DeepEqual(view.Fields, want) {
+					t.Errorf("Fields = %+v, want %+v", view.Fields, want)
+				}
+			},
+		},
+		{
+			name:    "skills without names are dropped",
+			payload: `{"skills": [{"skill": ""}, {"skill": "Pepper Breath", "description": "Fireball"}, {"skill": "Claw"}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if got, want := view.SkillsText(), "- Pepper Breath: Fireball\n- Claw\n"; got != want {
+					t.Errorf("SkillsText = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name:    "only blank skills",
+			payload: `{"skills": [{"skill": ""}, {"skill": " "}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if got := view.SkillsText(); got != "No skills available" {
+					t.Errorf("SkillsText = %q", got)
+				}
+			},
+		},
+		{
+			name:    "description without english",
+			payload: `{"descriptions": [{"language": "jap", "description": "..."}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.Description != "No description available in English" {
+					t.Errorf("Description = %q", view.Description)
+				}
+			},
+		},
+		{
+			name:    "english description after other languages",
+			payload: `{"descriptions": [{"language": "jap", "description": "..."}, {"language": "en_us", "description": "A reptile Digimon."}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.Description != "A reptile Digimon." {
+					t.Errorf("Description = %q", view.Description)
+				}
+			},
+		},
+		{
+			name:    "empty english description",
+			payload: `{"descriptions": [{"language": "en_us", "description": ""}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if view.Description != "No description available" {
+					t.Errorf("Description = %q", view.Description)
+				}
+			},
+		},
+		{
+			name:    "evolutions without id or name are dropped",
+			payload: `{"nextEvolutions": [{"id": 0, "digimon": ""}, {"id": 2, "digimon": "Greymon", "condition": " "}], "priorEvolutions": [{"id": 3}]}`,
+			check: func(t *testing.T, view DigimonView) {
+				if want := []EvolutionView{{ID: 2, Name: "Greymon"}}; !reflect.DeepEqual(view.NextEvolutions, want) {
+					t.Errorf("NextEvolutions = %+v, want %+v", view.NextEvolutions, want)
+				}
+				if want := []EvolutionView{{ID: 3, Name: "Unknown"}}; !reflect.DeepEqual(view.PriorEvolutions, want) {
+					t.Errorf("PriorEvolutions = %+v, want %+v", view.PriorEvolutions, want)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, NewDigimonView(decodeDetail(t, tt.payload)))
+		})
+	}
+}
+
+func TestNewDigimonViewNil(t *testing.T) {
+	view := NewDigimonView(nil)
+	if view.Name != "Unknown" || view.SkillsText() != "No skills available" {
+		t.Errorf("unexpected view for nil detail: %+v", view)
+	}
+}
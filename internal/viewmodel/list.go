@@ -0,0 +1,59 @@
+package viewmodel
+
+import (
+	"strings"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+// ListView is one page of the Digimon list. It is safe to build from a nil
+// response, which yields an empty page.
+type ListView struct {
+	Items       []ListItem `json:"items"`
+	CurrentPage int        `json:"currentPage"`
+	TotalPages  int        `json:"totalPages"`
+	TotalItems  int        `json:"totalItems"`
+	HasPrevious bool       `json:"hasPrevious"`
+	HasNext     bool       `json:"hasNext"`
+}
+
+type ListItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func NewListView(resp *models.DigimonResponse) ListView {
+	if resp == nil {
+		return ListView{}
+	}
+
+	view := ListView{
+		CurrentPage: max(resp.Pageable.CurrentPage, 0),
+		TotalPages:  max(resp.Pageable.TotalPages, 0),
+		TotalItems:  max(resp.Pageable.TotalElements, 0),
+		HasPrevious: resp.Pageable.PreviousPage != "",
+		HasNext:     resp.Pageable.NextPage != "",
+	}
+
+	for _, digimon := range resp.Content {
+		// Entries without an ID cannot be opened
+		if digimon.ID <= 0 {
+			continue
+		}
+		view.Items = append(view.Items, ListItem{
+			ID:   digimon.ID,
+			Name: orUnknown(strings.TrimSpace(digimon.Name)),
+		})
+	}
+
+	return view
+}
+
+// IDs returns the IDs of the items in list order.
+func (v ListView) IDs() []int {
+	ids := make([]int, 0, len(v.Items))
+	for _, item := range v.Items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
@@ -0,0 +1,70 @@
+package viewmodel
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sangnt1552314/digimontex/internal/models"
+)
+
+func TestNewListView(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    ListView
+	}{
+		{
+			name:    "empty object",
+			payload: `{}`,
+			want:    ListView{},
+		},
+		{
+			name:    "null content",
+			payload: `{"content": null, "pageable": {"totalPages": 0}}`,
+			want:    ListView{},
+		},
+		{
+			name:    "items without id or name",
+			payload: `{"content": [{"id": 0, "name": "Ghost"}, {"id": 7, "name": ""}, {"id": 1, "name": "Agumon"}]}`,
+			want: ListView{
+				Items: []ListItem{{ID: 7, Name: "Unknown"}, {ID: 1, Name: "Agumon"}},
+			},
+		},
+		{
+			name:    "pagination",
+			payload: `{"content": [{"id": 11, "name": "Gabumon"}], "pageable": {"currentPage": 1, "totalPages": 3, "totalElements": 25, "previousPage": "p", "nextPage": "n"}}`,
+			want: ListView{
+				Items:       []ListItem{{ID: 11, Name: "Gabumon"}},
+				CurrentPage: 1,
+				TotalPages:  3,
+				TotalItems:  25,
+				HasPrevious: true,
+				HasNext:     true,
+			},
+		},
+		{
+			name:    "negative counts",
+			payload: `{"pageable": {"currentPage": -1, "totalPages": -5, "totalElements": -2}}`,
+			want:    ListView{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp models.DigimonResponse
+			if err := json.Unmarshal([]byte(tt.payload), &resp); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+			if got := NewListView(&resp); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewListView() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewListViewNil(t *testing.T) {
+	if got := NewListView(nil); !reflect.DeepEqual(got, ListView{}) {
+		t.Errorf("NewListView(nil) = %+v, want empty view", got)
+	}
+}